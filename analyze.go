@@ -0,0 +1,177 @@
+package dynamodb
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// ScanReportBuckets are the upper bounds in bytes of the ScanReport histogram.
+// 1KB is one write unit and 4KB is one read unit, larger buckets track the 400KB item limit.
+var ScanReportBuckets = []int64{1 << 10, 4 << 10, 16 << 10, 64 << 10, 128 << 10, 256 << 10, 400 << 10}
+
+// ScanReport summarizes item sizes and attribute usage of a table.
+type ScanReport struct {
+	Table      string
+	Items      int64
+	TotalBytes int64
+	MaxBytes   int64
+	// Histogram[i] counts items up to ScanReportBuckets[i] bytes,
+	// the last element counts larger items.
+	Histogram []int64
+	// AttributeFrequency counts the items each attribute appears in.
+	AttributeFrequency map[string]int64
+	// Largest items, biggest first.
+	Largest []ItemSize
+}
+
+// ItemSize :
+type ItemSize struct {
+	Key   []*DynamodbAttributeValue
+	Bytes int64
+}
+
+// AverageBytes :
+func (r *ScanReport) AverageBytes() int64 {
+	if r.Items == 0 {
+		return 0
+	}
+	return r.TotalBytes / r.Items
+}
+
+// ScanAnalyze is only for script like Scan. Do not use from application.
+// It scans the table and reports item size distribution, attribute frequency
+// and the top largest items.
+func (con *dynamodb) ScanAnalyze(tableName string, top int, filters ...ScanFilter) (*ScanReport, error) {
+	table := con.table(tableName)
+	desc, err := table.Describe().Run()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ScanReport{
+		Table:              tableName,
+		Histogram:          make([]int64, len(ScanReportBuckets)+1),
+		AttributeFrequency: map[string]int64{},
+	}
+
+	ctx, cancel := con.scanContext(context.Background())
+	defer cancel()
+
+	iter := scan(table, filters...).Iter()
+	var item map[string]*awsDynamodb.AttributeValue
+	for iter.NextWithContext(ctx, &item) {
+		size := itemSize(item)
+
+		report.Items++
+		report.TotalBytes += size
+		if size > report.MaxBytes {
+			report.MaxBytes = size
+		}
+		report.Histogram[sort.Search(len(ScanReportBuckets), func(i int) bool { return size <= ScanReportBuckets[i] })]++
+
+		for name := range item {
+			report.AttributeFrequency[name]++
+		}
+
+		if top > 0 && (len(report.Largest) < top || size > report.Largest[len(report.Largest)-1].Bytes) {
+			key, err := itemKey(item, desc.HashKey, desc.RangeKey)
+			if err != nil {
+				return nil, err
+			}
+			report.Largest = append(report.Largest, ItemSize{Key: key, Bytes: size})
+			sort.SliceStable(report.Largest, func(i, j int) bool { return report.Largest[i].Bytes > report.Largest[j].Bytes })
+			if len(report.Largest) > top {
+				report.Largest = report.Largest[:top]
+			}
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, scanError(ctx, err)
+	}
+
+	return report, nil
+}
+
+func itemKey(item map[string]*awsDynamodb.AttributeValue, names ...string) ([]*DynamodbAttributeValue, error) {
+	key := []*DynamodbAttributeValue{}
+	for _, name := range names {
+		av, ok := item[name]
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		if err := dynamo.Unmarshal(av, &value); err != nil {
+			return nil, err
+		}
+		key = append(key, &DynamodbAttributeValue{Key: name, Value: value})
+	}
+	return key, nil
+}
+
+// itemSize approximates the stored size of an item as DynamoDB bills it.
+func itemSize(item map[string]*awsDynamodb.AttributeValue) int64 {
+	var size int64
+	for name, av := range item {
+		size += int64(len(name)) + attributeSize(av)
+	}
+	return size
+}
+
+func attributeSize(av *awsDynamodb.AttributeValue) int64 {
+	switch {
+	case av == nil:
+		return 0
+	case av.S != nil:
+		return int64(len(*av.S))
+	case av.N != nil:
+		return numberSize(*av.N)
+	case av.B != nil:
+		return int64(len(av.B))
+	case av.BOOL != nil, av.NULL != nil:
+		return 1
+	case av.SS != nil:
+		var size int64
+		for _, s := range av.SS {
+			size += int64(len(*s))
+		}
+		return size
+	case av.NS != nil:
+		var size int64
+		for _, n := range av.NS {
+			size += numberSize(*n)
+		}
+		return size
+	case av.BS != nil:
+		var size int64
+		for _, b := range av.BS {
+			size += int64(len(b))
+		}
+		return size
+	case av.L != nil:
+		size := int64(3)
+		for _, v := range av.L {
+			size += 1 + attributeSize(v)
+		}
+		return size
+	case av.M != nil:
+		size := int64(3)
+		for name, v := range av.M {
+			size += 1 + int64(len(name)) + attributeSize(v)
+		}
+		return size
+	}
+	return 0
+}
+
+// numberSize is one byte per two significant digits plus one.
+func numberSize(n string) int64 {
+	digits := strings.Trim(strings.TrimLeft(n, "-+"), "0.")
+	digits = strings.Replace(digits, ".", "", 1)
+	return int64(len(digits)+1)/2 + 1
+}
@@ -0,0 +1,45 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanAnalyze(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := faker.UUIDDigit()
+	for i := 0; 3 > i; i++ {
+		var d HashOnly
+		faker.FakeData(&d)
+		d.Name = name
+		dynamo.Put(tableNameHashOnly, &d)
+	}
+
+	report, err := dynamo.ScanAnalyze(tableNameHashOnly, 2, ScanFilter{Expr: "'Name' = ?", Value: name})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), report.Items)
+	assert.Equal(t, int64(3), report.Histogram[0])
+	assert.Equal(t, int64(3), report.AttributeFrequency["Name"])
+	assert.Len(t, report.Largest, 2)
+	assert.Equal(t, "ID", report.Largest[0].Key[0].Key)
+	assert.GreaterOrEqual(t, report.Largest[0].Bytes, report.Largest[1].Bytes)
+}
+
+func TestItemSize(t *testing.T) {
+	item := map[string]*awsDynamodb.AttributeValue{
+		"ID":     {S: aws.String("abc")},
+		"Status": {N: aws.String("12345")},
+		"Tags": {L: []*awsDynamodb.AttributeValue{
+			{BOOL: aws.Bool(true)},
+		}},
+	}
+
+	// ID(2)+abc(3) + Status(6)+12345(4) + Tags(4)+list(3+1+1)
+	assert.Equal(t, int64(24), itemSize(item))
+}
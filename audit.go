@@ -0,0 +1,69 @@
+package dynamodb
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+)
+
+// AuditMode detects misuse of the client at runtime. It has a cost and is meant for development.
+type AuditMode int
+
+// Audit modes.
+const (
+	AuditOff AuditMode = iota
+	// AuditLog reports misuse through DynamodbConfig.Logger, or the standard logger.
+	AuditLog
+	// AuditPanic panics on misuse.
+	AuditPanic
+)
+
+func (con *dynamodb) audit(format string, args ...interface{}) {
+	msg := "dynamodb audit: " + fmt.Sprintf(format, args...)
+	switch con.config.Audit {
+	case AuditPanic:
+		panic(msg)
+	case AuditLog:
+		if con.config.Logger != nil {
+			con.config.Logger.Debug(msg)
+		} else {
+			log.Print(msg)
+		}
+	}
+}
+
+// auditResult checks that result is a pointer no other call is writing to.
+// The returned func must be called when the call is done with result.
+func (con *dynamodb) auditResult(method string, result interface{}) (done func()) {
+	done = func() {}
+	if con.config.Audit == AuditOff {
+		return done
+	}
+
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		con.audit("%s: result must be a non-nil pointer, got %T", method, result)
+		return done
+	}
+
+	ptr := rv.Pointer()
+	if other, busy := con.inflight.LoadOrStore(ptr, method); busy {
+		con.audit("%s: result %T is being written by a concurrent %s; use one result per call", method, result, other)
+		return done
+	}
+	return func() { con.inflight.Delete(ptr) }
+}
+
+// auditKeys reports duplicate keys, which usually come from key closures
+// capturing a loop variable shared by all iterations.
+func (con *dynamodb) auditKeys(method string, keys, uniqKeys int) {
+	if con.config.Audit == AuditOff || keys == uniqKeys {
+		return
+	}
+
+	if uniqKeys == 1 {
+		con.audit("%s: all %d keys are equal; do the key closures capture a loop variable?", method, keys)
+		return
+	}
+	con.audit("%s: %d of %d keys are duplicates", method, keys-uniqKeys, keys)
+}
@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	db := newDynamoWithConfig(t, &DynamodbConfig{Audit: AuditPanic})
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), faker.UUIDDigit() },
+	}
+
+	t.Run("Non-pointer result", func(t *testing.T) {
+		var datum HashOnly
+		assert.Panics(t, func() { db.Get(tableNameHashOnly, key, datum) })
+	})
+
+	t.Run("Result used concurrently", func(t *testing.T) {
+		con := db.(*dynamodb)
+
+		var datum HashOnly
+		done := con.auditResult("Get", &datum)
+		assert.Panics(t, func() { db.Get(tableNameHashOnly, key, &datum) })
+		done()
+
+		assert.NotPanics(t, func() { db.Get(tableNameHashOnly, key, &datum) })
+	})
+
+	t.Run("Keys capturing a loop variable", func(t *testing.T) {
+		ids := []string{faker.UUIDDigit(), faker.UUIDDigit(), faker.UUIDDigit()}
+
+		keys := []*DynamodbKey{}
+		var id string
+		for _, id = range ids {
+			keys = append(keys, &DynamodbKey{
+				Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), id },
+			})
+		}
+
+		var data []HashOnly
+		assert.Panics(t, func() { db.BatchGet(tableNameHashOnly, keys, &data) })
+	})
+
+	t.Run("Off", func(t *testing.T) {
+		var datum HashOnly
+		assert.NotPanics(t, func() { newDynamo(t).Get(tableNameHashOnly, key, datum) })
+	})
+}
@@ -0,0 +1,262 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// maxBatchGet is the number of keys DynamoDB accepts in one BatchGetItem.
+const maxBatchGet = 100
+
+// ErrUnprocessed is wrapped by UnprocessedError.
+var ErrUnprocessed = errors.New("unprocessed keys")
+
+// UnprocessedError is returned by a batch read whose Keys were still unprocessed after every retry
+// of the RetryPolicy. The items of the other keys are unmarshaled into the results.
+type UnprocessedError struct {
+	Keys []*DynamodbKey
+	// Tables holds the unprocessed keys by table, for MultiTableBatchGet.
+	Tables map[string][]*DynamodbKey
+}
+
+func (e *UnprocessedError) Error() string {
+	return fmt.Sprintf("%v: %d keys", ErrUnprocessed, len(e.Keys))
+}
+
+func (e *UnprocessedError) Unwrap() error {
+	return ErrUnprocessed
+}
+
+func unprocessedError(unprocessed map[string][]*DynamodbKey) error {
+	if len(unprocessed) < 1 {
+		return nil
+	}
+	err := &UnprocessedError{Tables: unprocessed}
+	for _, keys := range unprocessed {
+		err.Keys = append(err.Keys, keys...)
+	}
+	return err
+}
+
+// BatchGetSpec is the read of one table by MultiTableBatchGet.
+type BatchGetSpec struct {
+	Keys []*DynamodbKey
+	// Result is a pointer to a slice, to which the items are appended.
+	Result interface{}
+}
+
+// MultiTableBatchGet reads keys of several tables in shared BatchGetItem requests, unmarshaling the
+// items of every table into the Result of its spec. Tables without items are not an error, and specs
+// without keys are skipped. Duplicate keys of a table are read once.
+func (con *dynamodb) MultiTableBatchGet(ops map[string]BatchGetSpec) (*DynamodbReadResponse, error) {
+	requests := make(map[string][]*DynamodbKey, len(ops))
+	total, uniq := 0, 0
+	for tableName, spec := range ops {
+		if len(spec.Keys) < 1 {
+			continue
+		}
+		keys, err := uniqueKeys(tableName, spec.Keys)
+		if err != nil {
+			return &DynamodbReadResponse{}, err
+		}
+		requests[tableName] = keys
+		total, uniq = total+len(spec.Keys), uniq+len(keys)
+	}
+	if total < 1 {
+		return &DynamodbReadResponse{}, errors.New("key empty")
+	}
+	if err := con.guardBulk(total); err != nil {
+		return &DynamodbReadResponse{}, err
+	}
+	con.auditKeys("MultiTableBatchGet", total, uniq)
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	items, unprocessed, err := con.batchGet(ctx, requests, cc)
+	if err != nil {
+		return readResponse(ctx, cc), err
+	}
+	for tableName, spec := range ops {
+		if len(spec.Keys) < 1 {
+			continue
+		}
+		if err := con.decodeBatch(tableName, items[tableName], spec.Result); err != nil {
+			return readResponse(ctx, cc), err
+		}
+	}
+	return readResponse(ctx, cc), unprocessedError(unprocessed)
+}
+
+// uniqueKeys drops the duplicates of keys of tableName. Keys are compared marshaled,
+// as values such as []byte cannot be map keys.
+func uniqueKeys(tableName string, keys []*DynamodbKey) ([]*DynamodbKey, error) {
+	m := make(map[string]bool, len(keys))
+	uniqKeys := []*DynamodbKey{}
+	for _, key := range keys {
+		av, err := marshalKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if k := cacheKey(tableName, av); !m[k] {
+			m[k] = true
+			uniqKeys = append(uniqKeys, key)
+		}
+	}
+	return uniqKeys, nil
+}
+
+// batchKey is a key of a batch read, marshaled.
+type batchKey struct {
+	table string
+	av    map[string]*awsDynamodb.AttributeValue
+}
+
+// batchGet reads the keys of every table in requests of up to maxBatchGet keys, retrying unprocessed
+// keys with the backoff of the RetryPolicy. It returns the items read and the keys left unprocessed by table.
+func (con *dynamodb) batchGet(ctx context.Context, requests map[string][]*DynamodbKey, cc *dynamo.ConsumedCapacity) (map[string][]map[string]*awsDynamodb.AttributeValue, map[string][]*DynamodbKey, error) {
+	policy := RetryPolicy{}
+	if con.config.RetryPolicy != nil {
+		policy = *con.config.RetryPolicy
+	}
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = DefaultRetryMaxAttempts
+	}
+
+	names := make([]string, 0, len(requests))
+	for tableName := range requests {
+		names = append(names, tableName)
+	}
+	sort.Strings(names)
+
+	// tables maps the names sent to DynamoDB, which carry the table prefix, back to the table names.
+	tables := make(map[string]string, len(requests))
+	byKey := map[string]*DynamodbKey{}
+	batchKeys := []batchKey{}
+	for _, tableName := range names {
+		tables[con.tableName(tableName)] = tableName
+		for _, key := range requests[tableName] {
+			av, err := marshalKey(key)
+			if err != nil {
+				return nil, nil, err
+			}
+			batchKeys = append(batchKeys, batchKey{table: tableName, av: av})
+			byKey[cacheKey(tableName, av)] = key
+		}
+	}
+
+	items := map[string][]map[string]*awsDynamodb.AttributeValue{}
+	unprocessed := map[string][]*DynamodbKey{}
+	for start := 0; start < len(batchKeys); start += maxBatchGet {
+		end := start + maxBatchGet
+		if end > len(batchKeys) {
+			end = len(batchKeys)
+		}
+
+		pending := map[string]*awsDynamodb.KeysAndAttributes{}
+		for _, key := range batchKeys[start:end] {
+			name := con.tableName(key.table)
+			if pending[name] == nil {
+				pending[name] = &awsDynamodb.KeysAndAttributes{}
+			}
+			pending[name].Keys = append(pending[name].Keys, key.av)
+		}
+
+		for attempt := 0; attempt < attempts && len(pending) > 0; attempt++ {
+			if attempt > 0 {
+				if err := sleep(ctx, policy.Delay(attempt-1)); err != nil {
+					return items, nil, err
+				}
+			}
+
+			in := &awsDynamodb.BatchGetItemInput{RequestItems: pending}
+			if cc != nil {
+				in.ReturnConsumedCapacity = aws.String(awsDynamodb.ReturnConsumedCapacityIndexes)
+			}
+			out, err := con.db.Client().BatchGetItemWithContext(ctx, in)
+			if err != nil {
+				return items, nil, err
+			}
+
+			for _, raw := range out.ConsumedCapacity {
+				addCapacity(cc, raw)
+			}
+			for name, responses := range out.Responses {
+				items[tables[name]] = append(items[tables[name]], responses...)
+			}
+			pending = out.UnprocessedKeys
+		}
+
+		for name, rest := range pending {
+			tableName := tables[name]
+			for _, av := range rest.Keys {
+				if key, ok := byKey[cacheKey(tableName, av)]; ok {
+					unprocessed[tableName] = append(unprocessed[tableName], key)
+				}
+			}
+		}
+	}
+	return items, unprocessed, nil
+}
+
+func marshalKey(key *DynamodbKey) (map[string]*awsDynamodb.AttributeValue, error) {
+	hKey, hValue := key.Hash()
+	hash, err := dynamo.Marshal(hValue)
+	if err != nil {
+		return nil, err
+	}
+	av := map[string]*awsDynamodb.AttributeValue{hKey: hash}
+
+	if key.Range != nil {
+		rKey, rValue, _ := key.Range()
+		rng, err := dynamo.Marshal(rValue)
+		if err != nil {
+			return nil, err
+		}
+		av[rKey] = rng
+	}
+	return av, nil
+}
+
+// decodeBatch appends items to result, a pointer to a slice, like the batch reads of dynamo.
+func (con *dynamodb) decodeBatch(tableName string, items []map[string]*awsDynamodb.AttributeValue, result interface{}) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("result must be a pointer to a slice, got %T", result)
+	}
+	return con.decodeItems(tableName, items, result)
+}
+
+func addCapacity(cc *dynamo.ConsumedCapacity, raw *awsDynamodb.ConsumedCapacity) {
+	if cc == nil || raw == nil {
+		return
+	}
+	cc.Total += aws.Float64Value(raw.CapacityUnits)
+	cc.Read += aws.Float64Value(raw.ReadCapacityUnits)
+	cc.Write += aws.Float64Value(raw.WriteCapacityUnits)
+	if raw.Table != nil {
+		cc.Table += aws.Float64Value(raw.Table.CapacityUnits)
+	}
+	for name, c := range raw.GlobalSecondaryIndexes {
+		if cc.GSI == nil {
+			cc.GSI = map[string]float64{}
+		}
+		cc.GSI[name] += aws.Float64Value(c.CapacityUnits)
+	}
+	for name, c := range raw.LocalSecondaryIndexes {
+		if cc.LSI == nil {
+			cc.LSI = map[string]float64{}
+		}
+		cc.LSI[name] += aws.Float64Value(c.CapacityUnits)
+	}
+}
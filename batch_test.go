@@ -0,0 +1,159 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchGetUnprocessed(t *testing.T) {
+	putItems := func(t *testing.T, n int) []*DynamodbKey {
+		dynamo := newDynamo(t)
+		items := make([]HashOnly, n)
+		keys := make([]*DynamodbKey, n)
+		for i := range items {
+			id := faker.UUIDDigit()
+			items[i] = HashOnly{Id: id, Name: faker.Name()}
+			keys[i] = &DynamodbKey{
+				Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), id },
+			}
+		}
+		_, err := dynamo.BulkPut(context.Background(), tableNameHashOnly, items, BulkPutOptions{})
+		assert.NoError(t, err)
+		return keys
+	}
+
+	t.Run("Success: over 100 keys", func(t *testing.T) {
+		dynamo := newDynamo(t)
+		keys := putItems(t, 150)
+
+		var items []HashOnly
+		assert.NoError(t, dynamo.BatchGet(tableNameHashOnly, keys, &items))
+		assert.Len(t, items, len(keys))
+	})
+
+	t.Run("Success: retried", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Faults: &Faults{UnprocessedEvery: 1, Operations: []string{"BatchGetItem"}}})
+		keys := putItems(t, 4)
+
+		var items []HashOnly
+		assert.NoError(t, dynamo.BatchGet(tableNameHashOnly, keys, &items))
+		assert.Len(t, items, len(keys))
+	})
+
+	t.Run("Failure: unprocessed", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{
+			Faults:      &Faults{UnprocessedEvery: 1, Operations: []string{"BatchGetItem"}},
+			RetryPolicy: &RetryPolicy{MaxAttempts: 1},
+		})
+		keys := putItems(t, 4)
+
+		var items []HashOnly
+		err := dynamo.BatchGet(tableNameHashOnly, keys, &items)
+		assert.True(t, errors.Is(err, ErrUnprocessed))
+		assert.Len(t, items, 2)
+
+		var unprocessed *UnprocessedError
+		if assert.True(t, errors.As(err, &unprocessed)) {
+			assert.Equal(t, keys[2:], unprocessed.Keys)
+		}
+	})
+}
+
+func TestMultiTableBatchGet(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	user := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	_, err := dynamo.Put(tableNameHashOnly, user)
+	assert.NoError(t, err)
+	post := HashAndRange{Id: faker.UUIDDigit(), CreatedAt: faker.Timestamp(), Name: faker.Name()}
+	_, err = dynamo.Put(tableNameHashAndRange, post)
+	assert.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		var users []HashOnly
+		var posts []HashAndRange
+		_, err := dynamo.MultiTableBatchGet(map[string]BatchGetSpec{
+			tableNameHashOnly: {
+				Keys: []*DynamodbKey{{
+					Hash: func() (string, interface{}) { return user.HashKey(), user.Id },
+				}},
+				Result: &users,
+			},
+			tableNameHashAndRange: {
+				Keys: []*DynamodbKey{{
+					Hash:  func() (string, interface{}) { return post.HashKey(), post.Id },
+					Range: func() (string, interface{}, *DynamodbOptions) { return post.RangeKey(), post.CreatedAt, nil },
+				}},
+				Result: &posts,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []HashOnly{user}, users)
+		assert.Equal(t, []HashAndRange{post}, posts)
+	})
+
+	t.Run("Success: not found", func(t *testing.T) {
+		var users []HashOnly
+		_, err := dynamo.MultiTableBatchGet(map[string]BatchGetSpec{
+			tableNameHashOnly: {
+				Keys: []*DynamodbKey{{
+					Hash: func() (string, interface{}) { return user.HashKey(), faker.UUIDDigit() },
+				}},
+				Result: &users,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("Success: duplicate keys and empty specs", func(t *testing.T) {
+		key := &DynamodbKey{
+			Hash: func() (string, interface{}) { return user.HashKey(), user.Id },
+		}
+		var users []HashOnly
+		_, err := dynamo.MultiTableBatchGet(map[string]BatchGetSpec{
+			tableNameHashOnly:     {Keys: []*DynamodbKey{key, key}, Result: &users},
+			tableNameHashAndRange: {},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []HashOnly{user}, users)
+	})
+
+	t.Run("Failure: key empty", func(t *testing.T) {
+		_, err := dynamo.MultiTableBatchGet(map[string]BatchGetSpec{})
+		assert.Error(t, err)
+	})
+}
+
+type BinaryKeyed struct {
+	Id   []byte `dynamo:"ID,hash"`
+	Name string `dynamo:"Name"`
+}
+
+func TestBatchGetBinaryKeys(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := "binary-" + faker.UUIDDigit()
+	if !assert.NoError(t, dynamo.CreateTableWithOptions(name, BinaryKeyed{}, CreateTableOptions{OnDemand: true, Wait: true})) {
+		t.FailNow()
+	}
+	defer func() {
+		dynamo.DeleteTable(name)
+		dynamo.WaitUntilTableDeleted(context.Background(), name)
+	}()
+
+	item := BinaryKeyed{Id: []byte(faker.UUIDDigit()), Name: faker.Name()}
+	_, err := dynamo.Put(name, item)
+	assert.NoError(t, err)
+
+	key := &DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", item.Id },
+	}
+	var items []BinaryKeyed
+	assert.NoError(t, dynamo.BatchGet(name, []*DynamodbKey{key, key}, &items))
+	assert.Equal(t, []BinaryKeyed{item}, items)
+}
@@ -0,0 +1,58 @@
+package dynamodb
+
+import (
+	"errors"
+
+	"github.com/guregu/dynamo"
+)
+
+// UpdateTableThroughput changes the provisioned units of a provisioned table, leaving its indexes as they are.
+// The table stays usable while it is UPDATING; see WaitUntilTableActive.
+func (con *dynamodb) UpdateTableThroughput(name string, read, write int64) error {
+	if read < 1 || write < 1 {
+		return errors.New("read and write units must be positive")
+	}
+
+	return con.updateBilling(name, func(req *dynamo.UpdateTable) {
+		req.Provision(read, write)
+	})
+}
+
+// SwitchToOnDemand changes the table and its indexes to on-demand billing.
+// DynamoDB allows one switch to on-demand per table every 24 hours.
+func (con *dynamodb) SwitchToOnDemand(name string) error {
+	return con.updateBilling(name, func(req *dynamo.UpdateTable) {
+		req.OnDemand(true)
+	})
+}
+
+// SwitchToProvisioned changes the table to provisioned billing with read and write units,
+// which every global secondary index gets too.
+func (con *dynamodb) SwitchToProvisioned(name string, read, write int64) error {
+	if read < 1 || write < 1 {
+		return errors.New("read and write units must be positive")
+	}
+
+	desc, err := con.table(name).Describe().Run()
+	if err != nil {
+		return err
+	}
+
+	return con.updateBilling(name, func(req *dynamo.UpdateTable) {
+		req.OnDemand(false).Provision(read, write)
+		for _, index := range desc.GSI {
+			req.ProvisionIndex(index.Name, read, write)
+		}
+	})
+}
+
+func (con *dynamodb) updateBilling(name string, update func(req *dynamo.UpdateTable)) error {
+	req := con.table(name).UpdateTable()
+	update(req)
+	if _, err := req.Run(); err != nil {
+		return err
+	}
+
+	con.emit(TableEvent{Type: TableUpdated, Table: name})
+	return nil
+}
@@ -0,0 +1,59 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBilling(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "billing-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, WithIndex{}, CreateTableOptions{
+		GSIs: []IndexDefinition{
+			{Name: "Status-index", HashKey: "Status", HashKeyType: DynamodbKeyTypeNumber},
+		},
+		Wait: true,
+	}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	t.Run("Success: throughput", func(t *testing.T) {
+		assert.NoError(t, dynamo.UpdateTableThroughput(name, 5, 3))
+		assert.NoError(t, dynamo.WaitUntilTableActive(ctx, name))
+
+		desc, err := dynamo.DescribeTable(name)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), desc.ReadUnits)
+		assert.Equal(t, int64(3), desc.WriteUnits)
+	})
+
+	t.Run("Success: on demand", func(t *testing.T) {
+		assert.NoError(t, dynamo.SwitchToOnDemand(name))
+		assert.NoError(t, dynamo.WaitUntilTableActive(ctx, name))
+
+		desc, err := dynamo.DescribeTable(name)
+		assert.NoError(t, err)
+		assert.True(t, desc.OnDemand)
+	})
+
+	t.Run("Success: provisioned", func(t *testing.T) {
+		assert.NoError(t, dynamo.SwitchToProvisioned(name, 2, 2))
+		assert.NoError(t, dynamo.WaitUntilTableActive(ctx, name))
+
+		desc, err := dynamo.DescribeTable(name)
+		assert.NoError(t, err)
+		assert.False(t, desc.OnDemand)
+		assert.Equal(t, int64(2), desc.ReadUnits)
+		if assert.Len(t, desc.GSIs, 1) {
+			assert.Equal(t, int64(2), desc.GSIs[0].ReadUnits)
+		}
+	})
+
+	t.Run("Failure: units", func(t *testing.T) {
+		assert.Error(t, dynamo.UpdateTableThroughput(name, 0, 1))
+	})
+}
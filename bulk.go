@@ -0,0 +1,211 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/guregu/dynamo"
+)
+
+// maxBatchWrite is the number of items DynamoDB accepts per BatchWriteItem.
+const maxBatchWrite = 25
+
+// Defaults used for unset BulkPutOptions and WarmUp fields.
+const (
+	DefaultBulkConcurrency = 4
+	DefaultWarmUpInitial   = 1
+	DefaultWarmUpMax       = 16
+	DefaultWarmUpInterval  = 20 * time.Second
+)
+
+// WarmUp ramps write concurrency up gradually. A new on-demand table starts with a ceiling
+// of around 4,000 write units per second and scales as traffic grows, so a full speed load
+// would spend minutes in throttle retries.
+type WarmUp struct {
+	// Initial is the number of writers to start with.
+	Initial int
+	// Max is the number of writers to ramp up to.
+	Max int
+	// Interval is the time between doublings of the writers.
+	Interval time.Duration
+}
+
+// BulkPutOptions :
+type BulkPutOptions struct {
+	// Concurrency is the number of parallel batch writers when WarmUp is unset.
+	Concurrency int
+	WarmUp      *WarmUp
+}
+
+// BulkPut writes items, a slice, in batches of 25 and returns the number of items written.
+// Items of one batch are written in no particular order and their old values are not checked.
+// With DynamodbConfig.DeadLetters, the items of a failed batch are put one by one and those
+// still failing are captured as dead letters instead of stopping the load.
+func (con *dynamodb) BulkPut(ctx context.Context, tableName string, items interface{}, opts BulkPutOptions) (int, error) {
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice {
+		return 0, errors.New("items must be a slice")
+	}
+	if rv.Len() < 1 {
+		return 0, nil
+	}
+	if err := con.guardBulk(rv.Len()); err != nil {
+		return 0, err
+	}
+
+	puts := make([]interface{}, rv.Len())
+	for i := range puts {
+		item, err := con.encodePut(tableName, rv.Index(i).Interface())
+		if err != nil {
+			return 0, err
+		}
+		puts[i] = item
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan []interface{})
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(batches)
+		for start := 0; start < len(puts); start += maxBatchWrite {
+			end := start + maxBatchWrite
+			if end > len(puts) {
+				end = len(puts)
+			}
+
+			select {
+			case batches <- puts[start:end]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return con.writeBatches(ctx, cancel, tableName, batches, produced, opts, nil)
+}
+
+// writeBatches writes the batches until the channel is closed, which is signaled by produced as well.
+// progress, if set, is called with the number of items written so far after every batch.
+func (con *dynamodb) writeBatches(ctx context.Context, cancel context.CancelFunc, tableName string, batches <-chan []interface{}, produced <-chan struct{}, opts BulkPutOptions, progress func(written int)) (int, error) {
+	var (
+		wg      sync.WaitGroup
+		once    sync.Once
+		mu      sync.Mutex
+		written int
+		first   error
+	)
+	write := func() {
+		defer wg.Done()
+		for batch := range batches {
+			n, err := con.table(tableName).Batch().Write().Put(batch...).RunWithContext(ctx)
+			if err != nil && ctx.Err() == nil && con.config.DeadLetters != nil {
+				n, err = con.putEach(ctx, tableName, batch)
+			}
+
+			mu.Lock()
+			written += n
+			if progress != nil {
+				progress(written)
+			}
+			mu.Unlock()
+
+			if err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+				return
+			}
+		}
+	}
+
+	start := func(n int) {
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go write()
+		}
+	}
+
+	if opts.WarmUp == nil {
+		concurrency := opts.Concurrency
+		if concurrency < 1 {
+			concurrency = DefaultBulkConcurrency
+		}
+		start(concurrency)
+	} else {
+		con.warmUp(produced, *opts.WarmUp, start)
+	}
+
+	wg.Wait()
+	if first == nil {
+		first = ctx.Err()
+	}
+	return written, first
+}
+
+// putEach puts the items of a failed batch one at a time, capturing the items that fail.
+func (con *dynamodb) putEach(ctx context.Context, tableName string, batch []interface{}) (int, error) {
+	written := 0
+	for _, item := range batch {
+		err := con.table(tableName).Put(item).RunWithContext(ctx)
+		if err == nil {
+			written++
+			continue
+		}
+		if ctx.Err() != nil {
+			return written, err
+		}
+
+		av, marshalErr := dynamo.MarshalItem(item)
+		if marshalErr != nil {
+			return written, marshalErr
+		}
+		if err := con.deadLetter(ctx, "BulkPut", tableName, av, err); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// warmUp starts writers, doubling them every interval until the maximum is reached.
+// It returns once all writers are started or there are no more batches to hand out.
+func (con *dynamodb) warmUp(produced <-chan struct{}, opts WarmUp, start func(n int)) {
+	current, max, interval := opts.Initial, opts.Max, opts.Interval
+	if current < 1 {
+		current = DefaultWarmUpInitial
+	}
+	if max < 1 {
+		max = DefaultWarmUpMax
+	}
+	if current > max {
+		current = max
+	}
+	if interval <= 0 {
+		interval = DefaultWarmUpInterval
+	}
+
+	start(current)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for current < max {
+		select {
+		case <-ticker.C:
+		case <-produced:
+			return
+		}
+
+		next := current * 2
+		if next > max {
+			next = max
+		}
+		start(next - current)
+		current = next
+	}
+}
@@ -0,0 +1,51 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkPut(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := "bulk-" + faker.UUIDDigit()
+	if err := dynamo.CreateTableWithOptions(name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		dynamo.DeleteTable(name)
+		dynamo.WaitUntilTableDeleted(context.Background(), name)
+	}()
+
+	items := make([]HashOnly, 120)
+	for i := range items {
+		items[i] = HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		n, err := dynamo.BulkPut(context.Background(), name, items[:60], BulkPutOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 60, n)
+	})
+
+	t.Run("Success: warm up", func(t *testing.T) {
+		n, err := dynamo.BulkPut(context.Background(), name, items[60:], BulkPutOptions{
+			WarmUp: &WarmUp{Initial: 1, Max: 4, Interval: 10 * time.Millisecond},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 60, n)
+
+		var all []HashOnly
+		assert.NoError(t, dynamo.Scan(name, &all))
+		assert.Len(t, all, len(items))
+	})
+
+	t.Run("Failure: not a slice", func(t *testing.T) {
+		_, err := dynamo.BulkPut(context.Background(), name, items[0], BulkPutOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,433 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// Cache stores items for NewCached. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(key string)
+}
+
+// NewCached caches Get results by table and key for ttl, and invalidates them on the writes
+// of one item: Put, Delete, SetSparse and their variants. Other writes, such as BestEffortGroup,
+// RenameAttribute or writes by other clients, are seen once the entries expire.
+func NewCached(db Dynamodb, cache Cache, ttl time.Duration) Dynamodb {
+	return NewCachedWithOptions(db, cache, CacheOptions{TTL: ttl})
+}
+
+// CacheOptions :
+type CacheOptions struct {
+	// TTL applies to Get results.
+	TTL time.Duration
+	// QueryTTL enables caching GetAll results by the shape of the query. Keep it short:
+	// a write through the cache invalidates the queries of its hash key, other writes are not seen.
+	QueryTTL time.Duration
+}
+
+func NewCachedWithOptions(db Dynamodb, cache Cache, opts CacheOptions) Dynamodb {
+	return &cached{Dynamodb: db, cache: cache, ttl: opts.TTL, queryTTL: opts.QueryTTL}
+}
+
+type cached struct {
+	Dynamodb
+	cache    Cache
+	ttl      time.Duration
+	queryTTL time.Duration
+
+	schemas sync.Map
+}
+
+func (c *cached) Get(tableName string, key DynamodbKey, result interface{}) error {
+	_, err := c.GetWithResponse(tableName, key, result)
+	return err
+}
+
+func (c *cached) GetWithResponse(tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error) {
+	cacheKey, ok := getCacheKey(tableName, key)
+	if !ok {
+		return c.Dynamodb.GetWithResponse(tableName, key, result)
+	}
+
+	if av, ok := c.cache.Get(cacheKey); ok {
+		return &DynamodbReadResponse{}, dynamo.UnmarshalItem(av.(map[string]*awsDynamodb.AttributeValue), result)
+	}
+
+	res, err := c.Dynamodb.GetWithResponse(tableName, key, result)
+	if err != nil {
+		return res, err
+	}
+	if av, err := dynamo.MarshalItem(result); err == nil {
+		c.cache.Set(cacheKey, av, c.ttl)
+	}
+	return res, nil
+}
+
+func (c *cached) GetAll(tableName string, key DynamodbKey, result interface{}) error {
+	_, err := c.GetAllWithResponse(tableName, key, result)
+	return err
+}
+
+func (c *cached) GetAllWithResponse(tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error) {
+	cacheKey, ok := c.queryCacheKey(tableName, key)
+	if !ok {
+		return c.Dynamodb.GetAllWithResponse(tableName, key, result)
+	}
+
+	if items, ok := c.cache.Get(cacheKey); ok {
+		return &DynamodbReadResponse{}, unmarshalItems(items.([]map[string]*awsDynamodb.AttributeValue), result)
+	}
+
+	res, err := c.Dynamodb.GetAllWithResponse(tableName, key, result)
+	if err != nil {
+		return res, err
+	}
+	if items, err := marshalItems(result); err == nil {
+		c.cache.Set(cacheKey, items, c.queryTTL)
+	}
+	return res, nil
+}
+
+func (c *cached) Put(tableName string, item interface{}) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.Put(tableName, item)
+}
+
+func (c *cached) PutIdempotent(tableName string, item interface{}, token string, ttl time.Duration) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.PutIdempotent(tableName, item, token, ttl)
+}
+
+func (c *cached) PutWithOldValue(tableName string, item interface{}, old interface{}) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.PutWithOldValue(tableName, item, old)
+}
+
+func (c *cached) PutIfNotExists(tableName string, item interface{}) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.PutIfNotExists(tableName, item)
+}
+
+func (c *cached) UpdateOnly(tableName string, item interface{}) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.UpdateOnly(tableName, item)
+}
+
+func (c *cached) PutWithMode(tableName string, item interface{}, mode WriteMode) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.PutWithMode(tableName, item, mode)
+}
+
+func (c *cached) PutIf(tableName string, item interface{}, conditions ...ScanFilter) (*DynamodbResponse, error) {
+	defer c.invalidateItem(tableName, item)
+	return c.Dynamodb.PutIf(tableName, item, conditions...)
+}
+
+func (c *cached) BulkPut(ctx context.Context, tableName string, items interface{}, opts BulkPutOptions) (int, error) {
+	defer func() {
+		rv := reflect.ValueOf(items)
+		if rv.Kind() != reflect.Slice {
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			c.invalidateItem(tableName, rv.Index(i).Interface())
+		}
+	}()
+	return c.Dynamodb.BulkPut(ctx, tableName, items, opts)
+}
+
+func (c *cached) Delete(tableName string, key DynamodbKey) (*DynamodbResponse, error) {
+	defer c.invalidate(tableName, key)
+	return c.Dynamodb.Delete(tableName, key)
+}
+
+func (c *cached) DeleteIf(tableName string, key DynamodbKey, conditions ...ScanFilter) (*DynamodbResponse, error) {
+	defer c.invalidate(tableName, key)
+	return c.Dynamodb.DeleteIf(tableName, key, conditions...)
+}
+
+func (c *cached) DeleteWithOldValue(tableName string, key DynamodbKey, old interface{}) (*DynamodbResponse, error) {
+	defer c.invalidate(tableName, key)
+	return c.Dynamodb.DeleteWithOldValue(tableName, key, old)
+}
+
+// DeleteAllByHash makes the cached queries of the partition unreachable.
+// Cached Gets of its items are not enumerated and expire with their TTL.
+func (c *cached) DeleteAllByHash(ctx context.Context, tableName string, hashKey string, hashValue interface{}) (int, error) {
+	defer func() {
+		if av, err := dynamo.Marshal(hashValue); err == nil {
+			c.bumpGeneration(tableName, hashKey, av)
+		}
+	}()
+	return c.Dynamodb.DeleteAllByHash(ctx, tableName, hashKey, hashValue)
+}
+
+func (c *cached) SetSparse(tableName string, key DynamodbKey, attribute string, value interface{}) error {
+	defer c.invalidate(tableName, key)
+	return c.Dynamodb.SetSparse(tableName, key, attribute, value)
+}
+
+func (c *cached) ClearSparse(tableName string, key DynamodbKey, attribute string) error {
+	defer c.invalidate(tableName, key)
+	return c.Dynamodb.ClearSparse(tableName, key, attribute)
+}
+
+func (c *cached) invalidate(tableName string, key DynamodbKey) {
+	if cacheKey, ok := getCacheKey(tableName, key); ok {
+		c.cache.Delete(cacheKey)
+	}
+	if key.Hash != nil {
+		hKey, hValue := key.Hash()
+		if av, err := dynamo.Marshal(hValue); err == nil {
+			c.bumpGeneration(tableName, hKey, av)
+		}
+	}
+}
+
+// invalidateItem drops the entry of item, whose key attributes are found through the table schema.
+func (c *cached) invalidateItem(tableName string, item interface{}) {
+	schema, ok := c.schemas.Load(tableName)
+	if !ok {
+		desc, err := c.DescribeTable(tableName)
+		if err != nil {
+			return
+		}
+		schema = []string{desc.HashKey, desc.RangeKey}
+		c.schemas.Store(tableName, schema)
+	}
+
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return
+	}
+	key := map[string]*awsDynamodb.AttributeValue{}
+	for _, name := range schema.([]string) {
+		if len(name) > 0 {
+			key[name] = av[name]
+		}
+	}
+	c.cache.Delete(cacheKey(tableName, key))
+
+	hKey := schema.([]string)[0]
+	c.bumpGeneration(tableName, hKey, av[hKey])
+}
+
+// generationKey is the cache key of the generation of a hash key. Query entries include the generation
+// in their key, so a write makes the cached queries of its hash key unreachable until they expire.
+func generationKey(tableName, hKey string, hValue *awsDynamodb.AttributeValue) string {
+	return "\x00generation\x00" + cacheKey(tableName, map[string]*awsDynamodb.AttributeValue{hKey: hValue})
+}
+
+func (c *cached) generation(tableName, hKey string, hValue *awsDynamodb.AttributeValue) int64 {
+	generation, _ := c.cache.Get(generationKey(tableName, hKey, hValue))
+	n, _ := generation.(int64)
+	return n
+}
+
+// bumpGeneration stores a new generation, which outlives the query entries of the previous one.
+// Generations are timestamps rather than counters, so one is never reused after its entry expired.
+func (c *cached) bumpGeneration(tableName, hKey string, hValue *awsDynamodb.AttributeValue) {
+	if c.queryTTL > 0 {
+		c.cache.Set(generationKey(tableName, hKey, hValue), time.Now().UnixNano(), c.queryTTL)
+	}
+}
+
+// queryShape identifies a GetAll by its key condition and options.
+type queryShape struct {
+	Index      string                      `json:",omitempty"`
+	RangeKey   string                      `json:",omitempty"`
+	RangeValue *awsDynamodb.AttributeValue `json:",omitempty"`
+	Options    *DynamodbOptions            `json:",omitempty"`
+	Hash       map[string]*awsDynamodb.AttributeValue
+	Generation int64
+}
+
+// queryCacheKey returns the cache key of a GetAll, or false when queries are not cached.
+func (c *cached) queryCacheKey(tableName string, key DynamodbKey) (string, bool) {
+	if c.queryTTL <= 0 || key.Hash == nil {
+		return "", false
+	}
+
+	hKey, hValue := key.Hash()
+	hv, err := dynamo.Marshal(hValue)
+	if err != nil {
+		return "", false
+	}
+	shape := queryShape{Hash: map[string]*awsDynamodb.AttributeValue{hKey: hv}}
+
+	var rValue interface{}
+	switch {
+	case key.Range != nil:
+		shape.RangeKey, rValue, shape.Options = key.Range()
+	case key.LocalSecondaryIndex != nil:
+		var index LocalSecondaryIndexName
+		index, shape.RangeKey, rValue, shape.Options = key.LocalSecondaryIndex()
+		shape.Index = string(index)
+	}
+	if rValue != nil {
+		if shape.RangeValue, err = dynamo.Marshal(rValue); err != nil {
+			return "", false
+		}
+	}
+	shape.Generation = c.generation(tableName, hKey, hv)
+
+	b, err := json.Marshal(shape)
+	if err != nil {
+		return "", false
+	}
+	return tableName + "\x00query\x00" + string(b), true
+}
+
+func marshalItems(result interface{}) ([]map[string]*awsDynamodb.AttributeValue, error) {
+	rv := reflect.Indirect(reflect.ValueOf(result))
+	if rv.Kind() != reflect.Slice {
+		return nil, errors.New("result must be a pointer to a slice")
+	}
+
+	items := make([]map[string]*awsDynamodb.AttributeValue, rv.Len())
+	for i := range items {
+		item, err := dynamo.MarshalItem(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func unmarshalItems(items []map[string]*awsDynamodb.AttributeValue, result interface{}) error {
+	slice := reflect.ValueOf(result).Elem()
+	out := reflect.MakeSlice(slice.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := dynamo.UnmarshalItem(item, out.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	slice.Set(out)
+	return nil
+}
+
+// getCacheKey returns the cache key of a Get. Range conditions other than equality are not cached.
+func getCacheKey(tableName string, key DynamodbKey) (string, bool) {
+	if key.Hash == nil || key.LocalSecondaryIndex != nil {
+		return "", false
+	}
+
+	values := map[string]interface{}{}
+	hKey, hValue := key.Hash()
+	values[hKey] = hValue
+	if key.Range != nil {
+		rKey, rValue, option := key.Range()
+		if option != nil && option.Operator != nil && *option.Operator != DynamodbEqual {
+			return "", false
+		}
+		values[rKey] = rValue
+	}
+
+	av := map[string]*awsDynamodb.AttributeValue{}
+	for name, value := range values {
+		v, err := dynamo.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		av[name] = v
+	}
+	return cacheKey(tableName, av), true
+}
+
+func cacheKey(tableName string, key map[string]*awsDynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{tableName}
+	for _, name := range names {
+		av := key[name]
+		switch {
+		case av == nil:
+			parts = append(parts, name+"=")
+		case av.S != nil:
+			parts = append(parts, name+"=S:"+*av.S)
+		case av.N != nil:
+			parts = append(parts, name+"=N:"+*av.N)
+		default:
+			parts = append(parts, name+"=B:"+base64.StdEncoding.EncodeToString(av.B))
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// MemoryCache is an in-process Cache. Expired entries are dropped when read, and swept on Set
+// once as many entries were set as the cache held, so entries never read again are released too.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	// sets counts the Sets since the last sweep.
+	sets int
+}
+
+type memoryEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]memoryEntry{}}
+}
+
+func (m *MemoryCache) Get(key string) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (m *MemoryCache) Set(key string, value interface{}, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.entries[key] = memoryEntry{value: value, expires: now.Add(ttl)}
+	if m.sets++; m.sets >= len(m.entries) {
+		m.sweep(now)
+	}
+}
+
+// sweep deletes the expired entries. Its cost is amortized over the Sets that preceded it.
+func (m *MemoryCache) sweep(now time.Time) {
+	for key, entry := range m.entries {
+		if now.After(entry.expires) {
+			delete(m.entries, key)
+		}
+	}
+	m.sets = 0
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
@@ -0,0 +1,141 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCached(t *testing.T) {
+	backend := newDynamo(t)
+	cache := NewMemoryCache()
+	dynamo := NewCached(backend, cache, time.Minute)
+
+	item := HashAndRange{Id: faker.UUIDDigit(), CreatedAt: time.Now().Format(time.RFC3339Nano), Name: "cached"}
+	key := DynamodbKey{
+		Hash:  func() (string, interface{}) { return "ID", item.Id },
+		Range: func() (string, interface{}, *DynamodbOptions) { return "CreatedAt", item.CreatedAt, nil },
+	}
+	_, err := dynamo.Put(tableNameHashAndRange, item)
+	assert.NoError(t, err)
+
+	var datum HashAndRange
+	assert.NoError(t, dynamo.Get(tableNameHashAndRange, key, &datum))
+	assert.Equal(t, item, datum)
+
+	t.Run("Read through", func(t *testing.T) {
+		stale := item
+		stale.Name = "changed behind the cache"
+		_, err := backend.Put(tableNameHashAndRange, stale)
+		assert.NoError(t, err)
+
+		var datum HashAndRange
+		assert.NoError(t, dynamo.Get(tableNameHashAndRange, key, &datum))
+		assert.Equal(t, "cached", datum.Name)
+	})
+
+	t.Run("Invalidated by Put", func(t *testing.T) {
+		next := item
+		next.Name = "next"
+		_, err := dynamo.Put(tableNameHashAndRange, next)
+		assert.NoError(t, err)
+
+		var datum HashAndRange
+		assert.NoError(t, dynamo.Get(tableNameHashAndRange, key, &datum))
+		assert.Equal(t, "next", datum.Name)
+	})
+
+	t.Run("Invalidated by Delete", func(t *testing.T) {
+		_, err := dynamo.Delete(tableNameHashAndRange, key)
+		assert.NoError(t, err)
+
+		var datum HashAndRange
+		assert.Error(t, dynamo.Get(tableNameHashAndRange, key, &datum))
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache()
+
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, -time.Second)
+
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+
+	t.Run("expired entries are swept on Set", func(t *testing.T) {
+		cache := NewMemoryCache()
+		for i := 0; i < 100; i++ {
+			cache.Set(faker.UUIDDigit(), i, -time.Second)
+		}
+		cache.Set("live", 1, time.Minute)
+
+		assert.True(t, len(cache.entries) < 100)
+		value, ok := cache.Get("live")
+		assert.True(t, ok)
+		assert.Equal(t, 1, value)
+	})
+}
+
+func TestNewCachedWithOptions(t *testing.T) {
+	backend := newDynamo(t)
+	dynamo := NewCachedWithOptions(backend, NewMemoryCache(), CacheOptions{TTL: time.Minute, QueryTTL: time.Minute})
+
+	id := faker.UUIDDigit()
+	first := HashAndRange{Id: id, CreatedAt: "2021-01-01", Name: faker.Name()}
+	_, err := dynamo.Put(tableNameHashAndRange, first)
+	assert.NoError(t, err)
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", id },
+	}
+	var items []HashAndRange
+	assert.NoError(t, dynamo.GetAll(tableNameHashAndRange, key, &items))
+	assert.Equal(t, []HashAndRange{first}, items)
+
+	t.Run("Read through", func(t *testing.T) {
+		_, err := backend.Put(tableNameHashAndRange, HashAndRange{Id: id, CreatedAt: "2021-01-02"})
+		assert.NoError(t, err)
+
+		var items []HashAndRange
+		assert.NoError(t, dynamo.GetAll(tableNameHashAndRange, key, &items))
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("Invalidated by a write to the hash key", func(t *testing.T) {
+		_, err := dynamo.Put(tableNameHashAndRange, HashAndRange{Id: id, CreatedAt: "2021-01-03"})
+		assert.NoError(t, err)
+
+		var items []HashAndRange
+		assert.NoError(t, dynamo.GetAll(tableNameHashAndRange, key, &items))
+		assert.Len(t, items, 3)
+	})
+}
+
+func TestCachedGeneration(t *testing.T) {
+	cache := NewMemoryCache()
+	c := &cached{cache: cache, queryTTL: 10 * time.Millisecond}
+	hv := &awsDynamodb.AttributeValue{S: aws.String(faker.UUIDDigit())}
+	assert.Zero(t, c.generation("table", "ID", hv))
+
+	c.bumpGeneration("table", "ID", hv)
+	first := c.generation("table", "ID", hv)
+	assert.NotZero(t, first)
+	c.bumpGeneration("table", "ID", hv)
+	assert.NotEqual(t, first, c.generation("table", "ID", hv))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, c.generation("table", "ID", hv))
+	assert.Empty(t, cache.entries)
+}
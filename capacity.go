@@ -0,0 +1,38 @@
+package dynamodb
+
+import (
+	"github.com/guregu/dynamo"
+)
+
+// ConsumedCapacity is the throughput consumed by one operation.
+type ConsumedCapacity struct {
+	Total float64
+	// Read and Write are only set for transactions.
+	Read  float64
+	Write float64
+	Table float64
+	GSI   map[string]float64
+	LSI   map[string]float64
+}
+
+// capacity returns a ConsumedCapacity to attach to requests, or nil when reporting is disabled.
+func (con *dynamodb) capacity() *dynamo.ConsumedCapacity {
+	if !con.config.ReturnConsumedCapacity {
+		return nil
+	}
+	return &dynamo.ConsumedCapacity{}
+}
+
+func consumed(cc *dynamo.ConsumedCapacity) *ConsumedCapacity {
+	if cc == nil {
+		return nil
+	}
+	return &ConsumedCapacity{
+		Total: cc.Total,
+		Read:  cc.Read,
+		Write: cc.Write,
+		Table: cc.Table,
+		GSI:   cc.GSI,
+		LSI:   cc.LSI,
+	}
+}
@@ -0,0 +1,47 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumedCapacity(t *testing.T) {
+	var expect HashOnly
+	faker.FakeData(&expect)
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), expect.Id },
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{ReturnConsumedCapacity: true})
+
+		res, err := dynamo.Put(tableNameHashOnly, &expect)
+		assert.NoError(t, err)
+		if assert.NotNil(t, res.ConsumedCapacity) {
+			assert.Greater(t, res.ConsumedCapacity.Total, float64(0))
+		}
+
+		var datum HashOnly
+		read, err := dynamo.GetWithResponse(tableNameHashOnly, key, &datum)
+		assert.NoError(t, err)
+		if assert.NotNil(t, read.ConsumedCapacity) {
+			assert.Greater(t, read.ConsumedCapacity.Total, float64(0))
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		dynamo := newDynamo(t)
+
+		res, err := dynamo.Put(tableNameHashOnly, &expect)
+		assert.NoError(t, err)
+		assert.Nil(t, res.ConsumedCapacity)
+
+		var datum HashOnly
+		read, err := dynamo.GetWithResponse(tableNameHashOnly, key, &datum)
+		assert.NoError(t, err)
+		assert.Nil(t, read.ConsumedCapacity)
+	})
+}
@@ -0,0 +1,115 @@
+package dynamodb
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Faults injects latency, throttling and partial batch failures into API calls, so resilience
+// tests can exercise retry and fallback paths. Nothing is injected unless DynamodbConfig.Faults is set.
+// Faults are counted per client, which makes them deterministic for a given sequence of calls.
+type Faults struct {
+	// Latency is added to every matching call.
+	Latency time.Duration
+	// ThrottleEvery fails every nth matching call with ProvisionedThroughputExceededException.
+	ThrottleEvery int
+	// UnprocessedEvery returns half of the items of every nth matching BatchGetItem or
+	// BatchWriteItem as unprocessed.
+	UnprocessedEvery int
+	// Operations limits the faults to these API operations, such as PutItem. Empty matches all.
+	Operations []string
+	// Tables limits the faults to these tables. Empty matches all.
+	Tables []string
+}
+
+func (f *Faults) matches(op OperationInfo) bool {
+	return f != nil && matchesAny(f.Operations, op.Name) && matchesAny(f.Tables, op.Table)
+}
+
+// matchesAny reports whether value is in list, or list is empty.
+func matchesAny(list []string, value string) bool {
+	if len(list) < 1 {
+		return true
+	}
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type faultCounters struct {
+	calls   int64
+	batches int64
+}
+
+func every(counter *int64, n int) bool {
+	return n > 0 && atomic.AddInt64(counter, 1)%int64(n) == 0
+}
+
+// injectFault delays the call and returns an injected throttling error when due.
+func (con *dynamodb) injectFault(ctx context.Context, op OperationInfo) error {
+	faults := con.config.Faults
+	if !faults.matches(op) {
+		return nil
+	}
+
+	if faults.Latency > 0 {
+		if err := aws.SleepWithContext(ctx, faults.Latency); err != nil {
+			return err
+		}
+	}
+	if every(&con.faults.calls, faults.ThrottleEvery) {
+		return awserr.NewRequestFailure(
+			awserr.New(awsDynamodb.ErrCodeProvisionedThroughputExceededException, "injected fault", nil),
+			400, "",
+		)
+	}
+	return nil
+}
+
+// partialBatch reports whether the batch call should leave half of its items unprocessed.
+func (con *dynamodb) partialBatch(op OperationInfo) bool {
+	faults := con.config.Faults
+	return faults.matches(op) && every(&con.faults.batches, faults.UnprocessedEvery)
+}
+
+// holdWrites splits the write requests of in, returning the requests to send and the ones held back.
+func holdWrites(in *awsDynamodb.BatchWriteItemInput) (*awsDynamodb.BatchWriteItemInput, map[string][]*awsDynamodb.WriteRequest) {
+	send := *in
+	send.RequestItems = make(map[string][]*awsDynamodb.WriteRequest, len(in.RequestItems))
+	held := make(map[string][]*awsDynamodb.WriteRequest)
+	for table, requests := range in.RequestItems {
+		n := (len(requests) + 1) / 2
+		send.RequestItems[table] = requests[:n]
+		if n < len(requests) {
+			held[table] = requests[n:]
+		}
+	}
+	return &send, held
+}
+
+// holdKeys splits the keys of in, returning the keys to read and the ones held back.
+func holdKeys(in *awsDynamodb.BatchGetItemInput) (*awsDynamodb.BatchGetItemInput, map[string]*awsDynamodb.KeysAndAttributes) {
+	send := *in
+	send.RequestItems = make(map[string]*awsDynamodb.KeysAndAttributes, len(in.RequestItems))
+	held := make(map[string]*awsDynamodb.KeysAndAttributes)
+	for table, keys := range in.RequestItems {
+		n := (len(keys.Keys) + 1) / 2
+		read := *keys
+		read.Keys = keys.Keys[:n]
+		send.RequestItems[table] = &read
+		if n < len(keys.Keys) {
+			rest := *keys
+			rest.Keys = keys.Keys[n:]
+			held[table] = &rest
+		}
+	}
+	return &send, held
+}
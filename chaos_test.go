@@ -0,0 +1,86 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaults(t *testing.T) {
+	t.Run("Latency", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Faults: &Faults{Latency: 100 * time.Millisecond}})
+
+		start := time.Now()
+		_, err := dynamo.Put(tableNameHashOnly, HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+	})
+
+	t.Run("ThrottleEvery", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Faults: &Faults{ThrottleEvery: 2, Operations: []string{"PutItem"}}})
+
+		var errs []error
+		dynamo.Use(func(ctx context.Context, op OperationInfo, next Handler) error {
+			err := next(ctx)
+			errs = append(errs, err)
+			return err
+		})
+
+		for i := 0; i < 2; i++ {
+			_, err := dynamo.Put(tableNameHashOnly, HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()})
+			assert.NoError(t, err)
+		}
+
+		if assert.Len(t, errs, 3) {
+			assert.NoError(t, errs[0])
+			assert.True(t, request.IsErrorThrottle(errs[1]))
+			assert.NoError(t, errs[2])
+		}
+	})
+
+	t.Run("UnprocessedEvery", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Faults: &Faults{UnprocessedEvery: 1}})
+
+		batches := 0
+		dynamo.Use(func(ctx context.Context, op OperationInfo, next Handler) error {
+			if op.Name == "BatchWriteItem" {
+				batches++
+			}
+			return next(ctx)
+		})
+
+		items := make([]HashOnly, 4)
+		for i := range items {
+			items[i] = HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		}
+		n, err := dynamo.BulkPut(context.Background(), tableNameHashOnly, items, BulkPutOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, len(items), n)
+		assert.Equal(t, 3, batches)
+	})
+}
+
+func TestHoldWrites(t *testing.T) {
+	put := func(id string) *awsDynamodb.WriteRequest {
+		return &awsDynamodb.WriteRequest{PutRequest: &awsDynamodb.PutRequest{
+			Item: map[string]*awsDynamodb.AttributeValue{"ID": {S: aws.String(id)}},
+		}}
+	}
+	in := &awsDynamodb.BatchWriteItemInput{RequestItems: map[string][]*awsDynamodb.WriteRequest{
+		"a": {put("1"), put("2"), put("3")},
+		"b": {put("4")},
+	}}
+
+	send, held := holdWrites(in)
+	assert.Len(t, send.RequestItems["a"], 2)
+	assert.Len(t, send.RequestItems["b"], 1)
+	assert.Len(t, held["a"], 1)
+	assert.NotContains(t, held, "b")
+	assert.Len(t, in.RequestItems["a"], 3)
+}
@@ -0,0 +1,60 @@
+package dynamodb
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time for timestamps and expirations written by the client.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a Clock for tests which only moves when told to.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock :
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set :
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance :
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Clock returns the Clock of DynamodbConfig, for packages keeping time consistently with the client.
+func (con *dynamodb) Clock() Clock {
+	return con.clock()
+}
+
+func (con *dynamodb) clock() Clock {
+	if con.config.Clock != nil {
+		return con.config.Clock
+	}
+	return systemClock{}
+}
@@ -0,0 +1,45 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{Clock: clock})
+
+	t.Run("ExpiresIn", func(t *testing.T) {
+		expect := ExpiresAt(clock.Now().Add(time.Hour))
+		assert.Equal(t, expect, dynamo.ExpiresIn(time.Hour))
+
+		clock.Advance(time.Minute)
+		assert.Equal(t, expect+60, dynamo.ExpiresIn(time.Hour))
+	})
+
+	t.Run("PutIdempotent", func(t *testing.T) {
+		var expect HashOnly
+		faker.FakeData(&expect)
+		token := faker.UUIDDigit()
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), expect.Id },
+		}
+
+		_, err := dynamo.PutIdempotent(tableNameHashOnly, &expect, token, time.Hour)
+		assert.NoError(t, err)
+
+		clock.Advance(2 * time.Hour)
+
+		replay := expect
+		replay.Name = "replayed"
+		_, err = dynamo.PutIdempotent(tableNameHashOnly, &replay, token, time.Hour)
+		assert.NoError(t, err)
+
+		var datum HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &datum))
+		assert.Equal(t, replay.Name, datum.Name)
+	})
+}
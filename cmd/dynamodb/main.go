@@ -0,0 +1,107 @@
+// Command dynamodb is an operations tool built on github.com/linksports/dynamodb.
+//
+// Usage:
+//
+//	dynamodb [-endpoint url] [-region region] <command> [arguments]
+//
+// Commands:
+//
+//	report [-analyze] [-top n] table...
+//		Print a usage and capacity summary per table.
+//
+//	scan [-page-size n] [-max-items n] [-resume-token token] table
+//		Print the items of a table as JSON lines.
+//
+//	rename [-page-size n] [-max-items n] [-resume-token token] table old new
+//		Rename an attribute on every item of a table.
+//
+// Scan and rename stop after -max-items and print the -resume-token to continue with,
+// so large tables can be processed in slices.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/linksports/dynamodb"
+)
+
+type env struct {
+	sess   *session.Session
+	config *dynamodb.DynamodbConfig
+	db     dynamodb.Dynamodb
+}
+
+// client returns an SDK client for the APIs the package does not wrap.
+func (e *env) client() *awsDynamodb.DynamoDB {
+	config := aws.NewConfig().WithRegion(e.config.Region)
+	if len(e.config.Endpoint) > 0 {
+		config = config.WithEndpoint(e.config.Endpoint)
+	}
+	return awsDynamodb.New(e.sess, config)
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(e *env, args []string) error
+}
+
+var commands = []command{
+	{"report", "[-analyze] [-top n] table...", runReport},
+	{"scan", "[-page-size n] [-max-items n] [-resume-token token] table", runScan},
+	{"rename", "[-page-size n] [-max-items n] [-resume-token token] table old new", runRename},
+}
+
+func main() {
+	endpoint := flag.String("endpoint", os.Getenv("DYNAMODB_ENDPOINT"), "DynamoDB endpoint, e.g. http://localhost:8000")
+	region := flag.String("region", os.Getenv("AWS_REGION"), "AWS region")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	e := &env{
+		sess: session.Must(session.NewSession()),
+		config: &dynamodb.DynamodbConfig{
+			Endpoint: *endpoint,
+			Region:   *region,
+		},
+	}
+
+	var err error
+	if e.db, err = dynamodb.New(e.sess, e.config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == flag.Arg(0) {
+			if err := cmd.run(e, flag.Args()[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dynamodb [-endpoint url] [-region region] <command> [arguments]")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %s %s\n", cmd.name, cmd.usage)
+	}
+}
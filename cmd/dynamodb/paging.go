@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/linksports/dynamodb"
+)
+
+// errMaxItems stops a job once -max-items is reached.
+var errMaxItems = errors.New("max items reached")
+
+// paging holds the flags shared by the commands that scan a whole table, so that large tables
+// can be processed in slices: each run stops after -max-items and prints the token to pass as
+// -resume-token to the next.
+type paging struct {
+	pageSize    int
+	maxItems    int64
+	resumeToken string
+}
+
+func pagingFlags(flags *flag.FlagSet) *paging {
+	p := &paging{}
+	flags.IntVar(&p.pageSize, "page-size", 100, "items scanned per request")
+	flags.Int64Var(&p.maxItems, "max-items", 0, "stop after the page reaching n items, 0 for no limit")
+	flags.StringVar(&p.resumeToken, "resume-token", "", "resume from the token printed by a previous run")
+	return p
+}
+
+// reached reports whether n items fill -max-items.
+func (p *paging) reached(n int64) bool {
+	return p.maxItems > 0 && n >= p.maxItems
+}
+
+// resume prints the token to continue from, or that the table is done.
+func (p *paging) resume(token string) {
+	if len(token) > 0 {
+		fmt.Fprintf(os.Stderr, "resume with -resume-token %s\n", token)
+	} else {
+		fmt.Fprintln(os.Stderr, "done")
+	}
+}
+
+func runScan(e *env, args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	p := pagingFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("scan: table name required")
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	token, scanned := p.resumeToken, int64(0)
+	for {
+		pageSize := int64(p.pageSize)
+		if p.maxItems > 0 && p.maxItems-scanned < pageSize {
+			pageSize = p.maxItems - scanned
+		}
+
+		var items []map[string]interface{}
+		next, err := e.db.ScanPage(context.Background(), flags.Arg(0), &items, dynamodb.ScanPageOptions{
+			PageSize:  int(pageSize),
+			StartFrom: token,
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+
+		token, scanned = next, scanned+int64(len(items))
+		if len(token) < 1 || p.reached(scanned) {
+			p.resume(token)
+			return nil
+		}
+	}
+}
+
+func runRename(e *env, args []string) error {
+	flags := flag.NewFlagSet("rename", flag.ExitOnError)
+	p := pagingFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 3 {
+		return fmt.Errorf("rename: table, old and new attribute names required")
+	}
+
+	token := ""
+	result, err := e.db.RenameAttribute(context.Background(), flags.Arg(0), flags.Arg(1), flags.Arg(2), dynamodb.RenameOptions{
+		PageSize:  p.pageSize,
+		StartFrom: p.resumeToken,
+		Checkpoint: func(next string, result dynamodb.RenameResult) error {
+			token = next
+			if p.reached(result.Renamed + result.Skipped) {
+				return errMaxItems
+			}
+			return nil
+		},
+	})
+	if err != nil && !errors.Is(err, errMaxItems) {
+		return err
+	}
+	if err == nil {
+		token = ""
+	}
+
+	fmt.Printf("renamed %d, skipped %d\n", result.Renamed, result.Skipped)
+	p.resume(token)
+	return nil
+}
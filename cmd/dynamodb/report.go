@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/linksports/dynamodb"
+)
+
+func runReport(e *env, args []string) error {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	analyze := flags.Bool("analyze", false, "scan each table to measure item sizes (consumes read capacity)")
+	top := flags.Int("top", 5, "number of largest items listed with -analyze")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		return fmt.Errorf("report: table name required")
+	}
+
+	client := e.client()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	for _, name := range flags.Args() {
+		desc, err := e.db.DescribeTable(name)
+		if err != nil {
+			return err
+		}
+		writeDescription(w, desc)
+
+		insights, err := client.DescribeContributorInsights(&awsDynamodb.DescribeContributorInsightsInput{
+			TableName: aws.String(name),
+		})
+		if err != nil {
+			fmt.Fprintf(w, "Contributor Insights\tunavailable (%v)\n", err)
+		} else {
+			fmt.Fprintf(w, "Contributor Insights\t%s\n", aws.StringValue(insights.ContributorInsightsStatus))
+			for _, rule := range insights.ContributorInsightsRuleList {
+				fmt.Fprintf(w, "  rule\t%s\n", aws.StringValue(rule))
+			}
+		}
+
+		if *analyze {
+			report, err := e.db.ScanAnalyze(name, *top)
+			if err != nil {
+				return err
+			}
+			writeScanReport(w, report)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func writeDescription(w io.Writer, desc *dynamodb.TableDescription) {
+	fmt.Fprintf(w, "Table\t%s (%s)\n", desc.Name, desc.Status)
+	fmt.Fprintf(w, "Items\t%d\n", desc.Items)
+	fmt.Fprintf(w, "Size\t%s\n", byteSize(desc.SizeBytes))
+	if desc.OnDemand {
+		fmt.Fprintf(w, "Capacity\ton-demand\n")
+	} else {
+		fmt.Fprintf(w, "Capacity\t%d RCU / %d WCU\n", desc.ReadUnits, desc.WriteUnits)
+	}
+	for _, index := range desc.GSIs {
+		fmt.Fprintf(w, "  GSI %s\t%s, %d RCU / %d WCU\n", index.Name, index.Projection, index.ReadUnits, index.WriteUnits)
+	}
+	for _, index := range desc.LSIs {
+		fmt.Fprintf(w, "  LSI %s\t%s\n", index.Name, index.Projection)
+	}
+}
+
+func writeScanReport(w io.Writer, report *dynamodb.ScanReport) {
+	avg := report.AverageBytes()
+	fmt.Fprintf(w, "Scanned items\t%d (%s)\n", report.Items, byteSize(report.TotalBytes))
+	fmt.Fprintf(w, "Average item\t%s, %d RCU per strong read, %d WCU per write\n", byteSize(avg), units(avg, 4<<10), units(avg, 1<<10))
+	fmt.Fprintf(w, "Largest item\t%s\n", byteSize(report.MaxBytes))
+
+	for i, count := range report.Histogram {
+		if i < len(dynamodb.ScanReportBuckets) {
+			fmt.Fprintf(w, "  <= %s\t%d\n", byteSize(dynamodb.ScanReportBuckets[i]), count)
+		} else {
+			fmt.Fprintf(w, "  >  %s\t%d\n", byteSize(dynamodb.ScanReportBuckets[i-1]), count)
+		}
+	}
+	for _, item := range report.Largest {
+		fmt.Fprintf(w, "  %s\t", byteSize(item.Bytes))
+		for _, key := range item.Key {
+			fmt.Fprintf(w, "%s=%v ", key.Key, key.Value)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func units(size, unit int64) int64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + unit - 1) / unit
+}
+
+func byteSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
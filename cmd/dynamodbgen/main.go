@@ -0,0 +1,255 @@
+// Command dynamodbgen generates constants for the attribute and index names declared
+// by the dynamo struct tags of entities, so keys and filters stop repeating raw strings:
+//
+//	//go:generate go run github.com/linksports/dynamodb/cmd/dynamodbgen -type HashAndRange
+//
+// For each struct it writes <Type>Attr<Field> constants, such as HashAndRangeAttrCreatedAt,
+// and <Type>Index<Name> constants for the names of index and localIndex tags.
+//
+// Usage:
+//
+//	dynamodbgen [-type T,...] [-output file] [dir]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	types := flag.String("type", "", "comma separated struct names; all structs with dynamo tags when empty")
+	output := flag.String("output", "dynamo_keys.go", "output file name, relative to dir")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var names []string
+	if len(*types) > 0 {
+		names = strings.Split(*types, ",")
+	}
+
+	src, err := generate(dir, names, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dynamodbgen:", err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, *output), src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "dynamodbgen:", err)
+		os.Exit(1)
+	}
+}
+
+type entity struct {
+	name       string
+	tagged     bool
+	attributes []constant
+	indexes    []constant
+}
+
+type constant struct {
+	name  string
+	value string
+}
+
+// generate parses the package in dir, skipping test files and the output file.
+func generate(dir string, types []string, output string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%d packages in %s, want 1", len(pkgs), dir)
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	structs := map[string]*ast.StructType{}
+	for _, file := range pkg.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	all := types == nil
+	if all {
+		for name := range structs {
+			types = append(types, name)
+		}
+		sort.Strings(types)
+	}
+
+	var entities []entity
+	for _, name := range types {
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct %s not found", name)
+		}
+
+		e := entity{name: name}
+		collect(&e, st, structs, map[string]bool{})
+		if len(e.attributes) > 0 && (e.tagged || !all) {
+			entities = append(entities, e)
+		}
+	}
+
+	return render(pkg.Name, entities)
+}
+
+// collect adds the fields of st to e, flattening embedded structs of the package like dynamo does.
+func collect(e *entity, st *ast.StructType, structs map[string]*ast.StructType, indexes map[string]bool) {
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			value, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(value)
+		}
+
+		if len(field.Names) < 1 {
+			if embedded, ok := structs[typeName(field.Type)]; ok && len(tag.Get("dynamo")) < 1 {
+				collect(e, embedded, structs, indexes)
+			}
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			if len(tag) > 0 {
+				e.tagged = true
+			}
+
+			name := strings.Split(tag.Get("dynamo"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if len(name) < 1 {
+				name = ident.Name
+			}
+			e.attributes = append(e.attributes, constant{name: e.name + "Attr" + ident.Name, value: name})
+
+			for _, key := range []string{"index", "localIndex"} {
+				for _, index := range tagValues(string(tag), key) {
+					index = strings.Split(index, ",")[0]
+					if len(index) < 1 || indexes[index] {
+						continue
+					}
+					indexes[index] = true
+					e.indexes = append(e.indexes, constant{name: e.name + "Index" + identifier(index), value: index})
+				}
+			}
+		}
+	}
+}
+
+// tagValues returns every value of key, which dynamo allows to repeat for fields in several indexes.
+func tagValues(tag, key string) []string {
+	var values []string
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		i := strings.Index(tag, ":\"")
+		if i < 1 {
+			return values
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		j := 1
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(tag) {
+			return values
+		}
+		value, err := strconv.Unquote(tag[:j+1])
+		if err != nil {
+			return values
+		}
+		tag = tag[j+1:]
+
+		if name == key {
+			values = append(values, value)
+		}
+	}
+}
+
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	}
+	return ""
+}
+
+// identifier turns an index name such as Status-index into StatusIndex.
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func render(pkg string, entities []entity) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by dynamodbgen; DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	block := func(comment string, constants []constant) {
+		if len(constants) < 1 {
+			return
+		}
+		fmt.Fprintf(&b, "\n// %s\nconst (\n", comment)
+		for _, c := range constants {
+			fmt.Fprintf(&b, "%s = %q\n", c.name, c.value)
+		}
+		fmt.Fprintf(&b, ")\n")
+	}
+	for _, e := range entities {
+		block(e.name+" attribute names.", e.attributes)
+		block(e.name+" index names.", e.indexes)
+	}
+
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const entities = `package models
+
+type Base struct {
+	CreatedAt string ` + "`dynamo:\"CreatedAt,range\" localIndex:\"ID-CreatedAt-index,range\"`" + `
+}
+
+type Post struct {
+	Base
+	Id      string ` + "`dynamo:\"ID,hash\"`" + `
+	Seq     int64  ` + "`index:\"Seq-index,hash\" index:\"Author-Seq-index,range\"`" + `
+	Author  string ` + "`index:\"Author-Seq-index,hash\"`" + `
+	Ignored string ` + "`dynamo:\"-\"`" + `
+	private string
+}
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "models.go"), []byte(entities), 0644))
+
+	src, err := generate(dir, []string{"Post"}, "dynamo_keys.go")
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by dynamodbgen; DO NOT EDIT.
+
+package models
+
+// Post attribute names.
+const (
+	PostAttrCreatedAt = "CreatedAt"
+	PostAttrId        = "ID"
+	PostAttrSeq       = "Seq"
+	PostAttrAuthor    = "Author"
+)
+
+// Post index names.
+const (
+	PostIndexIDCreatedAtIndex = "ID-CreatedAt-index"
+	PostIndexSeqIndex         = "Seq-index"
+	PostIndexAuthorSeqIndex   = "Author-Seq-index"
+)
+`, string(src))
+
+	_, err = generate(dir, []string{"Missing"}, "dynamo_keys.go")
+	assert.Error(t, err)
+}
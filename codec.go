@@ -0,0 +1,202 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// Codec serializes struct fields tagged `dynamo:"Name,codec"` into a single binary attribute,
+// which shrinks very wide items. Plug in protobuf, msgpack or CBOR through DynamodbConfig.Codec.
+// Coded fields are encoded by every put, including batch, group and transaction writes, and
+// decoded by every read into a struct or a slice of structs. Reads into maps return the raw binary.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the default Codec.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (con *dynamodb) codec() Codec {
+	if con.config.Codec != nil {
+		return con.config.Codec
+	}
+	return JSONCodec{}
+}
+
+// encodeItem prepares item for a put to tableName, applying the sparse, codec, gzip and encrypted tags.
+// tableName is the name without DynamodbConfig.TablePrefix, which encrypted fields are bound to.
+func (con *dynamodb) encodeItem(tableName string, item interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(item)
+	sparse, coded := taggedFields(rv, "sparse"), taggedFields(rv, "codec")
+	compressed, encrypted := taggedFields(rv, "gzip"), taggedFields(rv, "encrypted")
+	if len(sparse) < 1 && len(coded) < 1 && len(compressed) < 1 && len(encrypted) < 1 {
+		return item, nil
+	}
+
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return nil, err
+	}
+	removeSparse(av, sparse)
+	if err := con.encodeFields(av, coded); err != nil {
+		return nil, err
+	}
+	if err := con.compressFields(av, compressed); err != nil {
+		return nil, err
+	}
+	if err := con.encryptFields(tableName, av, encrypted); err != nil {
+		return nil, err
+	}
+	return av, nil
+}
+
+func (con *dynamodb) encodeFields(av map[string]*awsDynamodb.AttributeValue, fields []taggedField) error {
+	for _, field := range fields {
+		if field.value.IsZero() {
+			delete(av, field.name)
+			continue
+		}
+
+		data, err := con.codec().Marshal(field.value.Interface())
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", field.name, err)
+		}
+		av[field.name] = &awsDynamodb.AttributeValue{B: data}
+	}
+	return nil
+}
+
+// hasCodedFields reports whether out, a pointer to a struct or a slice of structs, needs decodeItem
+// for its coded, gzip or encrypted fields.
+func hasCodedFields(out interface{}) bool {
+	t := reflect.TypeOf(out)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	if t = t.Elem(); t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	v := reflect.New(t)
+	for _, option := range []string{"codec", "gzip", "encrypted"} {
+		if len(taggedFields(v, option)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeItem unmarshals av, an item of tableName, into out, a pointer to a struct, decoding its coded,
+// gzip and encrypted fields.
+func (con *dynamodb) decodeItem(tableName string, av map[string]*awsDynamodb.AttributeValue, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	fields, compressed, encrypted := taggedFields(rv, "codec"), taggedFields(rv, "gzip"), taggedFields(rv, "encrypted")
+
+	rest := make(map[string]*awsDynamodb.AttributeValue, len(av))
+	for name, value := range av {
+		rest[name] = value
+	}
+	for _, tagged := range [][]taggedField{fields, compressed, encrypted} {
+		for _, field := range tagged {
+			delete(rest, field.name)
+		}
+	}
+	if err := dynamo.UnmarshalItem(rest, out); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		value := av[field.name]
+		if value == nil {
+			continue
+		}
+		if value.B == nil {
+			return fmt.Errorf("decode %s: not a binary attribute", field.name)
+		}
+		if err := con.codec().Unmarshal(value.B, field.value.Addr().Interface()); err != nil {
+			return fmt.Errorf("decode %s: %w", field.name, err)
+		}
+	}
+	for _, field := range compressed {
+		if value := av[field.name]; value != nil {
+			if err := con.decompressField(value, field); err != nil {
+				return err
+			}
+		}
+	}
+	for _, field := range encrypted {
+		if value := av[field.name]; value != nil {
+			if err := con.decryptField(tableName, av, field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeItems appends the items to out, a pointer to a slice, decoding their coded fields.
+func (con *dynamodb) decodeItems(tableName string, items []map[string]*awsDynamodb.AttributeValue, out interface{}) error {
+	slice := reflect.ValueOf(out).Elem()
+	elem := slice.Type().Elem()
+	for _, item := range items {
+		ptr := elem.Kind() == reflect.Ptr
+		t := elem
+		if ptr {
+			t = elem.Elem()
+		}
+
+		v := reflect.New(t)
+		if err := con.decodeItem(tableName, item, v.Interface()); err != nil {
+			return err
+		}
+		if !ptr {
+			v = v.Elem()
+		}
+		slice.Set(reflect.Append(slice, v))
+	}
+	return nil
+}
+
+// readItem calls read with out, or with the raw item decoded into out when out has coded fields.
+func (con *dynamodb) readItem(tableName string, out interface{}, read func(out interface{}) error) error {
+	if !hasCodedFields(out) {
+		return read(out)
+	}
+
+	var av map[string]*awsDynamodb.AttributeValue
+	if err := read(&av); err != nil {
+		return err
+	}
+	return con.decodeItem(tableName, av, out)
+}
+
+// readItems is readItem for out, a pointer to a slice.
+func (con *dynamodb) readItems(tableName string, out interface{}, read func(out interface{}) error) error {
+	if !hasCodedFields(out) {
+		return read(out)
+	}
+
+	var items []map[string]*awsDynamodb.AttributeValue
+	if err := read(&items); err != nil {
+		return err
+	}
+	return con.decodeItems(tableName, items, out)
+}
@@ -0,0 +1,111 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type CodedDetails struct {
+	Tags  []string
+	Score int
+}
+
+type CodedItem struct {
+	Id      string        `dynamo:"ID,hash"`
+	Name    string        `dynamo:"Name"`
+	Details *CodedDetails `dynamo:"Details,codec"`
+}
+
+type CodedEntry struct {
+	Id        string        `dynamo:"ID,hash"`
+	CreatedAt string        `dynamo:"CreatedAt,range"`
+	Details   *CodedDetails `dynamo:"Details,codec"`
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func TestCodec(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		dynamo := newDynamo(t)
+		item := CodedItem{
+			Id:      faker.UUIDDigit(),
+			Name:    faker.Name(),
+			Details: &CodedDetails{Tags: []string{"a", "b"}, Score: 3},
+		}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+		}
+
+		var raw map[string]interface{}
+		err = dynamo.Get(tableNameHashOnly, key, &raw)
+		assert.NoError(t, err)
+		assert.IsType(t, []byte{}, raw["Details"])
+
+		var got CodedItem
+		err = dynamo.Get(tableNameHashOnly, key, &got)
+		assert.NoError(t, err)
+		assert.Equal(t, item, got)
+
+		var all []CodedItem
+		err = dynamo.GetAll(tableNameHashOnly, key, &all)
+		assert.NoError(t, err)
+		assert.Equal(t, []CodedItem{item}, all)
+	})
+
+	t.Run("zero value is not stored", func(t *testing.T) {
+		dynamo := newDynamo(t)
+		item := CodedItem{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+		}
+
+		var got CodedItem
+		err = dynamo.Get(tableNameHashOnly, key, &got)
+		assert.NoError(t, err)
+		assert.Nil(t, got.Details)
+	})
+
+	t.Run("read paths", func(t *testing.T) {
+		dynamo := newDynamo(t)
+		id := faker.UUIDDigit()
+		testReadPaths(t, dynamo, []CodedEntry{
+			{Id: id, CreatedAt: "1", Details: &CodedDetails{Tags: []string{"a"}, Score: 1}},
+			{Id: id, CreatedAt: "2", Details: &CodedDetails{Tags: []string{"b", "c"}, Score: 2}},
+		})
+	})
+
+	t.Run("custom codec", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Codec: gobCodec{}})
+		item := CodedItem{
+			Id:      faker.UUIDDigit(),
+			Details: &CodedDetails{Tags: []string{"x"}, Score: 7},
+		}
+		_, err := dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+
+		var got CodedItem
+		err = dynamo.Query(tableNameHashOnly).Hash("ID", item.Id).One(&got)
+		assert.NoError(t, err)
+		assert.Equal(t, item, got)
+	})
+}
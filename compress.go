@@ -0,0 +1,80 @@
+package dynamodb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// compressFields replaces the fields of av tagged `dynamo:"Name,gzip"` by a gzip compressed binary,
+// which keeps items with large texts or payloads under the item size limit. Strings and byte slices
+// are compressed as is, other types as their codec value.
+func (con *dynamodb) compressFields(av map[string]*awsDynamodb.AttributeValue, fields []taggedField) error {
+	for _, field := range fields {
+		if field.value.IsZero() {
+			delete(av, field.name)
+			continue
+		}
+
+		var data []byte
+		switch v := field.value.Interface().(type) {
+		case string:
+			data = []byte(v)
+		case []byte:
+			data = v
+		default:
+			var err error
+			if data, err = con.codec().Marshal(v); err != nil {
+				return fmt.Errorf("compress %s: %w", field.name, err)
+			}
+		}
+
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("compress %s: %w", field.name, err)
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("compress %s: %w", field.name, err)
+		}
+		av[field.name] = &awsDynamodb.AttributeValue{B: buf.Bytes()}
+	}
+	return nil
+}
+
+// decompressField decompresses value, written by compressFields, into field.
+// A string value, written before the field was tagged gzip, is read as is.
+func (con *dynamodb) decompressField(value *awsDynamodb.AttributeValue, field taggedField) error {
+	if value.S != nil && field.value.Kind() == reflect.String {
+		field.value.SetString(*value.S)
+		return nil
+	}
+	if value.B == nil {
+		return fmt.Errorf("decompress %s: not a binary attribute", field.name)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(value.B))
+	if err != nil {
+		return fmt.Errorf("decompress %s: %w", field.name, err)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("decompress %s: %w", field.name, err)
+	}
+
+	switch field.value.Interface().(type) {
+	case string:
+		field.value.SetString(string(data))
+	case []byte:
+		field.value.SetBytes(data)
+	default:
+		if err := con.codec().Unmarshal(data, field.value.Addr().Interface()); err != nil {
+			return fmt.Errorf("decompress %s: %w", field.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package dynamodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type CompressedItem struct {
+	Id      string        `dynamo:"ID,hash"`
+	Name    string        `dynamo:"Name,gzip"`
+	Payload []byte        `dynamo:"Payload,gzip"`
+	Details *CodedDetails `dynamo:"Details,gzip"`
+}
+
+type CompressedEntry struct {
+	Id        string `dynamo:"ID,hash"`
+	CreatedAt string `dynamo:"CreatedAt,range"`
+	Payload   []byte `dynamo:"Payload,gzip"`
+}
+
+type CompressedTask struct {
+	Id      string `dynamo:"ID,hash"`
+	Payload []byte `dynamo:"Payload,gzip"`
+	Pending int    `dynamo:"Pending,sparse"`
+}
+
+func TestCompressedFields(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	t.Run("Success: over the item size limit", func(t *testing.T) {
+		item := CompressedItem{
+			Id:      faker.UUIDDigit(),
+			Name:    strings.Repeat(faker.Name(), 50000),
+			Payload: []byte(strings.Repeat("payload", 1000)),
+			Details: &CodedDetails{Tags: []string{"a"}, Score: 1},
+		}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+		}
+		var raw map[string]interface{}
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &raw))
+		assert.IsType(t, []byte{}, raw["Name"])
+
+		var got CompressedItem
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &got))
+		assert.Equal(t, item, got)
+	})
+
+	t.Run("Success: uncompressed string", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		var got CompressedItem
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+		}, &got))
+		assert.Equal(t, item.Name, got.Name)
+	})
+
+	t.Run("Success: PutIdempotent", func(t *testing.T) {
+		item := CompressedItem{Id: faker.UUIDDigit(), Name: faker.Name(), Payload: []byte(strings.Repeat("payload", 1000))}
+		_, err := dynamo.PutIdempotent(tableNameHashOnly, item, faker.UUIDDigit(), time.Hour)
+		assert.NoError(t, err)
+
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+		}
+		var raw map[string]interface{}
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &raw))
+		assert.Less(t, len(raw["Payload"].([]byte)), len(item.Payload))
+
+		var got CompressedItem
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &got))
+		assert.Equal(t, item, got)
+	})
+
+	t.Run("Success: read paths", func(t *testing.T) {
+		id := faker.UUIDDigit()
+		testReadPaths(t, dynamo, []CompressedEntry{
+			{Id: id, CreatedAt: "1", Payload: []byte(strings.Repeat("first", 1000))},
+			{Id: id, CreatedAt: "2", Payload: []byte(strings.Repeat("second", 1000))},
+		})
+	})
+
+	t.Run("Success: ScanSparseIndex", func(t *testing.T) {
+		name := "compressed-" + faker.UUIDDigit()
+		err := dynamo.CreateTableWithOptions(name, CompressedTask{}, CreateTableOptions{
+			OnDemand: true,
+			GSIs: []IndexDefinition{
+				{Name: "Pending-index", HashKey: "Pending", HashKeyType: DynamodbKeyTypeNumber, Projection: DynamodbProjectionAll},
+			},
+			Wait: true,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer func() {
+			dynamo.DeleteTable(name)
+			dynamo.WaitUntilTableDeleted(context.Background(), name)
+		}()
+
+		item := CompressedTask{Id: faker.UUIDDigit(), Payload: []byte(strings.Repeat("payload", 1000)), Pending: 1}
+		_, err = dynamo.Put(name, item)
+		assert.NoError(t, err)
+
+		var items []CompressedTask
+		assert.NoError(t, dynamo.ScanSparseIndex(name, "Pending-index", &items))
+		assert.Equal(t, []CompressedTask{item}, items)
+	})
+}
@@ -0,0 +1,119 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guregu/dynamo"
+)
+
+// ErrIndexNotConsistent is returned when a written item did not appear on an index in time.
+// The write itself succeeded.
+var ErrIndexNotConsistent = errors.New("index not consistent")
+
+// Defaults used for unset IndexConsistency fields.
+const (
+	DefaultIndexConsistencyAttempts = 10
+	DefaultIndexConsistencyDelay    = 100 * time.Millisecond
+)
+
+// IndexConsistency makes writes wait until the item can be read from global secondary indexes,
+// for flows that query an index right after writing. Only the presence of the item under its
+// new index keys is verified.
+type IndexConsistency struct {
+	// Indexes lists the index names to verify by table name.
+	Indexes map[string][]string
+	// MaxAttempts bounds the reads per index.
+	MaxAttempts int
+	// Delay is the wait between reads.
+	Delay time.Duration
+}
+
+func (con *dynamodb) waitIndexes(tableName string, item interface{}) error {
+	names := con.config.IndexConsistency.Indexes[tableName]
+	if len(names) < 1 {
+		return nil
+	}
+
+	desc, err := con.describeCached(tableName)
+	if err != nil {
+		return err
+	}
+
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var index *IndexDefinition
+		for i := range desc.GSIs {
+			if desc.GSIs[i].Name == name {
+				index = &desc.GSIs[i]
+			}
+		}
+		if index == nil {
+			return fmt.Errorf("index %s not found on %s", name, tableName)
+		}
+
+		// items without the index keys are not part of a sparse index
+		if av[index.HashKey] == nil || (index.RangeKey != "" && av[index.RangeKey] == nil) {
+			continue
+		}
+
+		query := con.table(tableName).Get(index.HashKey, av[index.HashKey]).Index(name).
+			Filter("$ = ?", desc.HashKey, av[desc.HashKey])
+		if index.RangeKey != "" {
+			query = query.Range(index.RangeKey, dynamo.Equal, av[index.RangeKey])
+		}
+		if desc.RangeKey != "" {
+			query = query.Filter("$ = ?", desc.RangeKey, av[desc.RangeKey])
+		}
+
+		if err := con.waitIndex(query); err != nil {
+			return fmt.Errorf("%w: %s", err, name)
+		}
+	}
+	return nil
+}
+
+func (con *dynamodb) waitIndex(query *dynamo.Query) error {
+	attempts := con.config.IndexConsistency.MaxAttempts
+	if attempts < 1 {
+		attempts = DefaultIndexConsistencyAttempts
+	}
+	delay := con.config.IndexConsistency.Delay
+	if delay <= 0 {
+		delay = DefaultIndexConsistencyDelay
+	}
+
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+		}
+
+		count, err := query.Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	return ErrIndexNotConsistent
+}
+
+// describeCached describes a table once per client.
+func (con *dynamodb) describeCached(tableName string) (*TableDescription, error) {
+	if desc, ok := con.descriptions.Load(tableName); ok {
+		return desc.(*TableDescription), nil
+	}
+
+	desc, err := con.DescribeTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+	con.descriptions.Store(tableName, desc)
+	return desc, nil
+}
@@ -0,0 +1,58 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexConsistency(t *testing.T) {
+	name := "consistency-" + faker.UUIDDigit()
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{
+		IndexConsistency: IndexConsistency{
+			Indexes:     map[string][]string{name: {"Status-index"}},
+			MaxAttempts: 20,
+			Delay:       50 * time.Millisecond,
+		},
+	})
+
+	err := dynamo.CreateTableWithOptions(name, WithIndex{}, CreateTableOptions{
+		OnDemand: true,
+		GSIs: []IndexDefinition{
+			{Name: "Status-index", HashKey: "Status", HashKeyType: DynamodbKeyTypeNumber, RangeKey: "CreatedAt"},
+		},
+		Wait: true,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		dynamo.DeleteTable(name)
+		dynamo.WaitUntilTableDeleted(context.Background(), name)
+	}()
+
+	t.Run("Put", func(t *testing.T) {
+		item := WithIndex{Id: faker.UUIDDigit(), CreatedAt: time.Now().Format(time.RFC3339Nano), Status: 1}
+		_, err := dynamo.Put(name, item)
+		assert.NoError(t, err)
+	})
+
+	t.Run("PutWithOldValue", func(t *testing.T) {
+		item := WithIndex{Id: faker.UUIDDigit(), CreatedAt: time.Now().Format(time.RFC3339Nano), Status: 2}
+		var old WithIndex
+		res, err := dynamo.PutWithOldValue(name, item, &old)
+		assert.NoError(t, err)
+		assert.False(t, res.HasOldValue)
+	})
+
+	t.Run("Failure: unknown index", func(t *testing.T) {
+		other := newDynamoWithConfig(t, &DynamodbConfig{
+			IndexConsistency: IndexConsistency{Indexes: map[string][]string{name: {"not-exists"}}},
+		})
+		_, err := other.Put(name, WithIndex{Id: faker.UUIDDigit(), CreatedAt: "now"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,121 @@
+package dynamodb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// copyChunk is the number of scanned items handed to BulkPut at once.
+const copyChunk = 100
+
+// CopyOptions :
+type CopyOptions struct {
+	// DryRun describes the source and counts its items without creating or writing anything.
+	DryRun bool
+	// Destination receives the copy, such as a client of another environment. Defaults to this client.
+	Destination Dynamodb
+	// Segments scanned in parallel. Defaults to DefaultExportSegments.
+	Segments int
+	// Concurrency of the batch writers per segment.
+	Concurrency int
+}
+
+// CopyResult :
+type CopyResult struct {
+	// Spec is the schema of the destination, derived from the source.
+	Spec TableSpec
+	// Items is the number of items copied, or found with DryRun.
+	Items int
+}
+
+// CopyTable creates dst with the schema of src, including indexes, TTL and stream, and copies
+// every item across with a parallel scan. An existing dst is converged like ApplyTableSpec, and
+// its items are overwritten but not removed. Writes made to src during the copy may be missed.
+func (con *dynamodb) CopyTable(ctx context.Context, src, dst string, opts CopyOptions) (CopyResult, error) {
+	spec, err := con.DescribeTableSpec(ctx, src)
+	if err != nil {
+		return CopyResult{}, err
+	}
+	spec.Name = dst
+	result := CopyResult{Spec: spec}
+
+	dest := opts.Destination
+	if dest == nil {
+		dest = con
+	}
+	segments := opts.Segments
+	if segments < 1 {
+		segments = DefaultExportSegments
+	}
+
+	if !opts.DryRun {
+		if err := dest.ApplyTableSpec(ctx, spec); err != nil {
+			return result, err
+		}
+	}
+
+	ctx, cancel := con.scanContext(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		copied   int64
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	flush := func(chunk []map[string]*awsDynamodb.AttributeValue) error {
+		if opts.DryRun {
+			atomic.AddInt64(&copied, int64(len(chunk)))
+			return nil
+		}
+		n, err := dest.BulkPut(ctx, dst, chunk, BulkPutOptions{Concurrency: opts.Concurrency})
+		atomic.AddInt64(&copied, int64(n))
+		return err
+	}
+
+	for i := 0; i < segments; i++ {
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+
+			db := dynamo.NewFromIface(&segmentClient{con.db.Client(), int64(segment), int64(segments)})
+			iter := db.Table(con.tableName(src)).Scan().Iter()
+
+			chunk := make([]map[string]*awsDynamodb.AttributeValue, 0, copyChunk)
+			var item map[string]*awsDynamodb.AttributeValue
+			for iter.NextWithContext(ctx, &item) {
+				chunk = append(chunk, item)
+				item = nil
+				if len(chunk) < copyChunk {
+					continue
+				}
+				if err := flush(chunk); err != nil {
+					fail(err)
+					return
+				}
+				chunk = make([]map[string]*awsDynamodb.AttributeValue, 0, copyChunk)
+			}
+			if err := iter.Err(); err != nil {
+				fail(err)
+				return
+			}
+			if err := flush(chunk); err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	result.Items = int(copied)
+	return result, scanError(ctx, firstErr)
+}
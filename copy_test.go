@@ -0,0 +1,60 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyTable(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	src, dst := "copy-src-"+faker.UUIDDigit(), "copy-dst-"+faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, src, WithIndex{}, CreateTableOptions{
+		OnDemand: true,
+		GSIs: []IndexDefinition{
+			{Name: "Status-index", HashKey: "Status", HashKeyType: DynamodbKeyTypeNumber},
+		},
+		Wait: true,
+	}))
+	defer dynamo.DeleteTableWithContext(ctx, src)
+	defer dynamo.DeleteTableWithContext(ctx, dst)
+
+	items := make([]WithIndex, 120)
+	for i := range items {
+		items[i] = WithIndex{Id: faker.UUIDDigit(), CreatedAt: faker.Date(), Name: faker.Name(), Status: i % 3}
+	}
+	_, err := dynamo.BulkPut(ctx, src, items, BulkPutOptions{})
+	assert.NoError(t, err)
+
+	t.Run("Success: dry run", func(t *testing.T) {
+		result, err := dynamo.CopyTable(ctx, src, dst, CopyOptions{DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, len(items), result.Items)
+		assert.Equal(t, dst, result.Spec.Name)
+		assert.Equal(t, "CreatedAt", result.Spec.RangeKey)
+		assert.Len(t, result.Spec.GSIs, 1)
+
+		exists, err := dynamo.TableExists(ctx, dst)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		result, err := dynamo.CopyTable(ctx, src, dst, CopyOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, len(items), result.Items)
+
+		desc, err := dynamo.DescribeTable(dst)
+		assert.NoError(t, err)
+		assert.True(t, desc.OnDemand)
+		assert.Len(t, desc.GSIs, 1)
+
+		var copied []WithIndex
+		assert.NoError(t, dynamo.Scan(dst, &copied))
+		assert.ElementsMatch(t, items, copied)
+	})
+}
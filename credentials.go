@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// DefaultRoleSessionName names the sessions of DynamodbConfig.AssumeRoleARN unless RoleSessionName is set.
+const DefaultRoleSessionName = "linksports-dynamodb"
+
+// withCredentials returns sess with the credentials of config, or sess when config sets none.
+// Static credentials take precedence over CredentialsProvider; either one, or else the credentials
+// of the session, then assumes AssumeRoleARN.
+func withCredentials(sess *session.Session, config *DynamodbConfig) (*session.Session, error) {
+	var creds *credentials.Credentials
+	switch {
+	case len(config.AccessKeyID) > 0 || len(config.SecretAccessKey) > 0:
+		if len(config.AccessKeyID) < 1 || len(config.SecretAccessKey) < 1 {
+			return nil, errors.New("AccessKeyID and SecretAccessKey must be set together")
+		}
+		creds = credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, config.SessionToken)
+	case config.CredentialsProvider != nil:
+		creds = credentials.NewCredentials(config.CredentialsProvider)
+	}
+	if creds != nil {
+		sess = sess.Copy(aws.NewConfig().WithCredentials(creds))
+	}
+
+	if len(config.AssumeRoleARN) > 0 {
+		creds = stscreds.NewCredentials(sess, config.AssumeRoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if len(config.ExternalID) > 0 {
+				p.ExternalID = aws.String(config.ExternalID)
+			}
+			p.RoleSessionName = config.RoleSessionName
+			if len(p.RoleSessionName) < 1 {
+				p.RoleSessionName = DefaultRoleSessionName
+			}
+		})
+		sess = sess.Copy(aws.NewConfig().WithCredentials(creds))
+	} else if len(config.ExternalID) > 0 {
+		return nil, errors.New("ExternalID requires AssumeRoleARN")
+	}
+	return sess, nil
+}
@@ -0,0 +1,55 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCredentials(t *testing.T) {
+	sess := session.New()
+
+	t.Run("Success: static", func(t *testing.T) {
+		configured, err := withCredentials(sess, &DynamodbConfig{AccessKeyID: "key", SecretAccessKey: "secret"})
+		assert.NoError(t, err)
+
+		value, err := configured.Config.Credentials.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "key", value.AccessKeyID)
+		assert.Equal(t, "secret", value.SecretAccessKey)
+	})
+
+	t.Run("Success: provider", func(t *testing.T) {
+		provider := &credentials.StaticProvider{Value: credentials.Value{AccessKeyID: "provided", SecretAccessKey: "secret"}}
+		configured, err := withCredentials(sess, &DynamodbConfig{CredentialsProvider: provider})
+		assert.NoError(t, err)
+
+		value, err := configured.Config.Credentials.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "provided", value.AccessKeyID)
+	})
+
+	t.Run("Success: assume role", func(t *testing.T) {
+		configured, err := withCredentials(sess, &DynamodbConfig{AssumeRoleARN: "arn:aws:iam::123456789012:role/reader", ExternalID: "external"})
+		assert.NoError(t, err)
+		assert.NotSame(t, sess.Config.Credentials, configured.Config.Credentials)
+	})
+
+	t.Run("Success: session kept", func(t *testing.T) {
+		configured, err := withCredentials(sess, &DynamodbConfig{})
+		assert.NoError(t, err)
+		assert.Same(t, sess, configured)
+	})
+
+	t.Run("Failure: partial static", func(t *testing.T) {
+		_, err := withCredentials(sess, &DynamodbConfig{AccessKeyID: "key"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Failure: external ID without role", func(t *testing.T) {
+		_, err := withCredentials(sess, &DynamodbConfig{ExternalID: "external"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,214 @@
+package dynamodb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/guregu/dynamo"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be used to resume a query,
+// including page keys DynamoDB rejects, e.g. after the key schema changed.
+// Clients should restart pagination from the first page.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type cursorShape struct {
+	HashKey  string           `json:"h"`
+	RangeKey string           `json:"r,omitempty"`
+	Index    string           `json:"i,omitempty"`
+	Operator DynamodbOperator `json:"o"`
+	Order    bool             `json:"a"`
+}
+
+type cursorKey struct {
+	Key string  `json:"k"`
+	S   *string `json:"s,omitempty"`
+	N   *string `json:"n,omitempty"`
+	B   []byte  `json:"b,omitempty"`
+}
+
+type cursorPayload struct {
+	Shape cursorShape `json:"q"`
+	Keys  []cursorKey `json:"k"`
+}
+
+func shapeOf(key DynamodbKey) cursorShape {
+	var shape cursorShape
+	shape.HashKey, _ = key.Hash()
+
+	var option *DynamodbOptions
+	if key.Range != nil {
+		shape.RangeKey, _, option = key.Range()
+	} else if key.LocalSecondaryIndex != nil {
+		var name LocalSecondaryIndexName
+		name, shape.RangeKey, _, option = key.LocalSecondaryIndex()
+		shape.Index = string(name)
+	}
+
+	shape.Operator = DynamodbEqual
+	if option != nil {
+		if op := option.Operator; op != nil {
+			shape.Operator = *op
+		}
+		shape.Order = bool(option.Order.value())
+	} else {
+		shape.Order = true
+	}
+
+	return shape
+}
+
+// EncodeCursor encodes page keys together with the shape of key into an opaque cursor,
+// which DecodeCursor only accepts for a query of the same shape.
+func (con *dynamodb) EncodeCursor(key DynamodbKey, pageKeys []*DynamodbAttributeValue) (string, error) {
+	startKey, err := pagingKey(pageKeys)
+	if err != nil {
+		return "", err
+	}
+
+	payload := cursorPayload{Shape: shapeOf(key)}
+	for _, attr := range pageKeys {
+		if av, ok := startKey[attr.Key]; ok {
+			payload.Keys = append(payload.Keys, cursorKey{Key: attr.Key, S: av.S, N: av.N, B: av.B})
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if len(con.config.CursorSecret) > 0 {
+		if data, err = con.sealCursor(data); err != nil {
+			return "", err
+		}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func (con *dynamodb) DecodeCursor(key DynamodbKey, cursor string) ([]*DynamodbAttributeValue, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if len(con.config.CursorSecret) > 0 {
+		if data, err = con.openCursor(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if payload.Shape != shapeOf(key) {
+		return nil, fmt.Errorf("%w: query shape mismatch", ErrInvalidCursor)
+	}
+
+	pageKeys := []*DynamodbAttributeValue{}
+	for _, k := range payload.Keys {
+		attr := &DynamodbAttributeValue{Key: k.Key}
+		switch {
+		case k.S != nil:
+			attr.Value = *k.S
+		case k.N != nil:
+			if n, err := strconv.ParseInt(*k.N, 10, 64); err == nil {
+				attr.Value = n
+			} else if f, err := strconv.ParseFloat(*k.N, 64); err == nil {
+				attr.Value = f
+			} else {
+				return nil, ErrInvalidCursor
+			}
+		case k.B != nil:
+			attr.Value = k.B
+		default:
+			return nil, ErrInvalidCursor
+		}
+		pageKeys = append(pageKeys, attr)
+	}
+
+	return pageKeys, nil
+}
+
+// pagingKey marshals page keys like dynamo marshals items, so that any key type
+// paginates: strings and times become S, numbers N and byte slices B. Nil values are skipped.
+func pagingKey(pageKeys []*DynamodbAttributeValue) (dynamo.PagingKey, error) {
+	key := dynamo.PagingKey{}
+	for _, attr := range pageKeys {
+		if attr.Value == nil {
+			continue
+		}
+
+		av, err := dynamo.Marshal(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		if av == nil || (av.S == nil && av.N == nil && av.B == nil) {
+			return nil, fmt.Errorf("unsupported page key %s of type %T", attr.Key, attr.Value)
+		}
+		key[attr.Key] = av
+	}
+	return key, nil
+}
+
+func (con *dynamodb) cursorCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(con.config.CursorSecret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (con *dynamodb) sealCursor(data []byte) ([]byte, error) {
+	gcm, err := con.cursorCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func (con *dynamodb) openCursor(data []byte) ([]byte, error) {
+	gcm, err := con.cursorCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidCursor
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return plain, nil
+}
+
+// isInvalidStartKey reports whether DynamoDB rejected the ExclusiveStartKey of a request.
+func isInvalidStartKey(err error) bool {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) || aerr.Code() != "ValidationException" {
+		return false
+	}
+
+	msg := strings.ToLower(aerr.Message())
+	return strings.Contains(msg, "starting key") || strings.Contains(msg, "start key")
+}
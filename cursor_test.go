@@ -0,0 +1,107 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	op := DynamodbGreater
+	order := DynamodbOrderDesc
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), "hash" },
+		Range: func() (string, interface{}, *DynamodbOptions) {
+			return HashAndRange{}.RangeKey(), "range", &DynamodbOptions{Operator: &op, Order: &order}
+		},
+	}
+	pageKeys := []*DynamodbAttributeValue{
+		{Key: HashAndRange{}.HashKey(), Value: "hash"},
+		{Key: HashAndRange{}.RangeKey(), Value: int64(10)},
+	}
+
+	for name, dynamo := range map[string]Dynamodb{
+		"Plain":     newDynamo(t),
+		"Encrypted": newDynamoWithConfig(t, &DynamodbConfig{CursorSecret: []byte("0123456789abcdef")}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cursor, err := dynamo.EncodeCursor(key, pageKeys)
+			assert.NoError(t, err)
+
+			t.Run("Success", func(t *testing.T) {
+				decoded, err := dynamo.DecodeCursor(key, cursor)
+				assert.NoError(t, err)
+				assert.Equal(t, pageKeys, decoded)
+			})
+
+			t.Run("Failure", func(t *testing.T) {
+				t.Run("different order", func(t *testing.T) {
+					asc := DynamodbOrderAsc
+					other := DynamodbKey{
+						Hash: key.Hash,
+						Range: func() (string, interface{}, *DynamodbOptions) {
+							return HashAndRange{}.RangeKey(), "range", &DynamodbOptions{Operator: &op, Order: &asc}
+						},
+					}
+
+					_, err := dynamo.DecodeCursor(other, cursor)
+					assert.True(t, errors.Is(err, ErrInvalidCursor))
+				})
+
+				t.Run("tampered", func(t *testing.T) {
+					_, err := dynamo.DecodeCursor(key, cursor[:len(cursor)-2])
+					assert.True(t, errors.Is(err, ErrInvalidCursor))
+				})
+			})
+		})
+	}
+}
+
+func TestPagingKey(t *testing.T) {
+	at := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		key, err := pagingKey([]*DynamodbAttributeValue{
+			{Key: "Int", Value: 10},
+			{Key: "Int32", Value: int32(-3)},
+			{Key: "Float", Value: 1.5},
+			{Key: "Time", Value: at},
+			{Key: "Bytes", Value: []byte{1, 2}},
+			{Key: "Empty", Value: nil},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "10", *key["Int"].N)
+		assert.Equal(t, "-3", *key["Int32"].N)
+		assert.Equal(t, "1.5", *key["Float"].N)
+		assert.Equal(t, at.Format(time.RFC3339Nano), *key["Time"].S)
+		assert.Equal(t, []byte{1, 2}, key["Bytes"].B)
+		assert.NotContains(t, key, "Empty")
+	})
+
+	t.Run("Failure: not a key type", func(t *testing.T) {
+		_, err := pagingKey([]*DynamodbAttributeValue{{Key: "Bool", Value: true}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		dynamo := newDynamo(t)
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", "hash" },
+		}
+
+		cursor, err := dynamo.EncodeCursor(key, []*DynamodbAttributeValue{
+			{Key: "Float", Value: 1.5},
+			{Key: "Bytes", Value: []byte{1, 2}},
+		})
+		assert.NoError(t, err)
+
+		decoded, err := dynamo.DecodeCursor(key, cursor)
+		assert.NoError(t, err)
+		assert.Equal(t, []*DynamodbAttributeValue{
+			{Key: "Float", Value: 1.5},
+			{Key: "Bytes", Value: []byte{1, 2}},
+		}, decoded)
+	})
+}
@@ -0,0 +1,115 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DeadLetter is an item a background write gave up on.
+type DeadLetter struct {
+	// Source is the method that failed, such as BulkPut or Subscribe.
+	Source string
+	Table  string
+	Item   map[string]*awsDynamodb.AttributeValue
+	Err    error
+	Time   time.Time
+}
+
+// DeadLetterSink records dead letters, set by DynamodbConfig.DeadLetters. When Capture fails,
+// the write fails as it would without a sink.
+type DeadLetterSink interface {
+	Capture(ctx context.Context, letter DeadLetter) error
+}
+
+// DeadLetterFunc is a DeadLetterSink calling the func.
+type DeadLetterFunc func(ctx context.Context, letter DeadLetter) error
+
+func (f DeadLetterFunc) Capture(ctx context.Context, letter DeadLetter) error {
+	return f(ctx, letter)
+}
+
+// NewFileDeadLetters writes every dead letter to w as a JSON line, with the item in DynamoDB JSON.
+func NewFileDeadLetters(w io.Writer) DeadLetterSink {
+	return &fileDeadLetters{w: w}
+}
+
+type fileDeadLetters struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (f *fileDeadLetters) Capture(ctx context.Context, letter DeadLetter) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"Source": letter.Source,
+		"Table":  letter.Table,
+		"Item":   typedItem(letter.Item),
+		"Error":  letter.Err.Error(),
+		"Time":   letter.Time,
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = f.w.Write(append(line, '\n'))
+	return err
+}
+
+// DeadLetterItem is an item of a dead-letter table.
+type DeadLetterItem struct {
+	ID        string                                 `dynamo:"ID,hash"`
+	Source    string                                 `dynamo:"Source"`
+	Table     string                                 `dynamo:"Table"`
+	Item      map[string]*awsDynamodb.AttributeValue `dynamo:"Item"`
+	Error     string                                 `dynamo:"Error"`
+	CreatedAt time.Time                              `dynamo:"CreatedAt"`
+}
+
+// NewTableDeadLetters puts every dead letter into tableName as a DeadLetterItem.
+// The table has the hash key ID, a string. A dead-letter table should not itself have a sink.
+func NewTableDeadLetters(db Dynamodb, tableName string) DeadLetterSink {
+	return &tableDeadLetters{db: db, table: tableName}
+}
+
+type tableDeadLetters struct {
+	db    Dynamodb
+	table string
+}
+
+func (t *tableDeadLetters) Capture(ctx context.Context, letter DeadLetter) error {
+	id, err := UUIDGenerator{}.NewID()
+	if err != nil {
+		return err
+	}
+
+	_, err = t.db.Put(t.table, DeadLetterItem{
+		ID:        id,
+		Source:    letter.Source,
+		Table:     letter.Table,
+		Item:      letter.Item,
+		Error:     letter.Err.Error(),
+		CreatedAt: letter.Time,
+	})
+	return err
+}
+
+// deadLetter captures item with the configured sink. Without one, it returns err unchanged.
+func (con *dynamodb) deadLetter(ctx context.Context, source, tableName string, item map[string]*awsDynamodb.AttributeValue, err error) error {
+	if con.config.DeadLetters == nil {
+		return err
+	}
+
+	return con.config.DeadLetters.Capture(ctx, DeadLetter{
+		Source: source,
+		Table:  tableName,
+		Item:   item,
+		Err:    err,
+		Time:   con.clock().Now(),
+	})
+}
@@ -0,0 +1,66 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDeadLetters(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewFileDeadLetters(&buf)
+
+	assert.NoError(t, sink.Capture(context.Background(), DeadLetter{
+		Source: "BulkPut",
+		Table:  tableNameHashOnly,
+		Item:   map[string]*awsDynamodb.AttributeValue{"ID": {S: aws.String("1")}},
+		Err:    errors.New("failed"),
+	}))
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "BulkPut", line["Source"])
+	assert.Equal(t, "failed", line["Error"])
+	assert.Equal(t, map[string]interface{}{"ID": map[string]interface{}{"S": "1"}}, line["Item"])
+}
+
+func TestBulkPutDeadLetters(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		letters []DeadLetter
+	)
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{
+		DeadLetters: DeadLetterFunc(func(ctx context.Context, letter DeadLetter) error {
+			mu.Lock()
+			defer mu.Unlock()
+			letters = append(letters, letter)
+			return nil
+		}),
+	})
+
+	items := []HashOnly{
+		{Id: faker.UUIDDigit(), Name: faker.Name()},
+		{Id: faker.UUIDDigit(), Name: strings.Repeat("x", 500*1024)},
+		{Id: faker.UUIDDigit(), Name: faker.Name()},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		n, err := dynamo.BulkPut(context.Background(), tableNameHashOnly, items, BulkPutOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		assert.Len(t, letters, 1)
+		assert.Equal(t, "BulkPut", letters[0].Source)
+		assert.Equal(t, items[1].Id, *letters[0].Item["ID"].S)
+		assert.Error(t, letters[0].Err)
+	})
+}
@@ -0,0 +1,123 @@
+package dynamodb
+
+import (
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// DebugRequest is the exact request of one API call, recorded with DynamodbConfig.Debug,
+// such as to see why a query matched nothing. Expressions use the placeholders sent to
+// DynamoDB, which Names and Values resolve.
+type DebugRequest struct {
+	Operation              string
+	Table                  string
+	Index                  string
+	KeyConditionExpression string
+	FilterExpression       string
+	ConditionExpression    string
+	UpdateExpression       string
+	ProjectionExpression   string
+	Names                  map[string]string
+	Values                 map[string]interface{}
+	Time                   time.Time
+	Duration               time.Duration
+	Err                    error
+}
+
+type lastRequest struct {
+	value atomic.Value
+}
+
+// LastRequest returns the last API call made by any goroutine of the client, or nil unless
+// DynamodbConfig.Debug is enabled.
+func (con *dynamodb) LastRequest() *DebugRequest {
+	req, _ := con.lastRequest.value.Load().(*DebugRequest)
+	return req
+}
+
+// recordRequest records op for LastRequest and extends the debug log of the logger with its expressions.
+func (con *dynamodb) recordRequest(op OperationInfo, start time.Time, d time.Duration, err error) *DebugRequest {
+	if !con.config.Debug {
+		return nil
+	}
+
+	req := &DebugRequest{
+		Operation: op.Name,
+		Table:     op.Table,
+		Time:      start,
+		Duration:  d,
+		Err:       err,
+	}
+	in := reflect.ValueOf(op.Input)
+	if in.Kind() == reflect.Ptr && in.Elem().Kind() == reflect.Struct {
+		in = in.Elem()
+		req.Index = stringField(in, "IndexName")
+		req.KeyConditionExpression = stringField(in, "KeyConditionExpression")
+		req.FilterExpression = stringField(in, "FilterExpression")
+		req.ConditionExpression = stringField(in, "ConditionExpression")
+		req.UpdateExpression = stringField(in, "UpdateExpression")
+		req.ProjectionExpression = stringField(in, "ProjectionExpression")
+
+		if names, ok := fieldValue(in, "ExpressionAttributeNames").(map[string]*string); ok && len(names) > 0 {
+			req.Names = aws.StringValueMap(names)
+		}
+		if values, ok := fieldValue(in, "ExpressionAttributeValues").(map[string]*awsDynamodb.AttributeValue); ok && len(values) > 0 {
+			req.Values = make(map[string]interface{}, len(values))
+			for name, av := range values {
+				var v interface{}
+				if err := dynamo.Unmarshal(av, &v); err != nil {
+					v = av
+				}
+				req.Values[name] = v
+			}
+		}
+	}
+
+	con.lastRequest.value.Store(req)
+	return req
+}
+
+// debugArgs are the logger arguments of the expressions of req.
+func (req *DebugRequest) debugArgs() []interface{} {
+	if req == nil {
+		return nil
+	}
+
+	var args []interface{}
+	add := func(key, value string) {
+		if len(value) > 0 {
+			args = append(args, key, value)
+		}
+	}
+	add("index", req.Index)
+	add("keyCondition", req.KeyConditionExpression)
+	add("filter", req.FilterExpression)
+	add("condition", req.ConditionExpression)
+	add("update", req.UpdateExpression)
+	add("projection", req.ProjectionExpression)
+	if len(req.Names) > 0 {
+		args = append(args, "names", req.Names)
+	}
+	if len(req.Values) > 0 {
+		args = append(args, "values", req.Values)
+	}
+	return args
+}
+
+func fieldValue(v reflect.Value, name string) interface{} {
+	field := v.FieldByName(name)
+	if !field.IsValid() {
+		return nil
+	}
+	return field.Interface()
+}
+
+func stringField(v reflect.Value, name string) string {
+	s, _ := fieldValue(v, name).(*string)
+	return aws.StringValue(s)
+}
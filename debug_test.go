@@ -0,0 +1,56 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastRequest(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Debug: true})
+
+		id := faker.UUIDDigit()
+		var items []HashAndRange
+		err := dynamo.Query(tableNameHashAndRange).
+			Hash(HashAndRange{}.HashKey(), id).
+			Filter(FilterAttr("Status", DynamodbEqual, 1)).
+			All(&items)
+		assert.NoError(t, err)
+
+		req := dynamo.LastRequest()
+		if assert.NotNil(t, req) {
+			assert.Equal(t, "Query", req.Operation)
+			assert.Equal(t, tableNameHashAndRange, req.Table)
+			assert.NotEmpty(t, req.KeyConditionExpression)
+			assert.NotEmpty(t, req.FilterExpression)
+			assert.NotEmpty(t, req.Values)
+			assert.NoError(t, req.Err)
+		}
+	})
+
+	t.Run("Success: logged", func(t *testing.T) {
+		logger := &recordedLogger{}
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Debug: true, Logger: logger})
+
+		_, err := dynamo.DeleteIf(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), faker.UUIDDigit() },
+		}, FilterNotExists("ID"))
+		assert.NoError(t, err)
+
+		if assert.Len(t, logger.records, 1) {
+			assert.Contains(t, logger.records[0], "condition")
+		}
+	})
+
+	t.Run("Success: disabled", func(t *testing.T) {
+		dynamo := newDynamo(t)
+
+		var item HashOnly
+		dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), faker.UUIDDigit() },
+		}, &item)
+		assert.Nil(t, dynamo.LastRequest())
+	})
+}
@@ -0,0 +1,62 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// DeleteAllByHash deletes every item of the partition of hashValue, such as all the data of a user,
+// and returns the number of items deleted. The keys are queried a page at a time and deleted in batches
+// of 25 by a single writer, which keeps the purge from starving other traffic of write capacity;
+// throttled deletes are retried with backoff. Items written during the purge may be missed.
+func (con *dynamodb) DeleteAllByHash(ctx context.Context, tableName string, hashKey string, hashValue interface{}) (int, error) {
+	desc, err := con.describeCached(tableName)
+	if err != nil {
+		return 0, err
+	}
+	if hashKey != desc.HashKey {
+		return 0, fmt.Errorf("%s is not the hash key of %s", hashKey, tableName)
+	}
+
+	table := con.table(tableName)
+	keyNames := []string{desc.HashKey}
+	if len(desc.RangeKey) > 0 {
+		keyNames = append(keyNames, desc.RangeKey)
+	}
+
+	deleted := 0
+	flush := func(keys []dynamo.Keyed) error {
+		if len(keys) < 1 {
+			return nil
+		}
+		n, err := table.Batch(keyNames...).Write().Delete(keys...).RunWithContext(ctx)
+		deleted += n
+		return err
+	}
+
+	iter := table.Get(hashKey, hashValue).Project(keyNames...).Iter()
+	keys := make([]dynamo.Keyed, 0, maxBatchWrite)
+	var item map[string]*awsDynamodb.AttributeValue
+	for iter.NextWithContext(ctx, &item) {
+		key := dynamo.Keys{item[desc.HashKey]}
+		if len(desc.RangeKey) > 0 {
+			key[1] = item[desc.RangeKey]
+		}
+		keys = append(keys, key)
+		item = nil
+
+		if len(keys) == maxBatchWrite {
+			if err := flush(keys); err != nil {
+				return deleted, err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+	return deleted, flush(keys)
+}
@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteAllByHash(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	hashKey, other := faker.UUIDDigit(), faker.UUIDDigit()
+	items := make([]HashAndRange, 60)
+	for i := range items {
+		items[i] = HashAndRange{Id: hashKey, CreatedAt: strconv.Itoa(i), Name: faker.Name()}
+	}
+	items = append(items, HashAndRange{Id: other, CreatedAt: "0"})
+	_, err := dynamo.BulkPut(ctx, tableNameHashAndRange, items, BulkPutOptions{})
+	assert.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		deleted, err := dynamo.DeleteAllByHash(ctx, tableNameHashAndRange, "ID", hashKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 60, deleted)
+
+		count, err := dynamo.Count(tableNameHashAndRange, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", hashKey },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		exists, err := dynamo.Exists(tableNameHashAndRange, DynamodbKey{
+			Hash:  func() (string, interface{}) { return "ID", other },
+			Range: func() (string, interface{}, *DynamodbOptions) { return "CreatedAt", "0", nil },
+		})
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Failure: not the hash key", func(t *testing.T) {
+		_, err := dynamo.DeleteAllByHash(ctx, tableNameHashAndRange, "Name", hashKey)
+		assert.Error(t, err)
+	})
+}
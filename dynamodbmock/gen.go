@@ -0,0 +1,167 @@
+//go:build ignore
+// +build ignore
+
+// gen writes mock_gen.go, implementing every method of dynamodb.Dynamodb on Mock.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// outParams are the parameters calls unmarshal results into.
+var outParams = map[string]bool{"result": true, "old": true, "out": true}
+
+func main() {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "..", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var iface *ast.InterfaceType
+	for name, pkg := range pkgs {
+		if name != "dynamodb" {
+			continue
+		}
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == "Dynamodb" {
+					iface, _ = spec.Type.(*ast.InterfaceType)
+				}
+				return iface == nil
+			})
+		}
+	}
+	if iface == nil {
+		log.Fatal("interface Dynamodb not found")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`// Code generated by gen.go; DO NOT EDIT.
+
+package dynamodbmock
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/linksports/dynamodb"
+)
+`)
+	for _, method := range iface.Methods.List {
+		fn := method.Type.(*ast.FuncType)
+		name := method.Names[0].Name
+
+		var params, args []string
+		out := -1
+		i := 0
+		for _, field := range fn.Params.List {
+			typ := typeString(qualify(field.Type))
+			for _, ident := range field.Names {
+				params = append(params, ident.Name+" "+typ)
+				args = append(args, ident.Name)
+				if outParams[ident.Name] {
+					out = i
+				}
+				i++
+			}
+		}
+
+		var results []string
+		if fn.Results != nil {
+			for _, field := range fn.Results.List {
+				typ := typeString(qualify(field.Type))
+				results = append(results, typ)
+				for i := 1; i < len(field.Names); i++ {
+					results = append(results, typ)
+				}
+			}
+		}
+
+		fmt.Fprintf(&b, "\nfunc (m *Mock) %s(%s) ", name, strings.Join(params, ", "))
+		switch len(results) {
+		case 0:
+		case 1:
+			b.WriteString(results[0] + " ")
+		default:
+			b.WriteString("(" + strings.Join(results, ", ") + ") ")
+		}
+		b.WriteString("{\n\tm.t.Helper()\n")
+
+		call := fmt.Sprintf("m.called(%q, %d", name, out)
+		if len(args) > 0 {
+			call += ", " + strings.Join(args, ", ")
+		}
+		call += ")"
+		if len(results) < 1 {
+			fmt.Fprintf(&b, "\t%s\n}\n", call)
+			continue
+		}
+
+		fmt.Fprintf(&b, "\tc := %s\n", call)
+		names := make([]string, len(results))
+		for i, typ := range results {
+			names[i] = fmt.Sprintf("r%d", i)
+			fmt.Fprintf(&b, "\t%s, _ := c.value(%d).(%s)\n", names[i], i, typ)
+		}
+		fmt.Fprintf(&b, "\treturn %s\n}\n", strings.Join(names, ", "))
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("mock_gen.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// qualify prefixes the exported identifiers of package dynamodb.
+func qualify(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("dynamodb"), Sel: ast.NewIdent(t.Name)}
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualify(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(t.Key), Value: qualify(t.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(t.Elt)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: t.Dir, Value: qualify(t.Value)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(t.Params), Results: qualifyFields(t.Results)}
+	}
+	return expr
+}
+
+func qualifyFields(fields *ast.FieldList) *ast.FieldList {
+	if fields == nil {
+		return nil
+	}
+	list := &ast.FieldList{}
+	for _, field := range fields.List {
+		list.List = append(list.List, &ast.Field{Names: field.Names, Type: qualify(field.Type)})
+	}
+	return list
+}
+
+func typeString(expr ast.Expr) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, token.NewFileSet(), expr)
+	return b.String()
+}
@@ -0,0 +1,296 @@
+// Package dynamodbmock provides a mock of dynamodb.Dynamodb with call expectations,
+// for the tests of services depending on the interface:
+//
+//	db := dynamodbmock.New(t)
+//	db.ExpectGet("users", key).Return(User{ID: "1", Name: "Alice"})
+//	db.ExpectPut("users", dynamodbmock.Any).ReturnError(errors.New("throttled"))
+//
+// Calls match the expectations in the order they were added. Unexpected calls and
+// expectations left unmet fail the test.
+package dynamodbmock
+
+//go:generate go run gen.go
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/linksports/dynamodb"
+)
+
+// TestingT is the part of testing.TB used by Mock.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+var _ dynamodb.Dynamodb = (*Mock)(nil)
+
+// Mock implements dynamodb.Dynamodb. Methods without a matching expectation
+// report an error and return zero values.
+type Mock struct {
+	t TestingT
+
+	mu       sync.Mutex
+	expected []*Call
+}
+
+// New returns a mock whose expectations are asserted when the test ends.
+func New(t TestingT) *Mock {
+	m := &Mock{t: t}
+	t.Cleanup(m.AssertExpectations)
+	return m
+}
+
+type anything struct{}
+
+func (anything) String() string { return "Any" }
+
+// Any matches every argument.
+var Any = anything{}
+
+// Call is an expected call.
+type Call struct {
+	method  string
+	args    []interface{}
+	values  []interface{}
+	fill    interface{}
+	hasFill bool
+	times   int
+	calls   int
+}
+
+// Expect adds an expectation of method called with args, compared with reflect.DeepEqual.
+// Keys are compared by the values they return, and missing trailing args match anything.
+// The call is expected once. The ExpectXxx helpers take Any or a value for each argument.
+func (m *Mock) Expect(method string, args ...interface{}) *Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := &Call{method: method, args: args, times: 1}
+	m.expected = append(m.expected, c)
+	return c
+}
+
+func (m *Mock) ExpectGet(tableName string, key interface{}) *Call {
+	return m.Expect("Get", tableName, key)
+}
+
+func (m *Mock) ExpectGetAll(tableName string, key interface{}) *Call {
+	return m.Expect("GetAll", tableName, key)
+}
+
+func (m *Mock) ExpectBatchGet(tableName string, keys interface{}) *Call {
+	return m.Expect("BatchGet", tableName, keys)
+}
+
+func (m *Mock) ExpectCount(tableName string, key interface{}) *Call {
+	return m.Expect("Count", tableName, key)
+}
+
+func (m *Mock) ExpectPut(tableName string, item interface{}) *Call {
+	return m.Expect("Put", tableName, item)
+}
+
+func (m *Mock) ExpectDelete(tableName string, key interface{}) *Call {
+	return m.Expect("Delete", tableName, key)
+}
+
+func (m *Mock) ExpectScan(tableName string) *Call {
+	return m.Expect("Scan", tableName)
+}
+
+// Return sets what the call returns. Methods unmarshaling into an argument, such as the result
+// of Get or the old value of PutWithOldValue, take the value to unmarshal first, then their
+// return values. Return values left out are zero.
+func (c *Call) Return(values ...interface{}) *Call {
+	c.values = values
+	return c
+}
+
+// ReturnError makes the call return err as its last value.
+func (c *Call) ReturnError(err error) *Call {
+	c.values = []interface{}{errorReturn{err}}
+	return c
+}
+
+type errorReturn struct {
+	err error
+}
+
+// Times expects the call n times. A negative n allows any number of calls, including none.
+func (c *Call) Times(n int) *Call {
+	c.times = n
+	return c
+}
+
+// AssertExpectations reports the expected calls not made.
+func (m *Mock) AssertExpectations() {
+	m.t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.expected {
+		if c.times >= 0 && c.calls < c.times {
+			m.t.Errorf("dynamodbmock: expected %s%v %d times, called %d times", c.method, c.args, c.times, c.calls)
+		}
+	}
+}
+
+// result is the outcome of a mocked call.
+type result struct {
+	values []interface{}
+	err    error
+	n      int
+}
+
+// value returns return value i, or nil.
+func (r *result) value(i int) interface{} {
+	if r.err != nil && i == r.n-1 {
+		return r.err
+	}
+	if i < len(r.values) {
+		return r.values[i]
+	}
+	return nil
+}
+
+// called matches a call made with args. out is the index of the argument to unmarshal into, or -1.
+func (m *Mock) called(method string, out int, args ...interface{}) *result {
+	m.t.Helper()
+	c := m.match(method, args)
+	if c == nil {
+		m.t.Errorf("dynamodbmock: unexpected call %s%v", method, args)
+		return &result{}
+	}
+
+	values := c.values
+	if len(values) == 1 {
+		if e, ok := values[0].(errorReturn); ok {
+			return &result{err: e.err, n: returns(method)}
+		}
+	}
+	if out >= 0 && len(values) > 0 {
+		if err := fill(args[out], values[0]); err != nil {
+			m.t.Errorf("dynamodbmock: %s: %v", method, err)
+		}
+		values = values[1:]
+	}
+	return &result{values: values}
+}
+
+func (m *Mock) match(method string, args []interface{}) *Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.expected {
+		if c.method != method || (c.times >= 0 && c.calls >= c.times) {
+			continue
+		}
+		if matchArgs(c.args, args) {
+			c.calls++
+			return c
+		}
+	}
+	return nil
+}
+
+// returns is the number of values method returns.
+func returns(method string) int {
+	m, ok := reflect.TypeOf((*dynamodb.Dynamodb)(nil)).Elem().MethodByName(method)
+	if !ok {
+		return 0
+	}
+	return m.Type.NumOut()
+}
+
+func matchArgs(expected, actual []interface{}) bool {
+	if len(expected) > len(actual) {
+		return false
+	}
+	for i, e := range expected {
+		if !matchArg(e, actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchArg(expected, actual interface{}) bool {
+	if _, ok := expected.(anything); ok {
+		return true
+	}
+
+	switch e := expected.(type) {
+	case dynamodb.DynamodbKey:
+		a, ok := actual.(dynamodb.DynamodbKey)
+		return ok && reflect.DeepEqual(keyValues(e), keyValues(a))
+	case *dynamodb.DynamodbKey:
+		a, ok := actual.(*dynamodb.DynamodbKey)
+		return ok && (e == a || e != nil && a != nil && reflect.DeepEqual(keyValues(*e), keyValues(*a)))
+	case []*dynamodb.DynamodbKey:
+		a, ok := actual.([]*dynamodb.DynamodbKey)
+		if !ok || len(e) != len(a) {
+			return false
+		}
+		for i := range e {
+			if !matchArg(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// items are compared by value, whether passed as a struct or a pointer
+	ev, av := reflect.ValueOf(expected), reflect.ValueOf(actual)
+	if ev.Kind() == reflect.Ptr && av.Kind() != reflect.Ptr && !ev.IsNil() {
+		ev = ev.Elem()
+	}
+	if av.Kind() == reflect.Ptr && ev.Kind() != reflect.Ptr && !av.IsNil() {
+		av = av.Elem()
+	}
+	if !ev.IsValid() || !av.IsValid() {
+		return ev.IsValid() == av.IsValid()
+	}
+	return reflect.DeepEqual(ev.Interface(), av.Interface())
+}
+
+// keyValues evaluates the functions of key for comparison.
+func keyValues(key dynamodb.DynamodbKey) []interface{} {
+	var values []interface{}
+	if key.Hash != nil {
+		name, value := key.Hash()
+		values = append(values, name, value)
+	}
+	if key.Range != nil {
+		name, value, options := key.Range()
+		values = append(values, name, value, options)
+	}
+	if key.LocalSecondaryIndex != nil {
+		index, name, value, options := key.LocalSecondaryIndex()
+		values = append(values, index, name, value, options)
+	}
+	return values
+}
+
+// fill stores value into out, which must be a pointer.
+func fill(out, value interface{}) error {
+	ov := reflect.ValueOf(out)
+	if ov.Kind() != reflect.Ptr || ov.IsNil() {
+		return fmt.Errorf("cannot return into %T", out)
+	}
+
+	vv := reflect.ValueOf(value)
+	target := ov.Elem()
+	if vv.Type().AssignableTo(target.Type()) {
+		target.Set(vv)
+		return nil
+	}
+	if vv.Kind() == reflect.Ptr && vv.Elem().Type().AssignableTo(target.Type()) {
+		target.Set(vv.Elem())
+		return nil
+	}
+	return fmt.Errorf("cannot return %T into %T", value, out)
+}
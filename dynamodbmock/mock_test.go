@@ -0,0 +1,88 @@
+package dynamodbmock
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	errors  []string
+	cleanup []func()
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Cleanup(f func()) {
+	r.cleanup = append(r.cleanup, f)
+}
+
+func (r *recorder) finish() {
+	for _, f := range r.cleanup {
+		f()
+	}
+}
+
+type User struct {
+	ID   string `dynamo:"ID,hash"`
+	Name string
+}
+
+func userKey(id string) dynamodb.DynamodbKey {
+	return dynamodb.DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", id },
+	}
+}
+
+func TestMock(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		r := &recorder{}
+		db := New(r)
+		db.ExpectGet("users", userKey("1")).Return(User{ID: "1", Name: "Alice"})
+		db.ExpectPut("users", User{ID: "2"}).ReturnError(errors.New("throttled"))
+		db.ExpectCount("users", Any).Return(int64(3)).Times(2)
+
+		var user User
+		assert.NoError(t, db.Get("users", userKey("1"), &user))
+		assert.Equal(t, User{ID: "1", Name: "Alice"}, user)
+
+		res, err := db.Put("users", &User{ID: "2"})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "throttled")
+
+		for i := 0; i < 2; i++ {
+			count, err := db.Count("users", userKey("x"))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), count)
+		}
+
+		r.finish()
+		assert.Empty(t, r.errors)
+	})
+
+	t.Run("Failure: unexpected call", func(t *testing.T) {
+		r := &recorder{}
+		db := New(r)
+		db.ExpectGet("users", userKey("1"))
+
+		var user User
+		assert.NoError(t, db.Get("users", userKey("2"), &user))
+		assert.Len(t, r.errors, 1)
+	})
+
+	t.Run("Failure: unmet expectation", func(t *testing.T) {
+		r := &recorder{}
+		db := New(r)
+		db.ExpectDelete("users", userKey("1"))
+
+		r.finish()
+		assert.Len(t, r.errors, 1)
+	})
+}
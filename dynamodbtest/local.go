@@ -0,0 +1,120 @@
+// Package dynamodbtest connects tests to DynamoDB Local, such as the one of docker-compose.yml:
+//
+//	func TestUsers(t *testing.T) {
+//		db := dynamodbtest.NewLocal(t, dynamodbtest.Table{Name: "users", Entity: User{}})
+//		_, err := db.Put("users", User{ID: "1"})
+//		...
+//	}
+//
+// Every client gets its own table prefix, so tests can run in parallel against the same table
+// names, and its tables are deleted when the test ends. Tests are skipped when DynamoDB Local is
+// not running.
+package dynamodbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/linksports/dynamodb"
+)
+
+// EndpointEnv overrides DefaultEndpoint, such as for a DynamoDB Local started by CI on another host.
+const EndpointEnv = "DYNAMODB_ENDPOINT"
+
+// DefaultEndpoint is the endpoint of DynamoDB Local on its default port.
+const DefaultEndpoint = "http://localhost:8000"
+
+// pingTimeout bounds the detection of DynamoDB Local.
+const pingTimeout = 2 * time.Second
+
+// Table is created by NewLocal from the struct tags of Entity.
+type Table struct {
+	Name    string
+	Entity  interface{}
+	Options dynamodb.CreateTableOptions
+}
+
+// Endpoint returns the endpoint of DynamoDB Local.
+func Endpoint() string {
+	if endpoint := os.Getenv(EndpointEnv); len(endpoint) > 0 {
+		return endpoint
+	}
+	return DefaultEndpoint
+}
+
+// NewLocal returns a client of DynamoDB Local with tables created, on demand, and deletes every
+// table of the client when the test ends. It skips the test when DynamoDB Local is unreachable.
+func NewLocal(t testing.TB, tables ...Table) dynamodb.Dynamodb {
+	t.Helper()
+	return NewLocalWithConfig(t, &dynamodb.DynamodbConfig{}, tables...)
+}
+
+// NewLocalWithConfig is NewLocal with the options of config. Its endpoint, region, credentials and
+// table prefix are set by NewLocalWithConfig.
+func NewLocalWithConfig(t testing.TB, config *dynamodb.DynamodbConfig, tables ...Table) dynamodb.Dynamodb {
+	t.Helper()
+
+	prefix, err := randomPrefix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Endpoint = Endpoint()
+	config.Region = "us-east-1"
+	// DynamoDB Local accepts any credentials.
+	config.AccessKeyID, config.SecretAccessKey = "local", "local"
+	config.TablePrefix = prefix
+
+	db, err := dynamodb.New(session.New(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := db.Ping(ctx); err != nil {
+		t.Skipf("DynamoDB Local unreachable at %s, start it with docker-compose up -d: %v", config.Endpoint, err)
+	}
+
+	t.Cleanup(func() { deleteTables(t, db) })
+
+	ctx = context.Background()
+	for _, table := range tables {
+		options := table.Options
+		if !options.OnDemand && options.ReadUnits < 1 && options.WriteUnits < 1 {
+			options.OnDemand = true
+		}
+		options.Wait = true
+		if err := db.CreateTableWithContext(ctx, table.Name, table.Entity, options); err != nil {
+			t.Fatalf("create table %s: %v", table.Name, err)
+		}
+	}
+	return db
+}
+
+// deleteTables deletes the tables of the prefix of db, including those created by the test.
+func deleteTables(t testing.TB, db dynamodb.Dynamodb) {
+	ctx := context.Background()
+	names, err := db.ListTables(ctx)
+	if err != nil {
+		t.Errorf("list tables: %v", err)
+		return
+	}
+	for _, name := range names {
+		if err := db.DeleteTableIfExists(ctx, name, dynamodb.DeleteTableOptions{}); err != nil {
+			t.Errorf("delete table %s: %v", name, err)
+		}
+	}
+}
+
+func randomPrefix() (string, error) {
+	var b [6]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "test-" + hex.EncodeToString(b[:]) + "-", nil
+}
@@ -0,0 +1,42 @@
+package dynamodbtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   string `dynamo:"ID,hash"`
+	Name string `dynamo:"Name"`
+}
+
+func TestNewLocal(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db := NewLocal(t, Table{Name: "users", Entity: user{}})
+
+		_, err := db.Put("users", user{ID: "1", Name: "Alice"})
+		assert.NoError(t, err)
+
+		var got user
+		assert.NoError(t, db.Get("users", dynamodb.DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", "1" },
+		}, &got))
+		assert.Equal(t, "Alice", got.Name)
+	})
+
+	t.Run("Success: isolated", func(t *testing.T) {
+		first := NewLocal(t, Table{Name: "users", Entity: user{}})
+		second := NewLocal(t)
+
+		exists, err := first.TableExists(context.Background(), "users")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = second.TableExists(context.Background(), "users")
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
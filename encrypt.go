@@ -0,0 +1,226 @@
+package dynamodb
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// ErrNoKeyProvider is returned when an item has encrypted fields but DynamodbConfig.KeyProvider is unset.
+var ErrNoKeyProvider = errors.New("no key provider")
+
+// Attributes of an encrypted field, which is stored as a map.
+const (
+	encryptedKeyID = "KeyID"
+	encryptedData  = "Data"
+)
+
+// KeyProvider supplies the AES keys of fields tagged `dynamo:"Name,encrypted"`, which Put encrypts
+// with AES-GCM and Get, GetAll and Scan decrypt. The ID of the key is stored with every value, so
+// values encrypted before a rotation stay readable as long as Key returns their key.
+type KeyProvider interface {
+	// CurrentKey returns the key encrypting new values, of 16, 24 or 32 bytes.
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key returns the key of keyID.
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider holds its keys in memory, such as keys loaded from a secret store at startup.
+type StaticKeyProvider struct {
+	// Current is the ID of the key encrypting new values.
+	Current string
+	Keys    map[string][]byte
+}
+
+// CurrentKey :
+func (p StaticKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	key, err := p.Key(ctx, p.Current)
+	return p.Current, key, err
+}
+
+// Key :
+func (p StaticKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	key, ok := p.Keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	return key, nil
+}
+
+// KMSKeyProvider encrypts with a data key generated by AWS KMS under a customer master key.
+// The key ID stored with the values is the data key encrypted by KMS, so every data key ever used
+// can be decrypted again while the master key exists. A new data key is generated per provider.
+type KMSKeyProvider struct {
+	client    kmsiface.KMSAPI
+	masterKey string
+
+	mu      sync.Mutex
+	current string
+	keys    sync.Map
+}
+
+// NewKMSKeyProvider uses masterKey, the ID, ARN or alias of a KMS key.
+func NewKMSKeyProvider(client kmsiface.KMSAPI, masterKey string) *KMSKeyProvider {
+	return &KMSKeyProvider{client: client, masterKey: masterKey}
+}
+
+// CurrentKey :
+func (p *KMSKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.current) < 1 {
+		out, err := p.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+			KeyId:   aws.String(p.masterKey),
+			KeySpec: aws.String(kms.DataKeySpecAes256),
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		p.current = base64.StdEncoding.EncodeToString(out.CiphertextBlob)
+		p.keys.Store(p.current, out.Plaintext)
+	}
+	key, _ := p.keys.Load(p.current)
+	return p.current, key.([]byte), nil
+}
+
+// Key decrypts the data key of keyID with KMS, once per key.
+func (p *KMSKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	if key, ok := p.keys.Load(keyID); ok {
+		return key.([]byte), nil
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", keyID, err)
+	}
+	out, err := p.client.DecryptWithContext(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return nil, err
+	}
+	p.keys.Store(keyID, out.Plaintext)
+	return out.Plaintext, nil
+}
+
+func (con *dynamodb) keyProvider(name string) (KeyProvider, error) {
+	if con.config.KeyProvider == nil {
+		return nil, fmt.Errorf("encrypt %s: %w", name, ErrNoKeyProvider)
+	}
+	return con.config.KeyProvider, nil
+}
+
+// encryptFields replaces the encrypted fields of av, an item of tableName, by their encrypted codec value.
+// The table, the primary key of the item and the attribute name are authenticated, so values cannot be
+// swapped between attributes, items or tables.
+func (con *dynamodb) encryptFields(tableName string, av map[string]*awsDynamodb.AttributeValue, fields []taggedField) error {
+	for _, field := range fields {
+		if field.value.IsZero() {
+			delete(av, field.name)
+			continue
+		}
+
+		provider, err := con.keyProvider(field.name)
+		if err != nil {
+			return err
+		}
+		keyID, key, err := provider.CurrentKey(context.Background())
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", field.name, err)
+		}
+		gcm, err := fieldCipher(key)
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", field.name, err)
+		}
+
+		aad, err := con.fieldContext(tableName, av, field.name)
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", field.name, err)
+		}
+		plain, err := con.codec().Marshal(field.value.Interface())
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", field.name, err)
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return fmt.Errorf("encrypt %s: %w", field.name, err)
+		}
+
+		av[field.name] = &awsDynamodb.AttributeValue{M: map[string]*awsDynamodb.AttributeValue{
+			encryptedKeyID: {S: aws.String(keyID)},
+			encryptedData:  {B: gcm.Seal(nonce, nonce, plain, aad)},
+		}}
+	}
+	return nil
+}
+
+// decryptField decrypts the attribute of field in av, an item of tableName written by encryptFields, into field.
+func (con *dynamodb) decryptField(tableName string, av map[string]*awsDynamodb.AttributeValue, field taggedField) error {
+	value := av[field.name]
+	keyID, data := value.M[encryptedKeyID], value.M[encryptedData]
+	if keyID == nil || keyID.S == nil || data == nil || data.B == nil {
+		return fmt.Errorf("decrypt %s: not an encrypted attribute", field.name)
+	}
+
+	provider, err := con.keyProvider(field.name)
+	if err != nil {
+		return err
+	}
+	key, err := provider.Key(context.Background(), *keyID.S)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.name, err)
+	}
+	gcm, err := fieldCipher(key)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.name, err)
+	}
+
+	aad, err := con.fieldContext(tableName, av, field.name)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.name, err)
+	}
+	if len(data.B) < gcm.NonceSize() {
+		return fmt.Errorf("decrypt %s: ciphertext too short", field.name)
+	}
+	plain, err := gcm.Open(nil, data.B[:gcm.NonceSize()], data.B[gcm.NonceSize():], aad)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.name, err)
+	}
+	if err := con.codec().Unmarshal(plain, field.value.Addr().Interface()); err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.name, err)
+	}
+	return nil
+}
+
+// fieldContext is the additional data authenticated with the attribute name of an item of tableName:
+// the table and the marshaled primary key of av, as the table describes it, followed by name.
+func (con *dynamodb) fieldContext(tableName string, av map[string]*awsDynamodb.AttributeValue, name string) ([]byte, error) {
+	desc, err := con.describeCached(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := map[string]*awsDynamodb.AttributeValue{desc.HashKey: av[desc.HashKey]}
+	if len(desc.RangeKey) > 0 {
+		key[desc.RangeKey] = av[desc.RangeKey]
+	}
+	return []byte(cacheKey(tableName, key) + "\x00" + name), nil
+}
+
+func fieldCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,269 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type EncryptedItem struct {
+	Id    string `dynamo:"ID,hash"`
+	Name  string `dynamo:"Name"`
+	Email string `dynamo:"Email,encrypted"`
+}
+
+type EncryptedEntry struct {
+	Id        string `dynamo:"ID,hash"`
+	CreatedAt string `dynamo:"CreatedAt,range"`
+	Email     string `dynamo:"Email,encrypted"`
+}
+
+func TestEncryptedFields(t *testing.T) {
+	keys := map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	}
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{KeyProvider: StaticKeyProvider{Current: "k1", Keys: keys}})
+
+	item := EncryptedItem{Id: faker.UUIDDigit(), Name: faker.Name(), Email: faker.Email()}
+	_, err := dynamo.Put(tableNameHashOnly, item)
+	assert.NoError(t, err)
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var raw map[string]interface{}
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &raw))
+		if assert.IsType(t, map[string]interface{}{}, raw["Email"]) {
+			assert.Equal(t, "k1", raw["Email"].(map[string]interface{})["KeyID"])
+		}
+
+		var got EncryptedItem
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &got))
+		assert.Equal(t, item, got)
+
+		var all []EncryptedItem
+		assert.NoError(t, dynamo.Scan(tableNameHashOnly, &all, FilterAttr("ID", DynamodbEqual, item.Id)))
+		assert.Equal(t, []EncryptedItem{item}, all)
+	})
+
+	t.Run("Success: PutIdempotent", func(t *testing.T) {
+		item := EncryptedItem{Id: faker.UUIDDigit(), Name: faker.Name(), Email: faker.Email()}
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+		}
+		_, err := dynamo.PutIdempotent(tableNameHashOnly, item, faker.UUIDDigit(), time.Hour)
+		assert.NoError(t, err)
+
+		var raw map[string]interface{}
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &raw))
+		assert.IsType(t, map[string]interface{}{}, raw["Email"])
+
+		var got EncryptedItem
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &got))
+		assert.Equal(t, item, got)
+	})
+
+	t.Run("Success: read paths", func(t *testing.T) {
+		id := faker.UUIDDigit()
+		testReadPaths(t, dynamo, []EncryptedEntry{
+			{Id: id, CreatedAt: "1", Email: faker.Email()},
+			{Id: id, CreatedAt: "2", Email: faker.Email()},
+		})
+	})
+
+	t.Run("Success: rotated", func(t *testing.T) {
+		rotated := newDynamoWithConfig(t, &DynamodbConfig{KeyProvider: StaticKeyProvider{Current: "k2", Keys: keys}})
+
+		var got EncryptedItem
+		assert.NoError(t, rotated.Get(tableNameHashOnly, key, &got))
+		assert.Equal(t, item, got)
+	})
+
+	t.Run("Failure: swapped ciphertext", func(t *testing.T) {
+		var raw map[string]*awsDynamodb.AttributeValue
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &raw))
+
+		other := EncryptedItem{Id: faker.UUIDDigit(), Name: faker.Name(), Email: faker.Email()}
+		_, err := dynamo.Put(tableNameHashOnly, other)
+		assert.NoError(t, err)
+		otherKey := DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), other.Id },
+		}
+		var swapped map[string]*awsDynamodb.AttributeValue
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, otherKey, &swapped))
+		swapped["Email"] = raw["Email"]
+		_, err = dynamo.Put(tableNameHashOnly, swapped)
+		assert.NoError(t, err)
+
+		var got EncryptedItem
+		assert.Error(t, dynamo.Get(tableNameHashOnly, otherKey, &got))
+	})
+
+	t.Run("Failure: no key provider", func(t *testing.T) {
+		plain := newDynamo(t)
+		_, err := plain.Put(tableNameHashOnly, EncryptedItem{Id: faker.UUIDDigit(), Email: faker.Email()})
+		assert.True(t, errors.Is(err, ErrNoKeyProvider))
+
+		var got EncryptedItem
+		assert.True(t, errors.Is(plain.Get(tableNameHashOnly, key, &got), ErrNoKeyProvider))
+	})
+}
+
+// testReadPaths puts want, a slice of items of one hash key of tableNameHashAndRange in range key order,
+// and checks that every read path other than Get, GetAll and Scan returns them decoded.
+func testReadPaths(t *testing.T, db Dynamodb, want interface{}) {
+	items := reflect.ValueOf(want)
+	elem := items.Type().Elem()
+	for i := 0; i < items.Len(); i++ {
+		if _, err := db.Put(tableNameHashAndRange, items.Index(i).Interface()); !assert.NoError(t, err) {
+			t.FailNow()
+		}
+	}
+
+	id := items.Index(0).FieldByName("Id").Interface()
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), id },
+	}
+	itemKey := func(i int) DynamodbKey {
+		createdAt := items.Index(i).FieldByName("CreatedAt").Interface()
+		return DynamodbKey{
+			Hash:  key.Hash,
+			Range: func() (string, interface{}, *DynamodbOptions) { return HashAndRange{}.RangeKey(), createdAt, nil },
+		}
+	}
+	filter := FilterAttr("ID", DynamodbEqual, id)
+	collect := func(t *testing.T, iter DynamodbIter) interface{} {
+		got := reflect.MakeSlice(items.Type(), 0, items.Len())
+		for {
+			item := reflect.New(elem)
+			if !iter.Next(context.Background(), item.Interface()) {
+				break
+			}
+			got = reflect.Append(got, item.Elem())
+		}
+		assert.NoError(t, iter.Err())
+		return got.Interface()
+	}
+
+	t.Run("Paging", func(t *testing.T) {
+		got := reflect.New(items.Type())
+		assert.NoError(t, db.Paging(tableNameHashAndRange, key, DynamodbPaged{Limit: items.Len()}, got.Interface()))
+		assert.Equal(t, want, got.Elem().Interface())
+	})
+
+	t.Run("QueryIter", func(t *testing.T) {
+		assert.Equal(t, want, collect(t, db.QueryIter(tableNameHashAndRange, key)))
+	})
+
+	t.Run("GetAllStream", func(t *testing.T) {
+		assert.Equal(t, want, collect(t, db.GetAllStream(tableNameHashAndRange, key, 1)))
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		assert.Equal(t, want, collect(t, db.Query(tableNameHashAndRange).Hash(HashAndRange{}.HashKey(), id).Iter()))
+	})
+
+	t.Run("ScanIter", func(t *testing.T) {
+		assert.Equal(t, want, collect(t, db.ScanIter(tableNameHashAndRange, filter)))
+	})
+
+	t.Run("ScanParallel", func(t *testing.T) {
+		got := reflect.New(items.Type())
+		assert.NoError(t, db.ScanParallel(tableNameHashAndRange, 2, got.Interface(), filter))
+		assert.ElementsMatch(t, want, got.Elem().Interface())
+	})
+
+	t.Run("ScanPage", func(t *testing.T) {
+		got := reflect.MakeSlice(items.Type(), 0, items.Len())
+		var token string
+		for {
+			page := reflect.New(items.Type())
+			next, err := db.ScanPage(context.Background(), tableNameHashAndRange, page.Interface(), ScanPageOptions{StartFrom: token}, filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got = reflect.AppendSlice(got, page.Elem())
+			if token = next; len(token) < 1 {
+				break
+			}
+		}
+		assert.ElementsMatch(t, want, got.Interface())
+	})
+
+	t.Run("BatchGetEntities", func(t *testing.T) {
+		keys := make([]*DynamodbKey, items.Len())
+		for i := range keys {
+			key := itemKey(i)
+			keys[i] = &key
+		}
+		// The items have no Type attribute, so they are all read as the empty type.
+		got, err := db.BatchGetEntities(tableNameHashAndRange, keys, "Type", map[string]interface{}{"": reflect.Zero(elem).Interface()})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, want, got[""])
+	})
+
+	t.Run("PutWithOldValue", func(t *testing.T) {
+		old := reflect.New(elem)
+		_, err := db.PutWithOldValue(tableNameHashAndRange, items.Index(0).Interface(), old.Interface())
+		assert.NoError(t, err)
+		assert.Equal(t, items.Index(0).Interface(), old.Elem().Interface())
+	})
+
+	t.Run("DeleteWithOldValue", func(t *testing.T) {
+		for i := 0; i < items.Len(); i++ {
+			old := reflect.New(elem)
+			_, err := db.DeleteWithOldValue(tableNameHashAndRange, itemKey(i), old.Interface())
+			assert.NoError(t, err)
+			assert.Equal(t, items.Index(i).Interface(), old.Elem().Interface())
+		}
+	})
+}
+
+type fakeKMS struct {
+	kmsiface.KMSAPI
+	generated int
+}
+
+func (f *fakeKMS) GenerateDataKeyWithContext(ctx aws.Context, in *kms.GenerateDataKeyInput, opts ...request.Option) (*kms.GenerateDataKeyOutput, error) {
+	f.generated++
+	return &kms.GenerateDataKeyOutput{CiphertextBlob: []byte("sealed"), Plaintext: bytes.Repeat([]byte{3}, 32)}, nil
+}
+
+func (f *fakeKMS) DecryptWithContext(ctx aws.Context, in *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	if string(in.CiphertextBlob) != "sealed" {
+		return nil, errors.New("invalid ciphertext")
+	}
+	return &kms.DecryptOutput{Plaintext: bytes.Repeat([]byte{3}, 32)}, nil
+}
+
+func TestKMSKeyProvider(t *testing.T) {
+	client := &fakeKMS{}
+	provider := NewKMSKeyProvider(client, "alias/pii")
+	ctx := context.Background()
+
+	keyID, key, err := provider.CurrentKey(ctx)
+	assert.NoError(t, err)
+	_, _, err = provider.CurrentKey(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.generated)
+
+	got, err := NewKMSKeyProvider(client, "alias/pii").Key(ctx, keyID)
+	assert.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	_, err = provider.Key(ctx, "not base64!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// EntityItems holds BatchGetEntities results by entity type.
+// Each value is a slice of the struct registered for the type, e.g. items["User"].([]User).
+type EntityItems map[string]interface{}
+
+// Get stores the items of entityType in dst, a pointer to a slice of the struct registered for it.
+// dst is left unchanged when no item of entityType was read.
+func (items EntityItems) Get(entityType string, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dst must be a pointer to a slice, got %T", dst)
+	}
+
+	slice, ok := items[entityType]
+	if !ok {
+		return nil
+	}
+	value := reflect.ValueOf(slice)
+	if value.Type() != rv.Elem().Type() {
+		return fmt.Errorf("items of %q are %T, not %s", entityType, slice, rv.Elem().Type())
+	}
+	rv.Elem().Set(value)
+	return nil
+}
+
+// BatchGetEntities is BatchGet for tables holding several entity types.
+// Each item is unmarshaled into the struct registered in entities for the value of its typeAttribute.
+// An UnprocessedError is returned with the items of the other keys.
+func (con *dynamodb) BatchGetEntities(tableName string, keys []*DynamodbKey, typeAttribute string, entities map[string]interface{}) (EntityItems, error) {
+	var raw []map[string]*awsDynamodb.AttributeValue
+	err := con.BatchGet(tableName, keys, &raw)
+	var unprocessed *UnprocessedError
+	if err != nil && !errors.As(err, &unprocessed) {
+		return nil, err
+	}
+
+	slices := map[string]reflect.Value{}
+	for _, item := range raw {
+		var entityType string
+		if av := item[typeAttribute]; av != nil && av.S != nil {
+			entityType = *av.S
+		}
+
+		entity, ok := entities[entityType]
+		if !ok {
+			return nil, fmt.Errorf("unknown entity type %q", entityType)
+		}
+		elemType := reflect.Indirect(reflect.ValueOf(entity)).Type()
+
+		elem := reflect.New(elemType)
+		if err := con.decodeItem(tableName, item, elem.Interface()); err != nil {
+			return nil, err
+		}
+
+		slice, ok := slices[entityType]
+		if !ok {
+			slice = reflect.MakeSlice(reflect.SliceOf(elemType), 0, 1)
+		}
+		slices[entityType] = reflect.Append(slice, elem.Elem())
+	}
+
+	result := EntityItems{}
+	for entityType, slice := range slices {
+		result[entityType] = slice.Interface()
+	}
+	return result, err
+}
@@ -0,0 +1,94 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type EntityUser struct {
+	Id        string `dynamo:"ID,hash"`
+	CreatedAt string `dynamo:"CreatedAt,range"`
+	Type      string `dynamo:"Type"`
+	Name      string `dynamo:"Name"`
+}
+
+type EntityOrder struct {
+	Id        string `dynamo:"ID,hash"`
+	CreatedAt string `dynamo:"CreatedAt,range"`
+	Type      string `dynamo:"Type"`
+	Status    int    `dynamo:"Status"`
+}
+
+func TestBatchGetEntities(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	id := faker.UUIDDigit()
+	user := EntityUser{Id: id, CreatedAt: "PROFILE", Type: "User", Name: faker.Name()}
+	orders := []EntityOrder{
+		{Id: id, CreatedAt: "ORDER#1", Type: "Order", Status: 1},
+		{Id: id, CreatedAt: "ORDER#2", Type: "Order", Status: 2},
+	}
+	dynamo.Put(tableNameHashAndRange, &user)
+	for i := range orders {
+		dynamo.Put(tableNameHashAndRange, &orders[i])
+	}
+
+	keys := []*DynamodbKey{}
+	for _, rangeValue := range []string{user.CreatedAt, orders[0].CreatedAt, orders[1].CreatedAt} {
+		rangeValue := rangeValue
+		keys = append(keys, &DynamodbKey{
+			Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), id },
+			Range: func() (string, interface{}, *DynamodbOptions) {
+				return HashAndRange{}.RangeKey(), rangeValue, nil
+			},
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		items, err := dynamo.BatchGetEntities(tableNameHashAndRange, keys, "Type", map[string]interface{}{
+			"User":  EntityUser{},
+			"Order": EntityOrder{},
+		})
+
+		assert.NoError(t, err)
+		var users []EntityUser
+		assert.NoError(t, items.Get("User", &users))
+		assert.Equal(t, []EntityUser{user}, users)
+		var got []EntityOrder
+		assert.NoError(t, items.Get("Order", &got))
+		assert.ElementsMatch(t, orders, got)
+		assert.Error(t, items.Get("Order", &users))
+	})
+
+	t.Run("Failure: unprocessed", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{
+			Faults:      &Faults{UnprocessedEvery: 1, Operations: []string{"BatchGetItem"}},
+			RetryPolicy: &RetryPolicy{MaxAttempts: 1},
+		})
+		items, err := dynamo.BatchGetEntities(tableNameHashAndRange, keys, "Type", map[string]interface{}{
+			"User":  EntityUser{},
+			"Order": EntityOrder{},
+		})
+
+		var unprocessed *UnprocessedError
+		if assert.True(t, errors.As(err, &unprocessed)) {
+			var users []EntityUser
+			var got []EntityOrder
+			assert.NoError(t, items.Get("User", &users))
+			assert.NoError(t, items.Get("Order", &got))
+			assert.NotEmpty(t, unprocessed.Keys)
+			assert.Equal(t, len(keys), len(users)+len(got)+len(unprocessed.Keys))
+		}
+	})
+
+	t.Run("Failure: unknown entity type", func(t *testing.T) {
+		_, err := dynamo.BatchGetEntities(tableNameHashAndRange, keys, "Type", map[string]interface{}{
+			"User": EntityUser{},
+		})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,362 @@
+package dynamodb
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrEntityNotRegistered is returned by EntityMapper for types not passed to Register.
+var ErrEntityNotRegistered = errors.New("entity not registered")
+
+// EntityMapper stores several entity types in one table, deriving the composite keys of an entity
+// from templates in the dynamokey tag of its hash and range key fields. A placeholder {.Field}
+// stands for the Go field of that name, formatted as text:
+//
+//	type Order struct {
+//		PK        string    `dynamo:"PK,hash" dynamokey:"USER#{.UserID}"`
+//		SK        string    `dynamo:"SK,range" dynamokey:"ORDER#{.OrderedAt}"`
+//		UserID    string    `dynamo:"UserID"`
+//		OrderedAt time.Time `dynamo:"OrderedAt"`
+//	}
+//
+// Key fields without a template are used as they are.
+type EntityMapper struct {
+	db        Dynamodb
+	tableName string
+
+	mu       sync.RWMutex
+	entities map[reflect.Type]*entitySchema
+}
+
+type entitySchema struct {
+	name   string
+	entity interface{}
+	hash   entityKey
+	rng    *entityKey
+}
+
+// entityKey is a key attribute of an entity and its template, if any.
+type entityKey struct {
+	name     string
+	field    []int
+	template string
+	parts    []keyPart
+}
+
+// keyPart is a literal of a template, or a placeholder when field is set.
+type keyPart struct {
+	literal string
+	name    string
+	field   []int
+}
+
+// NewEntityMapper :
+func NewEntityMapper(db Dynamodb, tableName string) *EntityMapper {
+	return &EntityMapper{db: db, tableName: tableName, entities: map[reflect.Type]*entitySchema{}}
+}
+
+// Register parses the key templates of entity, a struct or a pointer to one, and names its type.
+func (m *EntityMapper) Register(name string, entity interface{}) error {
+	t := reflect.TypeOf(entity)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("entity %T is not a struct", entity)
+	}
+
+	schema, err := parseEntity(t)
+	if err != nil {
+		return fmt.Errorf("entity %s: %w", name, err)
+	}
+	schema.name, schema.entity = name, reflect.Zero(t).Interface()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entities[t] = schema
+	return nil
+}
+
+// Entities returns the registered entities by name, as BatchGetEntities takes them.
+func (m *EntityMapper) Entities() map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entities := make(map[string]interface{}, len(m.entities))
+	for _, schema := range m.entities {
+		entities[schema.name] = schema.entity
+	}
+	return entities
+}
+
+// Key derives the key of entity. Every field referenced by its templates must be set.
+func (m *EntityMapper) Key(entity interface{}) (DynamodbKey, error) {
+	schema, rv, err := m.schema(entity)
+	if err != nil {
+		return DynamodbKey{}, err
+	}
+
+	hash, err := schema.hash.value(rv)
+	if err != nil {
+		return DynamodbKey{}, err
+	}
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return schema.hash.name, hash },
+	}
+	if schema.rng != nil {
+		rng, err := schema.rng.value(rv)
+		if err != nil {
+			return DynamodbKey{}, err
+		}
+		key.Range = func() (string, interface{}, *DynamodbOptions) { return schema.rng.name, rng, nil }
+	}
+	return key, nil
+}
+
+// Put sets the key fields of entity from their templates and puts it. A pointer entity
+// is updated in place so the caller sees the keys; any other entity is copied.
+func (m *EntityMapper) Put(entity interface{}) (*DynamodbResponse, error) {
+	schema, rv, err := m.schema(entity)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+
+	item := entity
+	if !rv.CanSet() {
+		copied := reflect.New(rv.Type())
+		copied.Elem().Set(rv)
+		item, rv = copied.Interface(), copied.Elem()
+	}
+
+	keys := []entityKey{schema.hash}
+	if schema.rng != nil {
+		keys = append(keys, *schema.rng)
+	}
+	for _, key := range keys {
+		if err := key.set(rv); err != nil {
+			return &DynamodbResponse{}, err
+		}
+	}
+
+	return m.db.Put(m.tableName, item)
+}
+
+// Get reads the item with the key of entity, a pointer to a registered struct, into it.
+func (m *EntityMapper) Get(entity interface{}) error {
+	if rv := reflect.ValueOf(entity); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("entity must be a non-nil pointer")
+	}
+
+	key, err := m.Key(entity)
+	if err != nil {
+		return err
+	}
+	return m.db.Get(m.tableName, key, entity)
+}
+
+// Delete deletes the item with the key of entity.
+func (m *EntityMapper) Delete(entity interface{}) (*DynamodbResponse, error) {
+	key, err := m.Key(entity)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	return m.db.Delete(m.tableName, key)
+}
+
+// Query reads the entities sharing the hash key of partial into result, a pointer to a slice of
+// the type of partial. The range key template is rendered up to the first unset field and matched as
+// a prefix, so that Query(&orders, Order{UserID: "123"}) reads the items of USER#123 beginning with ORDER#.
+func (m *EntityMapper) Query(result interface{}, partial interface{}) error {
+	schema, rv, err := m.schema(partial)
+	if err != nil {
+		return err
+	}
+	if t := reflect.TypeOf(result); t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice || t.Elem().Elem() != rv.Type() {
+		return fmt.Errorf("result must be a pointer to a slice of %s", rv.Type())
+	}
+
+	hash, err := schema.hash.value(rv)
+	if err != nil {
+		return err
+	}
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return schema.hash.name, hash },
+	}
+	switch rng := schema.rng; {
+	case rng == nil:
+	case rng.parts == nil:
+		if fv := rv.FieldByIndex(rng.field); !fv.IsZero() {
+			key.Range = func() (string, interface{}, *DynamodbOptions) { return rng.name, fv.Interface(), nil }
+		}
+	default:
+		prefix, complete := rng.prefix(rv)
+		op := DynamodbBeginsWith
+		if complete {
+			op = DynamodbEqual
+		}
+		if len(prefix) > 0 {
+			key.Range = func() (string, interface{}, *DynamodbOptions) {
+				return rng.name, prefix, &DynamodbOptions{Operator: &op}
+			}
+		}
+	}
+
+	return m.db.GetAll(m.tableName, key, result)
+}
+
+func (m *EntityMapper) schema(entity interface{}) (*entitySchema, reflect.Value, error) {
+	rv := reflect.Indirect(reflect.ValueOf(entity))
+	if !rv.IsValid() {
+		return nil, rv, errors.New("entity is nil")
+	}
+
+	m.mu.RLock()
+	schema, ok := m.entities[rv.Type()]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, rv, fmt.Errorf("%w: %s", ErrEntityNotRegistered, rv.Type())
+	}
+	return schema, rv, nil
+}
+
+func parseEntity(t reflect.Type) (*entitySchema, error) {
+	schema := &entitySchema{}
+	var hash *entityKey
+	for _, field := range entityFields(t, nil) {
+		tags := strings.Split(field.Tag.Get("dynamo"), ",")
+		name := tags[0]
+		if len(name) < 1 {
+			name = field.Name
+		}
+
+		for _, option := range tags[1:] {
+			if option != "hash" && option != "range" {
+				continue
+			}
+
+			key := &entityKey{name: name, field: field.Index, template: field.Tag.Get("dynamokey")}
+			if len(key.template) > 0 {
+				if field.Type.Kind() != reflect.String {
+					return nil, fmt.Errorf("templated key %s must be a string", field.Name)
+				}
+				parts, err := parseKeyTemplate(t, key.template)
+				if err != nil {
+					return nil, err
+				}
+				key.parts = parts
+			}
+
+			if option == "hash" {
+				hash = key
+			} else {
+				schema.rng = key
+			}
+		}
+	}
+
+	if hash == nil {
+		return nil, errors.New("no hash key")
+	}
+	schema.hash = *hash
+	return schema, nil
+}
+
+// entityFields lists the fields of t, descending into embedded structs like dynamo flattens them.
+func entityFields(t reflect.Type, index []int) []reflect.StructField {
+	fields := []reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		field.Index = append(append([]int{}, index...), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, entityFields(field.Type, field.Index)...)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func parseKeyTemplate(t reflect.Type, template string) ([]keyPart, error) {
+	parts := []keyPart{}
+	for rest := template; len(rest) > 0; {
+		open := strings.Index(rest, "{.")
+		if open < 0 {
+			parts = append(parts, keyPart{literal: rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, keyPart{literal: rest[:open]})
+		}
+
+		end := strings.Index(rest[open:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("template %q: unclosed placeholder", template)
+		}
+		name := rest[open+2 : open+end]
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("template %q: no field %s", template, name)
+		}
+		parts = append(parts, keyPart{name: name, field: field.Index})
+		rest = rest[open+end+1:]
+	}
+	return parts, nil
+}
+
+// value renders the key of rv, which must have every referenced field set.
+func (k entityKey) value(rv reflect.Value) (interface{}, error) {
+	if k.parts == nil {
+		return rv.FieldByIndex(k.field).Interface(), nil
+	}
+
+	s, complete := k.prefix(rv)
+	if !complete {
+		return nil, fmt.Errorf("key %s %q: fields unset", k.name, k.template)
+	}
+	return s, nil
+}
+
+// prefix renders the template of the key up to the first unset field.
+func (k entityKey) prefix(rv reflect.Value) (string, bool) {
+	var b strings.Builder
+	for _, part := range k.parts {
+		if part.field == nil {
+			b.WriteString(part.literal)
+			continue
+		}
+
+		fv := rv.FieldByIndex(part.field)
+		if fv.IsZero() {
+			return b.String(), false
+		}
+		b.WriteString(formatKeyPart(fv))
+	}
+	return b.String(), true
+}
+
+// set renders a templated key into its field.
+func (k entityKey) set(rv reflect.Value) error {
+	if k.parts == nil {
+		return nil
+	}
+
+	s, err := k.value(rv)
+	if err != nil {
+		return err
+	}
+	rv.FieldByIndex(k.field).SetString(s.(string))
+	return nil
+}
+
+func formatKeyPart(fv reflect.Value) string {
+	if m, ok := fv.Interface().(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(fv.Interface())
+}
@@ -0,0 +1,99 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type MappedUser struct {
+	PK     string `dynamo:"ID,hash" dynamokey:"USER#{.UserID}"`
+	SK     string `dynamo:"CreatedAt,range" dynamokey:"PROFILE"`
+	UserID string `dynamo:"UserID"`
+	Name   string `dynamo:"Name"`
+}
+
+type MappedOrder struct {
+	PK      string `dynamo:"ID,hash" dynamokey:"USER#{.UserID}"`
+	SK      string `dynamo:"CreatedAt,range" dynamokey:"ORDER#{.OrderID}"`
+	UserID  string `dynamo:"UserID"`
+	OrderID string `dynamo:"OrderID"`
+	Status  int    `dynamo:"Status"`
+}
+
+func TestEntityMapper(t *testing.T) {
+	mapper := NewEntityMapper(newDynamo(t), tableNameHashAndRange)
+	assert.NoError(t, mapper.Register("User", MappedUser{}))
+	assert.NoError(t, mapper.Register("Order", &MappedOrder{}))
+
+	userID := faker.UUIDDigit()
+	user := MappedUser{UserID: userID, Name: faker.Name()}
+	orders := []MappedOrder{
+		{UserID: userID, OrderID: "1", Status: 1},
+		{UserID: userID, OrderID: "2", Status: 2},
+	}
+
+	t.Run("Success: put", func(t *testing.T) {
+		_, err := mapper.Put(&user)
+		assert.NoError(t, err)
+		assert.Equal(t, "USER#"+userID, user.PK)
+		assert.Equal(t, "PROFILE", user.SK)
+
+		for i := range orders {
+			_, err := mapper.Put(orders[i])
+			assert.NoError(t, err)
+			assert.Empty(t, orders[i].PK)
+			orders[i].PK, orders[i].SK = "USER#"+userID, "ORDER#"+orders[i].OrderID
+		}
+	})
+
+	t.Run("Success: get", func(t *testing.T) {
+		got := MappedUser{UserID: userID}
+		assert.NoError(t, mapper.Get(&got))
+		assert.Equal(t, user, got)
+
+		order := MappedOrder{UserID: userID, OrderID: "2"}
+		assert.NoError(t, mapper.Get(&order))
+		assert.Equal(t, orders[1], order)
+	})
+
+	t.Run("Success: query by entity", func(t *testing.T) {
+		var got []MappedOrder
+		assert.NoError(t, mapper.Query(&got, MappedOrder{UserID: userID}))
+		assert.Equal(t, orders, got)
+	})
+
+	t.Run("Success: entities", func(t *testing.T) {
+		entities := mapper.Entities()
+		assert.Equal(t, MappedUser{}, entities["User"])
+		assert.Equal(t, MappedOrder{}, entities["Order"])
+	})
+
+	t.Run("Success: delete", func(t *testing.T) {
+		_, err := mapper.Delete(MappedOrder{UserID: userID, OrderID: "1"})
+		assert.NoError(t, err)
+
+		var got []MappedOrder
+		assert.NoError(t, mapper.Query(&got, MappedOrder{UserID: userID}))
+		assert.Equal(t, orders[1:], got)
+	})
+
+	t.Run("Failure: field unset", func(t *testing.T) {
+		_, err := mapper.Put(MappedOrder{UserID: userID})
+		assert.Error(t, err)
+	})
+
+	t.Run("Failure: not registered", func(t *testing.T) {
+		_, err := mapper.Put(HashOnly{Id: faker.UUIDDigit()})
+		assert.True(t, errors.Is(err, ErrEntityNotRegistered))
+	})
+
+	t.Run("Failure: unknown field", func(t *testing.T) {
+		type Broken struct {
+			PK string `dynamo:"PK,hash" dynamokey:"X#{.Missing}"`
+		}
+		assert.Error(t, mapper.Register("Broken", Broken{}))
+	})
+}
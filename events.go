@@ -0,0 +1,59 @@
+package dynamodb
+
+import (
+	"sync"
+	"time"
+)
+
+// TableEventType :
+type TableEventType string
+
+// Table events emitted after admin operations succeed.
+const (
+	TableCreated TableEventType = "TableCreated"
+	TableDeleted TableEventType = "TableDeleted"
+	TableUpdated TableEventType = "TableUpdated"
+	IndexAdded   TableEventType = "IndexAdded"
+	IndexRemoved TableEventType = "IndexRemoved"
+	TTLChanged   TableEventType = "TTLChanged"
+)
+
+// TableEvent :
+type TableEvent struct {
+	Type  TableEventType
+	Table string
+	// Index is set for index events.
+	Index string
+	// Attribute is set for TTL events.
+	Attribute string
+	Time      time.Time
+}
+
+// TableEventHandler is called synchronously after an admin operation succeeds.
+type TableEventHandler func(event TableEvent)
+
+type tableEvents struct {
+	mu       sync.RWMutex
+	handlers []TableEventHandler
+}
+
+// OnTableEvent subscribes handler to the table events of this client.
+func (con *dynamodb) OnTableEvent(handler TableEventHandler) {
+	con.tableEvents.mu.Lock()
+	defer con.tableEvents.mu.Unlock()
+	con.tableEvents.handlers = append(con.tableEvents.handlers, handler)
+}
+
+func (con *dynamodb) emit(event TableEvent) {
+	con.descriptions.Delete(event.Table)
+
+	event.Time = con.clock().Now()
+
+	con.tableEvents.mu.RLock()
+	handlers := con.tableEvents.handlers
+	con.tableEvents.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
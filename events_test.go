@@ -0,0 +1,37 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnTableEvent(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	var events []TableEvent
+	dynamo.OnTableEvent(func(event TableEvent) {
+		events = append(events, event)
+	})
+
+	name := "events-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithOptions(name, WithTTL{}, CreateTableOptions{Wait: true}))
+	assert.NoError(t, dynamo.EnableTTL(name, "ExpiresAt"))
+	assert.NoError(t, dynamo.DeleteTable(name))
+	assert.NoError(t, dynamo.WaitUntilTableDeleted(context.Background(), name))
+
+	t.Run("Failure: not emitted", func(t *testing.T) {
+		assert.Error(t, dynamo.DeleteTable(name))
+	})
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, TableCreated, events[0].Type)
+		assert.Equal(t, name, events[0].Table)
+		assert.Equal(t, TTLChanged, events[1].Type)
+		assert.Equal(t, "ExpiresAt", events[1].Attribute)
+		assert.Equal(t, TableDeleted, events[2].Type)
+		assert.False(t, events[2].Time.IsZero())
+	}
+}
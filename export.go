@@ -0,0 +1,219 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// DefaultExportSegments is the number of segments ExportTable scans concurrently.
+const DefaultExportSegments = 4
+
+// ExportFormat :
+type ExportFormat string
+
+// Export formats
+const (
+	// ExportJSONLines writes every item as a plain JSON object per line. Numbers keep their precision.
+	ExportJSONLines ExportFormat = "JSON_LINES"
+	// ExportDynamoDBJSON writes every item as {"Item": ...} with typed attribute values per line,
+	// the format of native exports to S3.
+	ExportDynamoDBJSON ExportFormat = "DYNAMODB_JSON"
+)
+
+// ExportTable writes all items of the table to w, one per line, and returns the number of items written.
+// Segments are scanned concurrently, so the lines are in no particular order.
+func (con *dynamodb) ExportTable(ctx context.Context, tableName string, w io.Writer, format ExportFormat) (int, error) {
+	var encode func(item map[string]*awsDynamodb.AttributeValue) interface{}
+	switch format {
+	case ExportJSONLines:
+		encode = func(item map[string]*awsDynamodb.AttributeValue) interface{} {
+			return plainItem(item)
+		}
+	case ExportDynamoDBJSON:
+		encode = func(item map[string]*awsDynamodb.AttributeValue) interface{} {
+			return map[string]interface{}{"Item": typedItem(item)}
+		}
+	default:
+		return 0, errors.New("unknown export format")
+	}
+
+	ctx, cancel := con.scanContext(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		once     sync.Once
+		written  int
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i := 0; i < DefaultExportSegments; i++ {
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+
+			db := dynamo.NewFromIface(&segmentClient{con.db.Client(), int64(segment), DefaultExportSegments})
+			iter := db.Table(con.tableName(tableName)).Scan().Iter()
+
+			var item map[string]*awsDynamodb.AttributeValue
+			for iter.NextWithContext(ctx, &item) {
+				line, err := json.Marshal(encode(item))
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				mu.Lock()
+				_, err = w.Write(append(line, '\n'))
+				if err == nil {
+					written++
+				}
+				mu.Unlock()
+				if err != nil {
+					fail(err)
+					return
+				}
+				item = nil
+			}
+			if err := iter.Err(); err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return written, scanError(ctx, firstErr)
+}
+
+func plainItem(item map[string]*awsDynamodb.AttributeValue) map[string]interface{} {
+	out := make(map[string]interface{}, len(item))
+	for name, av := range item {
+		out[name] = plainValue(av)
+	}
+	return out
+}
+
+func plainValue(av *awsDynamodb.AttributeValue) interface{} {
+	switch {
+	case av.S != nil:
+		return *av.S
+	case av.N != nil:
+		return json.Number(*av.N)
+	case av.B != nil:
+		return av.B
+	case av.BOOL != nil:
+		return *av.BOOL
+	case av.SS != nil:
+		return aws.StringValueSlice(av.SS)
+	case av.NS != nil:
+		numbers := make([]json.Number, len(av.NS))
+		for i, n := range av.NS {
+			numbers[i] = json.Number(*n)
+		}
+		return numbers
+	case av.BS != nil:
+		return av.BS
+	case av.L != nil:
+		list := make([]interface{}, len(av.L))
+		for i, v := range av.L {
+			list[i] = plainValue(v)
+		}
+		return list
+	case av.M != nil:
+		return plainItem(av.M)
+	}
+	return nil
+}
+
+func typedItem(item map[string]*awsDynamodb.AttributeValue) map[string]interface{} {
+	out := make(map[string]interface{}, len(item))
+	for name, av := range item {
+		out[name] = typedValue(av)
+	}
+	return out
+}
+
+// typedValue keeps the one type set on av, which json.Marshal alone would write next to every unset type.
+func typedValue(av *awsDynamodb.AttributeValue) map[string]interface{} {
+	switch {
+	case av.S != nil:
+		return map[string]interface{}{"S": *av.S}
+	case av.N != nil:
+		return map[string]interface{}{"N": *av.N}
+	case av.B != nil:
+		return map[string]interface{}{"B": av.B}
+	case av.BOOL != nil:
+		return map[string]interface{}{"BOOL": *av.BOOL}
+	case av.SS != nil:
+		return map[string]interface{}{"SS": av.SS}
+	case av.NS != nil:
+		return map[string]interface{}{"NS": av.NS}
+	case av.BS != nil:
+		return map[string]interface{}{"BS": av.BS}
+	case av.L != nil:
+		list := make([]interface{}, len(av.L))
+		for i, v := range av.L {
+			list[i] = typedValue(v)
+		}
+		return map[string]interface{}{"L": list}
+	case av.M != nil:
+		return map[string]interface{}{"M": typedItem(av.M)}
+	}
+	return map[string]interface{}{"NULL": true}
+}
+
+// ExportToS3Options :
+type ExportToS3Options struct {
+	Bucket string
+	Prefix string
+	// At is the point in time to export. Defaults to now.
+	At time.Time
+	// Format defaults to ExportDynamoDBJSON, the only format of native exports this package supports.
+	Format ExportFormat
+}
+
+// ExportToS3 starts a native export of the table to S3 and returns its ARN. The export does
+// not consume read capacity but requires point-in-time recovery, and takes minutes to hours.
+func (con *dynamodb) ExportToS3(ctx context.Context, tableName string, opts ExportToS3Options) (string, error) {
+	if len(opts.Format) > 0 && opts.Format != ExportDynamoDBJSON {
+		return "", errors.New("unsupported export format")
+	}
+
+	desc, err := con.table(tableName).Describe().RunWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	input := &awsDynamodb.ExportTableToPointInTimeInput{
+		TableArn:     aws.String(desc.ARN),
+		S3Bucket:     aws.String(opts.Bucket),
+		ExportFormat: aws.String(awsDynamodb.ExportFormatDynamodbJson),
+	}
+	if len(opts.Prefix) > 0 {
+		input.S3Prefix = aws.String(opts.Prefix)
+	}
+	if !opts.At.IsZero() {
+		input.ExportTime = aws.Time(opts.At)
+	}
+
+	out, err := con.db.Client().ExportTableToPointInTimeWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.ExportDescription.ExportArn), nil
+}
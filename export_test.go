@@ -0,0 +1,66 @@
+package dynamodb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTable(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "export-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	items := make([]HashOnly, 30)
+	for i := range items {
+		items[i] = HashOnly{Id: faker.UUIDDigit(), Name: faker.Name(), Status: i}
+	}
+	_, err := dynamo.BulkPut(ctx, name, items, BulkPutOptions{})
+	assert.NoError(t, err)
+
+	t.Run("Success: json lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := dynamo.ExportTable(ctx, name, &buf, ExportJSONLines)
+		assert.NoError(t, err)
+		assert.Equal(t, len(items), n)
+
+		ids := map[string]bool{}
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var item HashOnly
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &item))
+			ids[item.Id] = true
+		}
+		assert.Len(t, ids, len(items))
+	})
+
+	t.Run("Success: dynamodb json", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := dynamo.ExportTable(ctx, name, &buf, ExportDynamoDBJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, len(items), n)
+
+		line, err := bufio.NewReader(&buf).ReadBytes('\n')
+		assert.NoError(t, err)
+
+		var record struct {
+			Item map[string]map[string]interface{}
+		}
+		assert.NoError(t, json.Unmarshal(line, &record))
+		assert.Contains(t, record.Item["ID"], "S")
+		assert.Contains(t, record.Item["Status"], "N")
+	})
+
+	t.Run("Failure: unknown format", func(t *testing.T) {
+		_, err := dynamo.ExportTable(ctx, name, &bytes.Buffer{}, "CSV")
+		assert.Error(t, err)
+	})
+}
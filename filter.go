@@ -0,0 +1,139 @@
+package dynamodb
+
+import (
+	"strings"
+)
+
+// Filter builds a ScanFilter whose expression takes several placeholders, such as
+// Filter("Status = ? AND Age > ?", status, age).
+func Filter(expr string, values ...interface{}) ScanFilter {
+	return ScanFilter{Expr: expr, Values: values}
+}
+
+// FilterAnd matches items matching all filters.
+func FilterAnd(filters ...ScanFilter) ScanFilter {
+	return joinFilters(" AND ", filters)
+}
+
+// FilterOr matches items matching any of filters.
+func FilterOr(filters ...ScanFilter) ScanFilter {
+	return joinFilters(" OR ", filters)
+}
+
+func joinFilters(op string, filters []ScanFilter) ScanFilter {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+
+	exprs := make([]string, len(filters))
+	values := []interface{}{}
+	for i, f := range filters {
+		exprs[i] = "(" + f.Expr + ")"
+		values = append(values, f.args()...)
+	}
+	return ScanFilter{Expr: strings.Join(exprs, op), Values: values}
+}
+
+// args returns the values substituted for the ? and $ placeholders of Expr.
+func (f ScanFilter) args() []interface{} {
+	values := f.Values
+	if values == nil {
+		values = []interface{}{f.Value}
+	}
+
+	if n := strings.Count(f.Expr, "?") + strings.Count(f.Expr, "$"); n < len(values) {
+		return values[:n]
+	}
+	return values
+}
+
+// FilterAttr compares the attribute at path, which may be a document path such as Address.City
+// or Items[0].Price. DynamodbBetween takes two values.
+func FilterAttr(path string, op DynamodbOperator, values ...interface{}) ScanFilter {
+	expr, names := documentPath(path)
+	return compare(expr, names, op, values)
+}
+
+// FilterContains matches items whose string at path contains value as a substring,
+// or whose set or list at path contains value as an element.
+func FilterContains(path string, value interface{}) ScanFilter {
+	return FilterAttr(path, DynamodbContains, value)
+}
+
+// FilterSize compares the size of the string, binary, set, list or map at path.
+func FilterSize(path string, op DynamodbOperator, values ...interface{}) ScanFilter {
+	expr, names := documentPath(path)
+	return compare("size("+expr+")", names, op, values)
+}
+
+// FilterExists matches items having an attribute at path.
+func FilterExists(path string) ScanFilter {
+	return FilterAttr(path, DynamodbNotNull)
+}
+
+// FilterNotExists matches items without an attribute at path.
+func FilterNotExists(path string) ScanFilter {
+	return FilterAttr(path, DynamodbNull)
+}
+
+// DynamodbType is the type of an attribute, compared by DynamodbAttributeType.
+type DynamodbType string
+
+// Attribute types
+const (
+	DynamodbTypeString    DynamodbType = "S"
+	DynamodbTypeStringSet DynamodbType = "SS"
+	DynamodbTypeNumber    DynamodbType = "N"
+	DynamodbTypeNumberSet DynamodbType = "NS"
+	DynamodbTypeBinary    DynamodbType = "B"
+	DynamodbTypeBinarySet DynamodbType = "BS"
+	DynamodbTypeBool      DynamodbType = "BOOL"
+	DynamodbTypeNull      DynamodbType = "NULL"
+	DynamodbTypeList      DynamodbType = "L"
+	DynamodbTypeMap       DynamodbType = "M"
+)
+
+func compare(operand string, names []interface{}, op DynamodbOperator, values []interface{}) ScanFilter {
+	var expr string
+	switch op {
+	case DynamodbBeginsWith:
+		expr = "begins_with(" + operand + ", ?)"
+	case DynamodbBetween:
+		expr = operand + " BETWEEN ? AND ?"
+	case DynamodbContains:
+		expr = "contains(" + operand + ", ?)"
+	case DynamodbNotContains:
+		expr = "NOT contains(" + operand + ", ?)"
+	case DynamodbAttributeType:
+		expr = "attribute_type(" + operand + ", ?)"
+	case DynamodbNotNull:
+		return ScanFilter{Expr: "attribute_exists(" + operand + ")", Values: names}
+	case DynamodbNull:
+		return ScanFilter{Expr: "attribute_not_exists(" + operand + ")", Values: names}
+	case DynamodbIn:
+		expr = operand + " IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")"
+	default:
+		expr = operand + " " + [...]string{"=", "<>", "<", "<=", ">", ">="}[op] + " ?"
+	}
+	return ScanFilter{Expr: expr, Values: append(names, values...)}
+}
+
+// documentPath turns a path such as Items[0].Name into $[0].$ with a name placeholder per segment,
+// so segments that are reserved words need no escaping.
+func documentPath(path string) (string, []interface{}) {
+	var b strings.Builder
+	names := []interface{}{}
+	for i, segment := range strings.Split(path, ".") {
+		if i > 0 {
+			b.WriteString(".")
+		}
+
+		name, index := segment, ""
+		if n := strings.IndexByte(segment, '['); n >= 0 {
+			name, index = segment[:n], segment[n:]
+		}
+		b.WriteString("$" + index)
+		names = append(names, name)
+	}
+	return b.String(), names
+}
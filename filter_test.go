@@ -0,0 +1,116 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterAnd(t *testing.T) {
+	f := FilterAnd(
+		ScanFilter{Expr: "attribute_exists(Name)"},
+		Filter("Status = ? AND Age > ?", 1, 20),
+		ScanFilter{Expr: "begins_with($, ?)", Value: "Na"},
+	)
+	assert.Equal(t, "(attribute_exists(Name)) AND (Status = ? AND Age > ?) AND (begins_with($, ?))", f.Expr)
+	assert.Equal(t, []interface{}{1, 20, "Na"}, f.args())
+}
+
+func TestFilterOr(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	id := faker.UUIDDigit()
+	for i, name := range []string{"a", "b", "c"} {
+		_, err := dynamo.Put(tableNameHashAndRange, HashAndRange{Id: id, CreatedAt: name, Name: name, Status: i})
+		assert.NoError(t, err)
+	}
+
+	t.Run("Scan", func(t *testing.T) {
+		var items []HashAndRange
+		err := dynamo.Scan(tableNameHashAndRange, &items,
+			Filter("ID = ?", id),
+			FilterOr(Filter("Name = ?", "a"), Filter("Status = ?", 2)),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		var items []HashAndRange
+		err := dynamo.Query(tableNameHashAndRange).
+			Hash("ID", id).
+			Filter(FilterOr(Filter("Name = ? OR Name = ?", "a", "b"), Filter("Status > ?", 1))).
+			All(&items)
+		assert.NoError(t, err)
+		assert.Len(t, items, 3)
+	})
+}
+
+type Document struct {
+	Id      string `dynamo:"ID,hash"`
+	Address struct {
+		City string
+	}
+	Tags  []string
+	Items []struct {
+		Name string
+	}
+}
+
+func TestFilterAttr(t *testing.T) {
+	t.Run("Expression", func(t *testing.T) {
+		f := FilterAttr("Items[0].Name", DynamodbBetween, "a", "b")
+		assert.Equal(t, "$[0].$ BETWEEN ? AND ?", f.Expr)
+		assert.Equal(t, []interface{}{"Items", "Name", "a", "b"}, f.args())
+
+		f = FilterSize("Tags", DynamodbGreaterOrEqual, 2)
+		assert.Equal(t, "size($) >= ?", f.Expr)
+		assert.Equal(t, []interface{}{"Tags", 2}, f.args())
+
+		f = FilterAttr("Tags", DynamodbNotContains, "go")
+		assert.Equal(t, "NOT contains($, ?)", f.Expr)
+
+		f = FilterAttr("Address.City", DynamodbNotNull)
+		assert.Equal(t, "attribute_exists($.$)", f.Expr)
+		assert.Equal(t, []interface{}{"Address", "City"}, f.args())
+
+		f = FilterAttr("Status", DynamodbIn, 1, 2, 3)
+		assert.Equal(t, "$ IN (?, ?, ?)", f.Expr)
+		assert.Equal(t, []interface{}{"Status", 1, 2, 3}, f.args())
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		dynamo := newDynamo(t)
+
+		var doc Document
+		doc.Id = faker.UUIDDigit()
+		doc.Address.City = "Tokyo"
+		doc.Tags = []string{"go", "dynamodb"}
+		doc.Items = append(doc.Items, struct{ Name string }{"first"})
+		_, err := dynamo.Put(tableNameHashOnly, doc)
+		assert.NoError(t, err)
+
+		for _, f := range []ScanFilter{
+			FilterAttr("Address.City", DynamodbEqual, "Tokyo"),
+			FilterAttr("Items[0].Name", DynamodbBeginsWith, "fi"),
+			FilterContains("Tags", "go"),
+			FilterSize("Tags", DynamodbGreater, 1),
+			FilterExists("Address.City"),
+			FilterAttr("Tags", DynamodbNotContains, "java"),
+			FilterAttr("Address", DynamodbAttributeType, DynamodbTypeMap),
+			FilterAttr("Address.Zip", DynamodbNull),
+			FilterAttr("Address.City", DynamodbIn, "Osaka", "Tokyo"),
+		} {
+			var items []Document
+			err := dynamo.Scan(tableNameHashOnly, &items, Filter("ID = ?", doc.Id), f)
+			assert.NoError(t, err, f.Expr)
+			assert.Len(t, items, 1, f.Expr)
+		}
+
+		var items []Document
+		err = dynamo.Scan(tableNameHashOnly, &items, Filter("ID = ?", doc.Id), FilterNotExists("Address.Zip"), FilterContains("Tags", "java"))
+		assert.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
@@ -0,0 +1,66 @@
+package dynamodb
+
+import (
+	"errors"
+	"reflect"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// GetOrDefault is Get that stores defaultItem in result when the item does not exist, without writing it.
+func (con *dynamodb) GetOrDefault(tableName string, key DynamodbKey, defaultItem, result interface{}) error {
+	err := con.Get(tableName, key, result)
+	if errors.Is(err, dynamo.ErrNotFound) {
+		return assignItem(defaultItem, result)
+	}
+	return err
+}
+
+// GetOrCreate is Get that puts defaultItem when the item does not exist, and reports whether it did.
+// defaultItem must have the key of key. When another writer creates the item first,
+// its item is read back with a strongly consistent read instead.
+func (con *dynamodb) GetOrCreate(tableName string, key DynamodbKey, defaultItem, result interface{}) (bool, error) {
+	err := con.Get(tableName, key, result)
+	if !errors.Is(err, dynamo.ErrNotFound) {
+		return false, err
+	}
+
+	_, err = con.PutIfNotExists(tableName, defaultItem)
+	switch {
+	case err == nil:
+		return true, assignItem(defaultItem, result)
+	case !errors.Is(err, ErrItemExists):
+		return false, err
+	}
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	table := con.table(tableName)
+	var av map[string]*awsDynamodb.AttributeValue
+	if err := query(&table, key).Consistent(true).OneWithContext(ctx, &av); err != nil {
+		return false, err
+	}
+	return false, con.decodeItem(tableName, av, result)
+}
+
+// assignItem stores item in result, a pointer to the type of item or to a struct it marshals into.
+func assignItem(item, result interface{}) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("result must be a non-nil pointer")
+	}
+
+	iv := reflect.Indirect(reflect.ValueOf(item))
+	if iv.IsValid() && iv.Type().AssignableTo(rv.Elem().Type()) {
+		rv.Elem().Set(iv)
+		return nil
+	}
+
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return err
+	}
+	return dynamo.UnmarshalItem(av, result)
+}
@@ -0,0 +1,83 @@
+package dynamodb
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrDefault(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	item := HashOnly{Id: faker.UUIDDigit(), Name: "default"}
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", item.Id },
+	}
+
+	var got HashOnly
+	assert.NoError(t, dynamo.GetOrDefault(tableNameHashOnly, key, item, &got))
+	assert.Equal(t, item, got)
+
+	exists, err := dynamo.Exists(tableNameHashOnly, key)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestGetOrCreate(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	t.Run("Success: create", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit(), Name: "default"}
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", item.Id },
+		}
+
+		var got HashOnly
+		created, err := dynamo.GetOrCreate(tableNameHashOnly, key, &item, &got)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, item, got)
+
+		var stored HashOnly
+		created, err = dynamo.GetOrCreate(tableNameHashOnly, key, HashOnly{Id: item.Id, Name: "other"}, &stored)
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, item, stored)
+	})
+
+	t.Run("Success: concurrent", func(t *testing.T) {
+		id := faker.UUIDDigit()
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", id },
+		}
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			created int
+			names   = map[string]bool{}
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var got HashOnly
+				ok, err := dynamo.GetOrCreate(tableNameHashOnly, key, HashOnly{Id: id, Name: faker.Name()}, &got)
+				assert.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if ok {
+					created++
+				}
+				names[got.Name] = true
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, created)
+		assert.Len(t, names, 1)
+	})
+}
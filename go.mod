@@ -7,4 +7,5 @@ require (
 	github.com/bxcodec/faker/v3 v3.6.0
 	github.com/guregu/dynamo v1.10.4
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -0,0 +1,161 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// BestEffortGroup applies conditional writes one at a time and undoes the applied ones when a write fails.
+// It costs a single write per item instead of the double cost of a transaction, but other clients
+// can observe the partial state, and rollback overwrites changes they made in between.
+type BestEffortGroup struct {
+	con    *dynamodb
+	writes []groupWrite
+}
+
+type groupWrite struct {
+	table      string
+	item       interface{}
+	key        DynamodbKey
+	conditions []ScanFilter
+}
+
+// GroupError is returned by a BestEffortGroup whose write at Index failed with Err.
+// RollbackErr is set when some applied writes could not be undone.
+type GroupError struct {
+	Index       int
+	Err         error
+	RollbackErr error
+}
+
+func (e *GroupError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("write %d: %v; rollback: %v", e.Index, e.Err, e.RollbackErr)
+	}
+	return fmt.Sprintf("write %d: %v", e.Index, e.Err)
+}
+
+func (e *GroupError) Unwrap() error {
+	return e.Err
+}
+
+func (con *dynamodb) BestEffortGroup() *BestEffortGroup {
+	return &BestEffortGroup{con: con}
+}
+
+// Put adds a put of item, applied only if all conditions hold.
+func (g *BestEffortGroup) Put(tableName string, item interface{}, conditions ...ScanFilter) *BestEffortGroup {
+	g.writes = append(g.writes, groupWrite{table: tableName, item: item, conditions: conditions})
+	return g
+}
+
+// Delete adds a delete of key, applied only if all conditions hold.
+func (g *BestEffortGroup) Delete(tableName string, key DynamodbKey, conditions ...ScanFilter) *BestEffortGroup {
+	g.writes = append(g.writes, groupWrite{table: tableName, key: key, conditions: conditions})
+	return g
+}
+
+// undo restores the state before a write.
+type undo func(ctx context.Context) error
+
+// Run applies the writes in order. When one fails, the applied writes are undone in reverse order
+// and a *GroupError is returned.
+func (g *BestEffortGroup) Run(ctx context.Context) error {
+	undos := make([]undo, 0, len(g.writes))
+	for i, w := range g.writes {
+		u, err := g.apply(ctx, w)
+		if err == nil {
+			undos = append(undos, u)
+			continue
+		}
+
+		groupErr := &GroupError{Index: i, Err: err}
+		for j := len(undos) - 1; j >= 0; j-- {
+			if err := undos[j](ctx); err != nil && groupErr.RollbackErr == nil {
+				groupErr.RollbackErr = err
+			}
+		}
+		return groupErr
+	}
+	return nil
+}
+
+func (g *BestEffortGroup) apply(ctx context.Context, w groupWrite) (undo, error) {
+	table := g.con.table(w.table)
+	var old map[string]*awsDynamodb.AttributeValue
+
+	if w.item == nil {
+		req := deleteItem(table, w.key)
+		for _, c := range w.conditions {
+			req.If(c.Expr, c.args()...)
+		}
+		if err := req.OldValueWithContext(ctx, &old); err != nil {
+			if errors.Is(err, dynamo.ErrNotFound) {
+				return func(context.Context) error { return nil }, nil
+			}
+			return nil, err
+		}
+		return restore(table, old), nil
+	}
+
+	put, err := g.con.encodePut(w.table, w.item)
+	if err != nil {
+		return nil, err
+	}
+	key, err := g.con.itemKey(w.table, w.item)
+	if err != nil {
+		return nil, err
+	}
+
+	req := table.Put(put)
+	for _, c := range w.conditions {
+		req.If(c.Expr, c.args()...)
+	}
+	if err := req.OldValueWithContext(ctx, &old); err != nil {
+		if errors.Is(err, dynamo.ErrNotFound) {
+			return func(ctx context.Context) error {
+				return deleteItem(table, key).RunWithContext(ctx)
+			}, nil
+		}
+		return nil, err
+	}
+	return restore(table, old), nil
+}
+
+func restore(table dynamo.Table, old map[string]*awsDynamodb.AttributeValue) undo {
+	return func(ctx context.Context) error {
+		return table.Put(old).RunWithContext(ctx)
+	}
+}
+
+// itemKey returns the key of item, using the key schema of the table.
+func (con *dynamodb) itemKey(tableName string, item interface{}) (DynamodbKey, error) {
+	desc, err := con.describeCached(tableName)
+	if err != nil {
+		return DynamodbKey{}, err
+	}
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return DynamodbKey{}, err
+	}
+
+	hValue, ok := av[desc.HashKey]
+	if !ok {
+		return DynamodbKey{}, fmt.Errorf("item has no hash key %s", desc.HashKey)
+	}
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return desc.HashKey, hValue },
+	}
+	if len(desc.RangeKey) > 0 {
+		rValue, ok := av[desc.RangeKey]
+		if !ok {
+			return DynamodbKey{}, fmt.Errorf("item has no range key %s", desc.RangeKey)
+		}
+		key.Range = func() (string, interface{}, *DynamodbOptions) { return desc.RangeKey, rValue, nil }
+	}
+	return key, nil
+}
@@ -0,0 +1,84 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestEffortGroup(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	get := func(id string) (HashOnly, error) {
+		var datum HashOnly
+		err := dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), id },
+		}, &datum)
+		return datum, err
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		replaced := HashOnly{Id: faker.UUIDDigit(), Name: "old"}
+		deleted := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, replaced)
+		assert.NoError(t, err)
+		_, err = dynamo.Put(tableNameHashOnly, deleted)
+		assert.NoError(t, err)
+
+		replaced.Name = "new"
+		err = dynamo.BestEffortGroup().
+			Put(tableNameHashOnly, replaced, Filter("Name = ?", "old")).
+			Delete(tableNameHashOnly, DynamodbKey{
+				Hash: func() (string, interface{}) { return deleted.HashKey(), deleted.Id },
+			}).
+			Run(ctx)
+		assert.NoError(t, err)
+
+		datum, err := get(replaced.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "new", datum.Name)
+		_, err = get(deleted.Id)
+		assert.Error(t, err)
+	})
+
+	t.Run("Failure: rolled back", func(t *testing.T) {
+		replaced := HashOnly{Id: faker.UUIDDigit(), Name: "old"}
+		deleted := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		created := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, replaced)
+		assert.NoError(t, err)
+		_, err = dynamo.Put(tableNameHashOnly, deleted)
+		assert.NoError(t, err)
+
+		next := replaced
+		next.Name = "new"
+		err = dynamo.BestEffortGroup().
+			Put(tableNameHashOnly, next).
+			Delete(tableNameHashOnly, DynamodbKey{
+				Hash: func() (string, interface{}) { return deleted.HashKey(), deleted.Id },
+			}).
+			Put(tableNameHashOnly, created).
+			Put(tableNameHashOnly, replaced, Filter("Name = ?", "old")).
+			Run(ctx)
+
+		var groupErr *GroupError
+		if assert.True(t, errors.As(err, &groupErr)) {
+			assert.Equal(t, 3, groupErr.Index)
+			assert.NoError(t, groupErr.RollbackErr)
+			assert.True(t, isConditionalCheckFailed(err))
+		}
+
+		datum, err := get(replaced.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, replaced, datum)
+		datum, err = get(deleted.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, deleted, datum)
+		_, err = get(created.Id)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DefaultGroupedConcurrency is the number of queries GetAllGrouped runs at once.
+const DefaultGroupedConcurrency = 8
+
+// GetAllGrouped runs GetAll for every key concurrently and stores the results in result,
+// a pointer to a map from hash value to a slice of items, such as *map[string][]Item.
+// Every hash of keys gets an entry, empty when nothing matched; keys sharing a hash are appended in order.
+func (con *dynamodb) GetAllGrouped(tableName string, keys []DynamodbKey, result interface{}) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Map || rv.Elem().Type().Elem().Kind() != reflect.Slice {
+		return errors.New("result must be a pointer to a map of slices")
+	}
+	mapType := rv.Elem().Type()
+
+	hashes := make([]reflect.Value, len(keys))
+	for i, key := range keys {
+		_, hValue := key.Hash()
+		hv := reflect.ValueOf(hValue)
+		if !hv.IsValid() || !hv.Type().ConvertibleTo(mapType.Key()) {
+			return fmt.Errorf("hash value %v does not fit the key of %s", hValue, mapType)
+		}
+		hashes[i] = hv.Convert(mapType.Key())
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	parts := make([]reflect.Value, len(keys))
+	sem := make(chan struct{}, DefaultGroupedConcurrency)
+	for i := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			part := reflect.New(mapType.Elem())
+			if err := con.GetAll(tableName, keys[i], part.Interface()); err != nil {
+				once.Do(func() { firstErr = err })
+				return
+			}
+			parts[i] = part.Elem()
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	groups := rv.Elem()
+	if groups.IsNil() {
+		groups.Set(reflect.MakeMap(mapType))
+	}
+	for i, hash := range hashes {
+		group := groups.MapIndex(hash)
+		if !group.IsValid() {
+			group = reflect.MakeSlice(mapType.Elem(), 0, parts[i].Len())
+		}
+		groups.SetMapIndex(hash, reflect.AppendSlice(group, parts[i]))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllGrouped(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKeys := []string{faker.UUIDDigit(), faker.UUIDDigit(), faker.UUIDDigit()}
+	now := time.Now()
+	for i, hashKey := range hashKeys[:2] {
+		for j := 0; j <= i; j++ {
+			_, err := dynamo.Put(tableNameHashAndRange, HashAndRange{
+				Id:        hashKey,
+				CreatedAt: now.AddDate(0, 0, j).Format(time.RFC3339),
+			})
+			assert.NoError(t, err)
+		}
+	}
+
+	keys := make([]DynamodbKey, len(hashKeys))
+	for i := range hashKeys {
+		hashKey := hashKeys[i]
+		keys[i] = DynamodbKey{
+			Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var groups map[string][]HashAndRange
+		assert.NoError(t, dynamo.GetAllGrouped(tableNameHashAndRange, keys, &groups))
+		assert.Len(t, groups, 3)
+		assert.Len(t, groups[hashKeys[0]], 1)
+		assert.Len(t, groups[hashKeys[1]], 2)
+		assert.Empty(t, groups[hashKeys[2]])
+	})
+
+	t.Run("Failure: result not a map", func(t *testing.T) {
+		var data []HashAndRange
+		assert.Error(t, dynamo.GetAllGrouped(tableNameHashAndRange, keys, &data))
+	})
+
+	t.Run("Failure: hash type", func(t *testing.T) {
+		var groups map[int][]HashAndRange
+		assert.Error(t, dynamo.GetAllGrouped(tableNameHashAndRange, keys, &groups))
+	})
+}
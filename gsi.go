@@ -0,0 +1,100 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guregu/dynamo"
+)
+
+// DefaultIndexPollInterval is how often the index waiters describe the table.
+const DefaultIndexPollInterval = 5 * time.Second
+
+// UpdateTableAddGSI starts creating a global secondary index on an existing table. Key types default
+// to string, the projection to all attributes and, on a provisioned table, the units to 1.
+// DynamoDB backfills the index from the existing items; see WaitUntilIndexActive.
+// Only one index can be created or deleted at a time per table.
+func (con *dynamodb) UpdateTableAddGSI(name string, index IndexDefinition) error {
+	desc, err := con.table(name).Describe().Run()
+	if err != nil {
+		return err
+	}
+
+	if _, err := con.table(name).UpdateTable().CreateIndex(gsi(index, desc.OnDemand)).Run(); err != nil {
+		return err
+	}
+
+	con.emit(TableEvent{Type: IndexAdded, Table: name, Index: index.Name})
+	return nil
+}
+
+// UpdateTableDeleteGSI starts deleting a global secondary index. See WaitUntilIndexDeleted.
+func (con *dynamodb) UpdateTableDeleteGSI(name, indexName string) error {
+	if _, err := con.table(name).UpdateTable().DeleteIndex(indexName).Run(); err != nil {
+		return err
+	}
+
+	con.emit(TableEvent{Type: IndexRemoved, Table: name, Index: indexName})
+	return nil
+}
+
+// WaitUntilIndexActive waits until the global secondary index is active and done backfilling,
+// which takes from minutes to hours depending on the size of the table.
+func (con *dynamodb) WaitUntilIndexActive(ctx context.Context, name, indexName string) error {
+	return con.pollIndex(ctx, name, indexName, func(index *dynamo.Index) (bool, error) {
+		if index == nil {
+			return false, fmt.Errorf("index %s not found", indexName)
+		}
+		return index.Status == dynamo.ActiveStatus && !index.Backfilling, nil
+	})
+}
+
+// WaitUntilIndexDeleted waits until the global secondary index is gone.
+func (con *dynamodb) WaitUntilIndexDeleted(ctx context.Context, name, indexName string) error {
+	return con.pollIndex(ctx, name, indexName, func(index *dynamo.Index) (bool, error) {
+		return index == nil, nil
+	})
+}
+
+func (con *dynamodb) pollIndex(ctx context.Context, name, indexName string, done func(index *dynamo.Index) (bool, error)) error {
+	for {
+		desc, err := con.table(name).Describe().RunWithContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		var found *dynamo.Index
+		for i := range desc.GSI {
+			if desc.GSI[i].Name == indexName {
+				found = &desc.GSI[i]
+			}
+		}
+		if ok, err := done(found); ok || err != nil {
+			return err
+		}
+
+		if err := sleep(ctx, DefaultIndexPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func gsi(index IndexDefinition, onDemand bool) dynamo.Index {
+	projection := index.Projection
+	if len(projection) < 1 {
+		projection = DynamodbProjectionAll
+	}
+
+	read, write := TableSpec{OnDemand: onDemand}.units(index.ReadUnits, index.WriteUnits)
+	return dynamo.Index{
+		Name:              index.Name,
+		HashKey:           index.HashKey,
+		HashKeyType:       index.HashKeyType.value(),
+		RangeKey:          index.RangeKey,
+		RangeKeyType:      index.RangeKeyType.value(),
+		ProjectionType:    dynamo.IndexProjection(projection),
+		ProjectionAttribs: index.NonKeyAttributes,
+		Throughput:        dynamo.Throughput{Read: read, Write: write},
+	}
+}
@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTableGSI(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "gsi-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, WithIndex{}, CreateTableOptions{Wait: true}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	item := WithIndex{Id: faker.UUIDDigit(), CreatedAt: faker.Date(), Name: faker.Name(), Status: 1}
+	_, err := dynamo.Put(name, item)
+	assert.NoError(t, err)
+
+	t.Run("Success: add", func(t *testing.T) {
+		assert.NoError(t, dynamo.UpdateTableAddGSI(name, IndexDefinition{
+			Name:        "Status-index",
+			HashKey:     "Status",
+			HashKeyType: DynamodbKeyTypeNumber,
+		}))
+		assert.NoError(t, dynamo.WaitUntilIndexActive(ctx, name, "Status-index"))
+
+		desc, err := dynamo.DescribeTable(name)
+		assert.NoError(t, err)
+		assert.Len(t, desc.GSIs, 1)
+
+		var got []WithIndex
+		assert.NoError(t, dynamo.ScanSparseIndex(name, "Status-index", &got))
+		assert.Equal(t, []WithIndex{item}, got)
+	})
+
+	t.Run("Success: delete", func(t *testing.T) {
+		assert.NoError(t, dynamo.UpdateTableDeleteGSI(name, "Status-index"))
+		assert.NoError(t, dynamo.WaitUntilIndexDeleted(ctx, name, "Status-index"))
+
+		desc, err := dynamo.DescribeTable(name)
+		assert.NoError(t, err)
+		assert.Empty(t, desc.GSIs)
+	})
+
+	t.Run("Failure: unknown index", func(t *testing.T) {
+		assert.Error(t, dynamo.WaitUntilIndexActive(ctx, name, "Missing-index"))
+	})
+}
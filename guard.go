@@ -0,0 +1,91 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Errors returned when a Guardrails limit is exceeded.
+var (
+	ErrTableQuotaExceeded   = errors.New("table quota exceeded")
+	ErrBulkQuotaExceeded    = errors.New("bulk item quota exceeded")
+	ErrScanDurationExceeded = errors.New("scan duration exceeded")
+)
+
+// Guardrails are client-side limits protecting shared accounts from runaway scripts.
+// Zero values are unlimited.
+type Guardrails struct {
+	// MaxTablesCreated is the number of tables one client may create.
+	MaxTablesCreated int
+	// MaxBulkItems is the number of keys or items one bulk operation may touch.
+	MaxBulkItems int
+	// MaxScanDuration bounds every scan.
+	MaxScanDuration time.Duration
+}
+
+// guardTableCreate reserves one table creation, which must be released if the creation fails.
+func (con *dynamodb) guardTableCreate() (release func(), err error) {
+	max := con.config.Guardrails.MaxTablesCreated
+	if max < 1 {
+		return func() {}, nil
+	}
+
+	if n := atomic.AddInt32(&con.tablesCreated, 1); int(n) > max {
+		atomic.AddInt32(&con.tablesCreated, -1)
+		return nil, fmt.Errorf("%w: already created %d tables", ErrTableQuotaExceeded, max)
+	}
+	return func() { atomic.AddInt32(&con.tablesCreated, -1) }, nil
+}
+
+func (con *dynamodb) guardBulk(n int) error {
+	if max := con.config.Guardrails.MaxBulkItems; max > 0 && n > max {
+		return fmt.Errorf("%w: %d items over the limit of %d", ErrBulkQuotaExceeded, n, max)
+	}
+	return nil
+}
+
+// scanContext bounds ctx by MaxScanDuration.
+func (con *dynamodb) scanContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if max := con.config.Guardrails.MaxScanDuration; max > 0 {
+		return context.WithTimeout(ctx, max)
+	}
+	return context.WithCancel(ctx)
+}
+
+// scanError reports a scan cut short by its scanContext as ErrScanDurationExceeded.
+func scanError(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrScanDurationExceeded
+	}
+	return err
+}
+
+type guardedIter struct {
+	iter     DynamodbIter
+	deadline time.Time
+	err      error
+}
+
+func (i *guardedIter) Next(ctx context.Context, out interface{}) bool {
+	if i.err != nil {
+		return false
+	}
+
+	scanCtx, cancel := context.WithDeadline(ctx, i.deadline)
+	defer cancel()
+
+	if i.iter.Next(scanCtx, out) {
+		return true
+	}
+	if i.err = i.iter.Err(); ctx.Err() == nil {
+		i.err = scanError(scanCtx, i.err)
+	}
+	return false
+}
+
+func (i *guardedIter) Err() error {
+	return i.err
+}
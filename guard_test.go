@@ -0,0 +1,54 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuardrails(t *testing.T) {
+	t.Run("MaxTablesCreated", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Guardrails: Guardrails{MaxTablesCreated: 1}})
+
+		name := "guarded-" + faker.UUIDDigit()
+		assert.NoError(t, dynamo.CreateTable(name, HashOnly{}))
+		defer dynamo.DeleteTable(name)
+
+		err := dynamo.CreateTable("guarded-"+faker.UUIDDigit(), HashOnly{})
+		assert.True(t, errors.Is(err, ErrTableQuotaExceeded))
+	})
+
+	t.Run("MaxBulkItems", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Guardrails: Guardrails{MaxBulkItems: 1}})
+
+		keys := []*DynamodbKey{}
+		for i := 0; 2 > i; i++ {
+			id := faker.UUIDDigit()
+			keys = append(keys, &DynamodbKey{
+				Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), id },
+			})
+		}
+
+		var items []HashOnly
+		err := dynamo.BatchGet(tableNameHashOnly, keys, &items)
+		assert.True(t, errors.Is(err, ErrBulkQuotaExceeded))
+	})
+
+	t.Run("MaxScanDuration", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{Guardrails: Guardrails{MaxScanDuration: time.Nanosecond}})
+
+		var items []HashOnly
+		err := dynamo.Scan(tableNameHashOnly, &items)
+		assert.True(t, errors.Is(err, ErrScanDurationExceeded))
+
+		iter := dynamo.ScanIter(tableNameHashOnly)
+		var item HashOnly
+		for iter.Next(context.Background(), &item) {
+		}
+		assert.True(t, errors.Is(iter.Err(), ErrScanDurationExceeded))
+	})
+}
@@ -0,0 +1,41 @@
+package dynamodb
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// Defaults of the HTTP client built when neither DynamodbConfig.HTTPClient nor the session sets one.
+// The SDK otherwise uses http.DefaultClient, which keeps only two idle connections per host and
+// reconnects under load.
+const (
+	DefaultMaxIdleConnsPerHost = 100
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// httpClient returns the HTTP client of config, or nil to keep the client of the session.
+func httpClient(sess *session.Session, config *DynamodbConfig) *http.Client {
+	base := config.HTTPClient
+	if base == nil && sess.Config.HTTPClient != nil && sess.Config.HTTPClient != http.DefaultClient {
+		base = sess.Config.HTTPClient
+	}
+	if base != nil {
+		if config.RequestTimeout <= 0 || base.Timeout == config.RequestTimeout {
+			return base
+		}
+		client := *base
+		client.Timeout = config.RequestTimeout
+		return &client
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 0
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	if transport.MaxIdleConnsPerHost < 1 {
+		transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	return &http.Client{Transport: transport, Timeout: config.RequestTimeout}
+}
@@ -0,0 +1,49 @@
+package dynamodb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClient(t *testing.T) {
+	sess := session.New()
+
+	t.Run("Success: defaults", func(t *testing.T) {
+		client := httpClient(sess, &DynamodbConfig{RequestTimeout: time.Second})
+		if assert.NotNil(t, client) {
+			assert.Equal(t, time.Second, client.Timeout)
+			assert.Equal(t, DefaultMaxIdleConnsPerHost, client.Transport.(*http.Transport).MaxIdleConnsPerHost)
+		}
+	})
+
+	t.Run("Success: max idle connections", func(t *testing.T) {
+		client := httpClient(sess, &DynamodbConfig{MaxIdleConnsPerHost: 8})
+		assert.Equal(t, 8, client.Transport.(*http.Transport).MaxIdleConnsPerHost)
+	})
+
+	t.Run("Success: configured client", func(t *testing.T) {
+		custom := &http.Client{}
+		assert.Same(t, custom, httpClient(sess, &DynamodbConfig{HTTPClient: custom}))
+
+		client := httpClient(sess, &DynamodbConfig{HTTPClient: custom, RequestTimeout: time.Second})
+		assert.Equal(t, time.Second, client.Timeout)
+		assert.Zero(t, custom.Timeout)
+	})
+
+	t.Run("Success: session client kept", func(t *testing.T) {
+		custom := &http.Client{}
+		assert.Same(t, custom, httpClient(session.New(aws.NewConfig().WithHTTPClient(custom)), &DynamodbConfig{}))
+	})
+
+	t.Run("Success: connected", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{RequestTimeout: 10 * time.Second, MaxRetries: -1})
+		_, err := dynamo.ListTables(context.Background())
+		assert.NoError(t, err)
+	})
+}
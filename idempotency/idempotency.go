@@ -0,0 +1,150 @@
+// Package idempotency runs operations at most once per idempotency key, such as the handlers of
+// payment webhooks delivered more than once, recording their results in a DynamoDB table:
+//
+//	store := idempotency.New(db, idempotency.Options{})
+//	var charge Charge
+//	err := store.Execute(ctx, event.ID, 24*time.Hour, &charge, func() (interface{}, error) {
+//		return payments.Charge(event)
+//	})
+//
+// A retry of a completed key returns the recorded result without running the operation again.
+// A failed operation is not recorded, so it runs again on the next retry.
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linksports/dynamodb"
+)
+
+// DefaultTable is the table used when Options.Table is unset.
+const DefaultTable = "idempotency_keys"
+
+// DefaultInProgressTTL is how long a key stays in progress when Options.InProgressTTL is unset.
+const DefaultInProgressTTL = time.Minute
+
+// ErrInProgress is returned by Execute while another call runs the operation of the same key.
+var ErrInProgress = errors.New("operation in progress")
+
+// Record statuses
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// Options :
+type Options struct {
+	// Table is created by CreateTable, with TTL on ExpiresAt. Defaults to DefaultTable.
+	Table string
+	// InProgressTTL bounds the time a key is held by a call that crashed before completing it.
+	// It must exceed the duration of the operation. Defaults to DefaultInProgressTTL.
+	InProgressTTL time.Duration
+}
+
+// Record is an item of the table.
+type Record struct {
+	Key    string `dynamo:"Key,hash"`
+	Status string `dynamo:"Status"`
+	// Result is the JSON encoded result of a completed operation.
+	Result string `dynamo:"Result"`
+	// ExpiresAt in Unix seconds, after which the key may run again.
+	ExpiresAt int64 `dynamo:"ExpiresAt"`
+}
+
+// Store :
+type Store struct {
+	db   dynamodb.Dynamodb
+	opts Options
+}
+
+// New :
+func New(db dynamodb.Dynamodb, opts Options) *Store {
+	if len(opts.Table) < 1 {
+		opts.Table = DefaultTable
+	}
+	if opts.InProgressTTL <= 0 {
+		opts.InProgressTTL = DefaultInProgressTTL
+	}
+	return &Store{db: db, opts: opts}
+}
+
+// CreateTable creates the table unless it exists, expiring the records with TTL.
+func (s *Store) CreateTable(ctx context.Context) error {
+	return s.db.ApplyTableSpec(ctx, dynamodb.TableSpec{
+		Name:         s.opts.Table,
+		HashKey:      "Key",
+		OnDemand:     true,
+		TTLAttribute: "ExpiresAt",
+	})
+}
+
+// Execute runs fn once for key and records its result, a JSON-encodable value, for ttl.
+// The result of fn, or the recorded one on a retry, is unmarshaled into result, a pointer.
+// It fails with ErrInProgress while another call runs fn for key.
+func (s *Store) Execute(ctx context.Context, key string, ttl time.Duration, result interface{}, fn func() (interface{}, error)) error {
+	if len(key) < 1 {
+		return errors.New("key empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := s.db.Clock().Now()
+	_, err := s.db.PutIf(s.opts.Table, Record{
+		Key:       key,
+		Status:    StatusInProgress,
+		ExpiresAt: now.Add(s.opts.InProgressTTL).Unix(),
+	}, dynamodb.FilterOr(
+		dynamodb.FilterNotExists("Key"),
+		dynamodb.FilterAttr("ExpiresAt", dynamodb.DynamodbLess, now.Unix()),
+	))
+	if errors.Is(err, dynamodb.ErrConditionFailed) {
+		return s.recorded(key, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	value, err := fn()
+	if err != nil {
+		s.db.DeleteIf(s.opts.Table, s.key(key), dynamodb.FilterAttr("Status", dynamodb.DynamodbEqual, StatusInProgress))
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Put(s.opts.Table, Record{
+		Key:       key,
+		Status:    StatusCompleted,
+		Result:    string(data),
+		ExpiresAt: s.db.ExpiresIn(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("record %s: %w", key, err)
+	}
+	return json.Unmarshal(data, result)
+}
+
+// recorded unmarshals the recorded result of key into result.
+func (s *Store) recorded(key string, result interface{}) error {
+	var record Record
+	if err := s.db.Get(s.opts.Table, s.key(key), &record); err != nil {
+		return err
+	}
+	if record.Status != StatusCompleted {
+		return fmt.Errorf("%w: %s", ErrInProgress, key)
+	}
+	return json.Unmarshal([]byte(record.Result), result)
+}
+
+func (s *Store) key(key string) dynamodb.DynamodbKey {
+	return dynamodb.DynamodbKey{
+		Hash: func() (string, interface{}) { return "Key", key },
+	}
+}
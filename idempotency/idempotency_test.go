@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type Charge struct {
+	ID     string
+	Amount int
+}
+
+func newDynamo(t *testing.T) dynamodb.Dynamodb {
+	return newDynamoWithClock(t, nil)
+}
+
+func newDynamoWithClock(t *testing.T, clock dynamodb.Clock) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+		Clock:    clock,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func TestExecute(t *testing.T) {
+	db := newDynamo(t)
+	ctx := context.Background()
+
+	table := "idempotency-" + faker.UUIDDigit()
+	defer db.DeleteTableWithContext(ctx, table)
+	store := New(db, Options{Table: table})
+	if !assert.NoError(t, store.CreateTable(ctx)) {
+		t.FailNow()
+	}
+
+	t.Run("Success: recorded result", func(t *testing.T) {
+		key := faker.UUIDDigit()
+		runs := 0
+		charge := func() (interface{}, error) {
+			runs++
+			return Charge{ID: faker.UUIDDigit(), Amount: 100}, nil
+		}
+
+		var first, second Charge
+		assert.NoError(t, store.Execute(ctx, key, time.Hour, &first, charge))
+		assert.NoError(t, store.Execute(ctx, key, time.Hour, &second, charge))
+		assert.Equal(t, 1, runs)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Success: failure not recorded", func(t *testing.T) {
+		key := faker.UUIDDigit()
+		var charge Charge
+		err := store.Execute(ctx, key, time.Hour, &charge, func() (interface{}, error) {
+			return nil, errors.New("declined")
+		})
+		assert.Error(t, err)
+
+		assert.NoError(t, store.Execute(ctx, key, time.Hour, &charge, func() (interface{}, error) {
+			return Charge{ID: "retried"}, nil
+		}))
+		assert.Equal(t, "retried", charge.ID)
+	})
+
+	t.Run("Success: expired on the Clock of the client", func(t *testing.T) {
+		clock := dynamodb.NewManualClock(time.Now())
+		store := New(newDynamoWithClock(t, clock), Options{Table: table})
+
+		key := faker.UUIDDigit()
+		runs := 0
+		charge := func() (interface{}, error) {
+			runs++
+			return Charge{ID: faker.UUIDDigit(), Amount: runs}, nil
+		}
+
+		var first, second Charge
+		assert.NoError(t, store.Execute(ctx, key, time.Hour, &first, charge))
+		clock.Advance(2 * time.Hour)
+		assert.NoError(t, store.Execute(ctx, key, time.Hour, &second, charge))
+		assert.Equal(t, 2, runs)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("Failure: in progress", func(t *testing.T) {
+		key := faker.UUIDDigit()
+		started, done := make(chan struct{}), make(chan struct{})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var charge Charge
+			store.Execute(ctx, key, time.Hour, &charge, func() (interface{}, error) {
+				close(started)
+				<-done
+				return Charge{}, nil
+			})
+		}()
+
+		<-started
+		var charge Charge
+		err := store.Execute(ctx, key, time.Hour, &charge, func() (interface{}, error) {
+			return Charge{}, nil
+		})
+		assert.True(t, errors.Is(err, ErrInProgress))
+		close(done)
+		wg.Wait()
+	})
+}
@@ -0,0 +1,98 @@
+package dynamodb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+	"time"
+)
+
+// IDGenerator makes hash keys for items put without one.
+type IDGenerator interface {
+	NewID() (string, error)
+}
+
+// UUIDGenerator generates random version 4 UUIDs.
+type UUIDGenerator struct{}
+
+func (UUIDGenerator) NewID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	s := hex.EncodeToString(b[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+// ULIDGenerator generates ULIDs, which sort by creation time to the millisecond.
+type ULIDGenerator struct {
+	// Clock defaults to the system clock.
+	Clock Clock
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func (g ULIDGenerator) NewID() (string, error) {
+	now := time.Now()
+	if g.Clock != nil {
+		now = g.Clock.Now()
+	}
+
+	// 48 bits of milliseconds followed by 80 random bits.
+	var b [16]byte
+	ms := uint64(now.UnixNano() / int64(time.Millisecond))
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits are 26 base32 digits, the first holding only 3 bits.
+	var id [26]byte
+	for i := 25; i >= 0; i-- {
+		var rem byte
+		for j := range b {
+			v := uint16(rem)<<8 | uint16(b[j])
+			b[j], rem = byte(v/32), byte(v%32)
+		}
+		id[i] = crockford[rem]
+	}
+	return string(id[:]), nil
+}
+
+// generateID fills the empty string hash key of item with a generated ID. A pointer item is
+// updated in place so the caller sees the ID; any other item is copied.
+func (con *dynamodb) generateID(item interface{}) (interface{}, string, error) {
+	if con.config.IDGenerator == nil {
+		return item, "", nil
+	}
+
+	rv := reflect.ValueOf(item)
+	fields := taggedFields(rv, "hash")
+	if len(fields) < 1 {
+		return item, "", nil
+	}
+	field := fields[0]
+	if field.value.Kind() != reflect.String || field.value.Len() > 0 {
+		return item, "", nil
+	}
+
+	id, err := con.config.IDGenerator.NewID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if field.value.CanSet() {
+		field.value.SetString(id)
+		return item, id, nil
+	}
+
+	copied := reflect.New(rv.Type())
+	copied.Elem().Set(rv)
+	taggedFields(copied, "hash")[0].value.SetString(id)
+	return copied.Interface(), id, nil
+}
@@ -0,0 +1,69 @@
+package dynamodb
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDGenerator(t *testing.T) {
+	id, err := UUIDGenerator{}.NewID()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+
+	other, err := UUIDGenerator{}.NewID()
+	assert.NoError(t, err)
+	assert.NotEqual(t, id, other)
+}
+
+func TestULIDGenerator(t *testing.T) {
+	clock := NewManualClock(time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC))
+	gen := ULIDGenerator{Clock: clock}
+
+	first, err := gen.NewID()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), first)
+
+	clock.Set(clock.Now().Add(time.Millisecond))
+	second, err := gen.NewID()
+	assert.NoError(t, err)
+	assert.Less(t, first, second)
+	assert.Equal(t, first[:9], second[:9])
+}
+
+func TestPutGeneratedID(t *testing.T) {
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{IDGenerator: UUIDGenerator{}})
+
+	t.Run("Success: pointer", func(t *testing.T) {
+		item := &HashOnly{Name: "generated"}
+		res, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.GeneratedID)
+		assert.Equal(t, res.GeneratedID, item.Id)
+
+		var got HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+		}, &got))
+		assert.Equal(t, "generated", got.Name)
+	})
+
+	t.Run("Success: value", func(t *testing.T) {
+		res, err := dynamo.Put(tableNameHashOnly, HashOnly{Name: "generated"})
+		assert.NoError(t, err)
+
+		var got HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", res.GeneratedID },
+		}, &got))
+		assert.Equal(t, "generated", got.Name)
+	})
+
+	t.Run("Success: key set", func(t *testing.T) {
+		res, err := dynamo.Put(tableNameHashOnly, HashOnly{Id: "given"})
+		assert.NoError(t, err)
+		assert.Empty(t, res.GeneratedID)
+	})
+}
@@ -0,0 +1,213 @@
+package dynamodb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxItemSize is the largest item DynamoDB stores.
+const maxItemSize = 400 * 1024
+
+// ImportCSV reads a header row of attribute names followed by one item per row.
+const ImportCSV ExportFormat = "CSV"
+
+// ImportOptions :
+type ImportOptions struct {
+	// Format is ExportJSONLines, ExportDynamoDBJSON or ImportCSV, so files written by ExportTable can be read back.
+	// Plain JSON has no binary type, so binary attributes are imported as base64 strings.
+	Format ExportFormat
+	// Types sets the type of CSV columns, which are strings by default. Empty cells are left out.
+	Types map[string]DynamodbKeyType
+	// RateLimit caps the items written per second. Zero is unlimited.
+	RateLimit   int
+	Concurrency int
+	// Progress is called with the number of items written so far after every batch, one call at a time.
+	Progress func(written int)
+}
+
+// ImportTable writes the records read from r in batches of 25, like BulkPut, and returns the number
+// of items written. Unprocessed items are retried and DynamodbConfig.DeadLetters applies.
+func (con *dynamodb) ImportTable(ctx context.Context, tableName string, r io.Reader, opts ImportOptions) (int, error) {
+	var next func() (map[string]*awsDynamodb.AttributeValue, error)
+	switch opts.Format {
+	case ExportJSONLines, ExportDynamoDBJSON:
+		next = jsonLineReader(r, opts.Format)
+	case ImportCSV:
+		var err error
+		if next, err = csvReader(r, opts.Types); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, errors.New("unknown import format")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var readErr error
+	batches := make(chan []interface{})
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(batches)
+
+		started := time.Now()
+		sent := 0
+		for {
+			batch := make([]interface{}, 0, maxBatchWrite)
+			for len(batch) < maxBatchWrite {
+				item, err := next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					readErr = err
+					cancel()
+					return
+				}
+				batch = append(batch, item)
+			}
+			if len(batch) < 1 {
+				return
+			}
+
+			if opts.RateLimit > 0 {
+				due := started.Add(time.Duration(sent) * time.Second / time.Duration(opts.RateLimit))
+				if err := sleep(ctx, time.Until(due)); err != nil {
+					return
+				}
+			}
+
+			select {
+			case batches <- batch:
+				sent += len(batch)
+			case <-ctx.Done():
+				return
+			}
+			if len(batch) < maxBatchWrite {
+				return
+			}
+		}
+	}()
+
+	written, err := con.writeBatches(ctx, cancel, tableName, batches, produced, BulkPutOptions{Concurrency: opts.Concurrency}, opts.Progress)
+	<-produced
+	if readErr != nil {
+		return written, readErr
+	}
+	return written, err
+}
+
+func jsonLineReader(r io.Reader, format ExportFormat) func() (map[string]*awsDynamodb.AttributeValue, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxItemSize*4)
+	line := 0
+	return func() (map[string]*awsDynamodb.AttributeValue, error) {
+		for scanner.Scan() {
+			line++
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) < 1 {
+				continue
+			}
+
+			item, err := decodeJSONLine(data, format)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			return item, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+}
+
+func decodeJSONLine(data []byte, format ExportFormat) (map[string]*awsDynamodb.AttributeValue, error) {
+	if format == ExportDynamoDBJSON {
+		var record struct {
+			Item map[string]*awsDynamodb.AttributeValue
+		}
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		if record.Item == nil {
+			return nil, errors.New("no Item")
+		}
+		return record.Item, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var item map[string]interface{}
+	if err := decoder.Decode(&item); err != nil {
+		return nil, err
+	}
+	return attributeValue(item).M, nil
+}
+
+// attributeValue is the inverse of plainValue for values decoded with json.Decoder.UseNumber.
+func attributeValue(v interface{}) *awsDynamodb.AttributeValue {
+	switch v := v.(type) {
+	case string:
+		return &awsDynamodb.AttributeValue{S: aws.String(v)}
+	case json.Number:
+		return &awsDynamodb.AttributeValue{N: aws.String(v.String())}
+	case bool:
+		return &awsDynamodb.AttributeValue{BOOL: aws.Bool(v)}
+	case []interface{}:
+		list := make([]*awsDynamodb.AttributeValue, len(v))
+		for i, e := range v {
+			list[i] = attributeValue(e)
+		}
+		return &awsDynamodb.AttributeValue{L: list}
+	case map[string]interface{}:
+		m := make(map[string]*awsDynamodb.AttributeValue, len(v))
+		for name, e := range v {
+			m[name] = attributeValue(e)
+		}
+		return &awsDynamodb.AttributeValue{M: m}
+	}
+	return &awsDynamodb.AttributeValue{NULL: aws.Bool(true)}
+}
+
+func csvReader(r io.Reader, types map[string]DynamodbKeyType) (func() (map[string]*awsDynamodb.AttributeValue, error), error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	return func() (map[string]*awsDynamodb.AttributeValue, error) {
+		row, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]*awsDynamodb.AttributeValue, len(row))
+		for i, cell := range row {
+			if len(cell) < 1 {
+				continue
+			}
+			switch types[header[i]] {
+			case DynamodbKeyTypeNumber:
+				item[header[i]] = &awsDynamodb.AttributeValue{N: aws.String(cell)}
+			case DynamodbKeyTypeBinary:
+				item[header[i]] = &awsDynamodb.AttributeValue{B: []byte(cell)}
+			default:
+				item[header[i]] = &awsDynamodb.AttributeValue{S: aws.String(cell)}
+			}
+		}
+		return item, nil
+	}, nil
+}
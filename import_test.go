@@ -0,0 +1,74 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportTable(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "import-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	get := func(id string) HashOnly {
+		var datum HashOnly
+		assert.NoError(t, dynamo.Get(name, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", id },
+		}, &datum))
+		return datum
+	}
+
+	t.Run("Success: json lines", func(t *testing.T) {
+		var lines []string
+		for i := 0; i < 30; i++ {
+			lines = append(lines, `{"ID": "json-`+faker.UUIDDigit()+`", "Name": "imported", "Status": 3}`)
+		}
+		lines[0] = `{"ID": "json-first", "Name": "imported", "Status": 3}`
+
+		var progress []int
+		n, err := dynamo.ImportTable(ctx, name, strings.NewReader(strings.Join(lines, "\n")), ImportOptions{
+			Format:   ExportJSONLines,
+			Progress: func(written int) { progress = append(progress, written) },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 30, n)
+		assert.Len(t, progress, 2)
+		assert.Equal(t, 30, progress[1])
+		assert.Equal(t, 3, get("json-first").Status)
+	})
+
+	t.Run("Success: export round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		exported, err := dynamo.ExportTable(ctx, name, &buf, ExportDynamoDBJSON)
+		assert.NoError(t, err)
+
+		n, err := dynamo.ImportTable(ctx, name, &buf, ImportOptions{Format: ExportDynamoDBJSON, RateLimit: 1000})
+		assert.NoError(t, err)
+		assert.Equal(t, exported, n)
+	})
+
+	t.Run("Success: csv", func(t *testing.T) {
+		data := "ID,Name,Status\ncsv-1,first,1\ncsv-2,,2\n"
+		n, err := dynamo.ImportTable(ctx, name, strings.NewReader(data), ImportOptions{
+			Format: ImportCSV,
+			Types:  map[string]DynamodbKeyType{"Status": DynamodbKeyTypeNumber},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, HashOnly{Id: "csv-2", Status: 2}, get("csv-2"))
+	})
+
+	t.Run("Failure: invalid line", func(t *testing.T) {
+		_, err := dynamo.ImportTable(ctx, name, strings.NewReader(`{"ID": "ok"}`+"\nnot json"), ImportOptions{Format: ExportJSONLines})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
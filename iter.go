@@ -0,0 +1,171 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// DynamodbIter iterates over query or scan results one item at a time.
+// Pages are fetched from DynamoDB as they are consumed, so memory use stays constant.
+type DynamodbIter interface {
+	// Next unmarshals the next item into out.
+	// It returns false when the results are exhausted or an error occurred.
+	Next(ctx context.Context, out interface{}) bool
+	// Err returns the error encountered, if any. Check it after Next returns false.
+	Err() error
+}
+
+type dynamodbIter struct {
+	con   *dynamodb
+	table string
+	iter  dynamo.Iter
+	err   error
+}
+
+func (i *dynamodbIter) Next(ctx context.Context, out interface{}) bool {
+	if i.err != nil {
+		return false
+	}
+	var ok bool
+	ok, i.err = i.con.next(ctx, i.table, i.iter, out)
+	return ok
+}
+
+func (i *dynamodbIter) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.iter.Err()
+}
+
+func (con *dynamodb) QueryIter(tableName string, key DynamodbKey) DynamodbIter {
+	table := con.table(tableName)
+	return &dynamodbIter{con: con, table: tableName, iter: query(&table, key).Iter()}
+}
+
+// GetAllStream is like QueryIter but requests at most pageSize items per round trip,
+// and only asks for the next page once the consumer has drained the current one.
+func (con *dynamodb) GetAllStream(tableName string, key DynamodbKey, pageSize int) DynamodbIter {
+	table := con.table(tableName)
+	if pageSize < 1 {
+		return &dynamodbIter{con: con, table: tableName, iter: query(&table, key).Iter()}
+	}
+
+	return &pagedIter{
+		con:   con,
+		table: tableName,
+		page: func(start dynamo.PagingKey) dynamo.PagingIter {
+			return query(&table, key).SearchLimit(int64(pageSize)).StartFrom(start).Iter()
+		},
+	}
+}
+
+type pagedIter struct {
+	con   *dynamodb
+	table string
+	page  func(start dynamo.PagingKey) dynamo.PagingIter
+	iter  dynamo.PagingIter
+	start dynamo.PagingKey
+	done  bool
+	err   error
+}
+
+func (i *pagedIter) Next(ctx context.Context, out interface{}) bool {
+	for !i.done && i.err == nil {
+		if i.iter == nil {
+			i.iter = i.page(i.start)
+		}
+
+		ok, err := i.con.next(ctx, i.table, i.iter, out)
+		if i.err = err; ok || err != nil {
+			return ok
+		}
+
+		if i.err = i.iter.Err(); i.err != nil {
+			return false
+		}
+
+		i.start = i.iter.LastEvaluatedKey()
+		i.iter = nil
+		i.done = i.start == nil
+	}
+
+	return false
+}
+
+func (i *pagedIter) Err() error {
+	return i.err
+}
+
+// ScanIter is only for script like Scan. Do not use from application.
+func (con *dynamodb) ScanIter(tableName string, filters ...ScanFilter) DynamodbIter {
+	iter := &dynamodbIter{con: con, table: tableName, iter: scan(con.table(tableName), filters...).Iter()}
+	if max := con.config.Guardrails.MaxScanDuration; max > 0 {
+		return &guardedIter{iter: iter, deadline: time.Now().Add(max)}
+	}
+	return iter
+}
+
+// GetAllEach unmarshals the items of key one at a time into out, a pointer reused for every item,
+// and calls fn after each. Pages are decoded as they arrive and no result slice is built, which keeps
+// memory flat for large reads; copy out in fn to keep an item. An error from fn stops and is returned.
+func (con *dynamodb) GetAllEach(ctx context.Context, tableName string, key DynamodbKey, out interface{}, fn func() error) error {
+	table := con.table(tableName)
+	return con.each(ctx, tableName, query(&table, key).Iter(), out, fn)
+}
+
+// ScanEach is GetAllEach for a scan of the table.
+func (con *dynamodb) ScanEach(ctx context.Context, tableName string, out interface{}, fn func() error, filters ...ScanFilter) error {
+	ctx, cancel := con.scanContext(ctx)
+	defer cancel()
+
+	err := con.each(ctx, tableName, scan(con.table(tableName), filters...).Iter(), out, fn)
+	return scanError(ctx, err)
+}
+
+func (con *dynamodb) each(ctx context.Context, tableName string, iter dynamo.Iter, out interface{}, fn func() error) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("out must be a non-nil pointer")
+	}
+	zero := reflect.Zero(rv.Elem().Type())
+
+	for {
+		// Attributes missing from an item would otherwise keep the values of the previous one.
+		rv.Elem().Set(zero)
+
+		ok, err := con.next(ctx, tableName, iter, out)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return iter.Err()
+		}
+
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+}
+
+// next unmarshals the next item of iter, a read of tableName, into out, decoding its coded, gzip and encrypted fields.
+// It returns false when the results are exhausted, with the error of iter left in iter.Err.
+func (con *dynamodb) next(ctx context.Context, tableName string, iter dynamo.Iter, out interface{}) (bool, error) {
+	if !hasCodedFields(out) {
+		return iter.NextWithContext(ctx, out), nil
+	}
+
+	var av map[string]*awsDynamodb.AttributeValue
+	if !iter.NextWithContext(ctx, &av) {
+		return false, nil
+	}
+	if err := con.decodeItem(tableName, av, out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,139 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryIter(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKey := faker.UUIDDigit()
+	now := time.Now()
+
+	n := rand.Intn(20) + 1
+	for i := 0; n > i; i++ {
+		var d HashAndRange
+		faker.FakeData(&d)
+		d.Id = hashKey
+		d.CreatedAt = now.AddDate(0, 0, i).String()
+		dynamo.Put(tableNameHashAndRange, &d)
+	}
+
+	iter := dynamo.QueryIter(tableNameHashAndRange, DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+	})
+
+	count := 0
+	var item HashAndRange
+	for iter.Next(context.Background(), &item) {
+		assert.Equal(t, hashKey, item.Id)
+		count++
+	}
+
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, n, count)
+}
+
+func TestGetAllStream(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKey := faker.UUIDDigit()
+	now := time.Now()
+
+	n := rand.Intn(20) + 1
+	for i := 0; n > i; i++ {
+		var d HashAndRange
+		faker.FakeData(&d)
+		d.Id = hashKey
+		d.CreatedAt = now.AddDate(0, 0, i).String()
+		dynamo.Put(tableNameHashAndRange, &d)
+	}
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+	}
+
+	for _, pageSize := range []int{0, 1, 3} {
+		iter := dynamo.GetAllStream(tableNameHashAndRange, key, pageSize)
+
+		count := 0
+		var item HashAndRange
+		for iter.Next(context.Background(), &item) {
+			count++
+		}
+
+		assert.NoError(t, iter.Err())
+		assert.Equal(t, n, count)
+	}
+}
+
+func TestScanIter(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	var expect HashOnly
+	faker.FakeData(&expect)
+	dynamo.Put(tableNameHashOnly, &expect)
+
+	iter := dynamo.ScanIter(tableNameHashOnly, ScanFilter{Expr: "ID = ?", Value: expect.Id})
+
+	var items []HashOnly
+	var item HashOnly
+	for iter.Next(context.Background(), &item) {
+		items = append(items, item)
+	}
+
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, []HashOnly{expect}, items)
+}
+
+func TestGetAllEach(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKey := faker.UUIDDigit()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		d := HashAndRange{Id: hashKey, CreatedAt: now.AddDate(0, 0, i).Format(time.RFC3339)}
+		if i == 0 {
+			d.Name = "first"
+		}
+		dynamo.Put(tableNameHashAndRange, &d)
+	}
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var names []string
+		var item HashAndRange
+		err := dynamo.GetAllEach(context.Background(), tableNameHashAndRange, key, &item, func() error {
+			assert.Equal(t, hashKey, item.Id)
+			names = append(names, item.Name)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "", "", "", ""}, names)
+	})
+
+	t.Run("Failure: callback error stops", func(t *testing.T) {
+		count := 0
+		var item HashAndRange
+		err := dynamo.GetAllEach(context.Background(), tableNameHashAndRange, key, &item, func() error {
+			count++
+			return errors.New("stop")
+		})
+		assert.EqualError(t, err, "stop")
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Failure: out not a pointer", func(t *testing.T) {
+		err := dynamo.GetAllEach(context.Background(), tableNameHashAndRange, key, HashAndRange{}, func() error { return nil })
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,130 @@
+// Package kv is a key-value store on a DynamoDB table, for sessions, feature flags and other data
+// needing no schema:
+//
+//	store := kv.New(db, "sessions")
+//	err := store.Set("session:"+id, session, 24*time.Hour)
+//	err = store.Get("session:"+id, &session)
+//
+// The table is created on first use, with TTL removing expired keys.
+package kv
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+	"github.com/linksports/dynamodb"
+)
+
+// Attributes of the items of the table.
+const (
+	KeyAttribute     = "Key"
+	ValueAttribute   = "Value"
+	ExpiresAttribute = "ExpiresAt"
+)
+
+// ErrNotFound is returned by Get for a missing or expired key.
+var ErrNotFound = errors.New("key not found")
+
+// Store :
+type Store struct {
+	db    dynamodb.Dynamodb
+	table string
+
+	mu      sync.Mutex
+	created bool
+}
+
+// New :
+func New(db dynamodb.Dynamodb, table string) *Store {
+	return &Store{db: db, table: table}
+}
+
+// ensureTable creates the table on first use. A failure is retried on the next call.
+func (s *Store) ensureTable() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.created {
+		return nil
+	}
+
+	err := s.db.ApplyTableSpec(context.Background(), dynamodb.TableSpec{
+		Name:         s.table,
+		HashKey:      KeyAttribute,
+		OnDemand:     true,
+		TTLAttribute: ExpiresAttribute,
+	})
+	s.created = err == nil
+	return err
+}
+
+// Set stores value, marshaled like the attributes of an item, under key for ttl. A zero ttl never expires.
+func (s *Store) Set(key string, value interface{}, ttl time.Duration) error {
+	if err := s.ensureTable(); err != nil {
+		return err
+	}
+
+	av, err := dynamo.Marshal(value)
+	if err != nil {
+		return err
+	}
+	item := map[string]*awsDynamodb.AttributeValue{
+		KeyAttribute:   {S: &key},
+		ValueAttribute: av,
+	}
+	if ttl > 0 {
+		expires, err := dynamo.Marshal(s.db.ExpiresIn(ttl))
+		if err != nil {
+			return err
+		}
+		item[ExpiresAttribute] = expires
+	}
+	_, err = s.db.Put(s.table, item)
+	return err
+}
+
+// Get unmarshals the value of key into v, a pointer, or fails with ErrNotFound.
+// Expired keys are not found, even before TTL removes them.
+func (s *Store) Get(key string, v interface{}) error {
+	if err := s.ensureTable(); err != nil {
+		return err
+	}
+
+	var item map[string]*awsDynamodb.AttributeValue
+	err := s.db.Get(s.table, s.key(key), &item)
+	if errors.Is(err, dynamo.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if expires := item[ExpiresAttribute]; expires != nil {
+		var at int64
+		if err := dynamo.Unmarshal(expires, &at); err != nil {
+			return err
+		}
+		if at <= s.db.Clock().Now().Unix() {
+			return ErrNotFound
+		}
+	}
+	return dynamo.Unmarshal(item[ValueAttribute], v)
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (s *Store) Delete(key string) error {
+	if err := s.ensureTable(); err != nil {
+		return err
+	}
+	_, err := s.db.Delete(s.table, s.key(key))
+	return err
+}
+
+func (s *Store) key(key string) dynamodb.DynamodbKey {
+	return dynamodb.DynamodbKey{
+		Hash: func() (string, interface{}) { return KeyAttribute, key },
+	}
+}
@@ -0,0 +1,85 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type Session struct {
+	UserID string
+	Roles  []string
+}
+
+func newDynamo(t *testing.T) dynamodb.Dynamodb {
+	return newDynamoWithClock(t, nil)
+}
+
+func newDynamoWithClock(t *testing.T, clock dynamodb.Clock) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+		Clock:    clock,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func TestStore(t *testing.T) {
+	db := newDynamo(t)
+	table := "kv-" + faker.UUIDDigit()
+	defer db.DeleteTableWithContext(context.Background(), table)
+	store := New(db, table)
+
+	t.Run("Success", func(t *testing.T) {
+		session := Session{UserID: faker.UUIDDigit(), Roles: []string{"admin"}}
+		assert.NoError(t, store.Set("session", session, time.Hour))
+
+		var got Session
+		assert.NoError(t, store.Get("session", &got))
+		assert.Equal(t, session, got)
+
+		assert.NoError(t, store.Delete("session"))
+		assert.True(t, errors.Is(store.Get("session", &got), ErrNotFound))
+	})
+
+	t.Run("Success: no expiry", func(t *testing.T) {
+		assert.NoError(t, store.Set("flag", true, 0))
+
+		var got bool
+		assert.NoError(t, store.Get("flag", &got))
+		assert.True(t, got)
+	})
+
+	t.Run("Failure: expired", func(t *testing.T) {
+		assert.NoError(t, store.Set("expired", "value", time.Millisecond))
+		time.Sleep(time.Second)
+
+		var got string
+		assert.True(t, errors.Is(store.Get("expired", &got), ErrNotFound))
+	})
+
+	t.Run("Failure: expired on the Clock of the client", func(t *testing.T) {
+		clock := dynamodb.NewManualClock(time.Now())
+		store := New(newDynamoWithClock(t, clock), table)
+		assert.NoError(t, store.Set("clocked", "value", time.Hour))
+
+		var got string
+		assert.NoError(t, store.Get("clocked", &got))
+		clock.Advance(2 * time.Hour)
+		assert.True(t, errors.Is(store.Get("clocked", &got), ErrNotFound))
+	})
+
+	t.Run("Failure: missing", func(t *testing.T) {
+		var got string
+		assert.True(t, errors.Is(store.Get(faker.UUIDDigit(), &got), ErrNotFound))
+	})
+}
@@ -0,0 +1,79 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/guregu/dynamo"
+)
+
+// ErrVersionConflict is returned by Put in OptimisticLock mode
+// when the stored item was modified since it was read.
+var ErrVersionConflict = errors.New("version conflict")
+
+// Versioned items are checked by optimistic locking.
+// Alternatively tag an integer field with version, e.g. `dynamo:"Version,version"`.
+type Versioned interface {
+	VersionKey() string
+	GetVersion() int64
+	SetVersion(version int64)
+}
+
+type versionLock struct {
+	name    string
+	version int64
+	set     func(version int64)
+}
+
+// lockVersion increments the version of item and returns the lock to apply to its put,
+// or nil when OptimisticLock is disabled or item is not versioned.
+func (con *dynamodb) lockVersion(item interface{}) (*versionLock, error) {
+	if !con.config.OptimisticLock {
+		return nil, nil
+	}
+
+	var lock *versionLock
+	if v, ok := item.(Versioned); ok {
+		lock = &versionLock{name: v.VersionKey(), version: v.GetVersion(), set: v.SetVersion}
+	} else if fields := taggedFields(reflect.ValueOf(item), "version"); len(fields) > 0 {
+		field := fields[0]
+		switch field.value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			return nil, fmt.Errorf("version field %s must be an integer", field.name)
+		}
+		if !field.value.CanSet() {
+			return nil, errors.New("versioned item must be a pointer")
+		}
+		lock = &versionLock{name: field.name, version: field.value.Int(), set: field.value.SetInt}
+	} else {
+		return nil, nil
+	}
+
+	lock.set(lock.version + 1)
+	return lock, nil
+}
+
+func (l *versionLock) apply(req *dynamo.Put) *dynamo.Put {
+	if l == nil {
+		return req
+	}
+	if l.version == 0 {
+		return req.If("attribute_not_exists($)", l.name)
+	}
+	return req.If("$ = ?", l.name, l.version)
+}
+
+// result restores the previous version when the put failed.
+func (l *versionLock) result(err error) error {
+	if l == nil || err == nil {
+		return err
+	}
+
+	l.set(l.version)
+	if isConditionalCheckFailed(err) {
+		return ErrVersionConflict
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type TaggedVersion struct {
+	Id      string `dynamo:"ID,hash"`
+	Name    string `dynamo:"Name"`
+	Version int    `dynamo:"Version,version"`
+}
+
+type InterfaceVersion struct {
+	Id  string `dynamo:"ID,hash"`
+	Rev int64  `dynamo:"Rev"`
+}
+
+func (InterfaceVersion) VersionKey() string {
+	return "Rev"
+}
+func (v *InterfaceVersion) GetVersion() int64 {
+	return v.Rev
+}
+func (v *InterfaceVersion) SetVersion(version int64) {
+	v.Rev = version
+}
+
+func TestOptimisticLock(t *testing.T) {
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{OptimisticLock: true})
+
+	t.Run("Tag", func(t *testing.T) {
+		item := TaggedVersion{Id: faker.UUIDDigit(), Name: faker.Name()}
+		stale := item
+
+		_, err := dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, item.Version)
+
+		_, err = dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, item.Version)
+
+		_, err = dynamo.Put(tableNameHashOnly, &stale)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+		assert.Equal(t, 0, stale.Version)
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		item := InterfaceVersion{Id: faker.UUIDDigit()}
+		stale := item
+
+		_, err := dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), item.Rev)
+
+		var old InterfaceVersion
+		res, err := dynamo.PutWithOldValue(tableNameHashOnly, &item, &old)
+		assert.NoError(t, err)
+		assert.True(t, res.HasOldValue)
+		assert.Equal(t, int64(1), old.Rev)
+		assert.Equal(t, int64(2), item.Rev)
+
+		_, err = dynamo.Put(tableNameHashOnly, &stale)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+	})
+
+	t.Run("Failure: not a pointer", func(t *testing.T) {
+		_, err := dynamo.Put(tableNameHashOnly, TaggedVersion{Id: faker.UUIDDigit()})
+		assert.Error(t, err)
+	})
+}
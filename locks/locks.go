@@ -0,0 +1,174 @@
+// Package locks provides distributed locks held as leases on the items of a DynamoDB table, such as
+// for leader election between workers:
+//
+//	lock, err := locks.AcquireLock(ctx, db, "locks", "report-job", 30*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Release(ctx)
+//	for work() {
+//		if err := lock.Heartbeat(ctx); err != nil {
+//			return err // another worker took over
+//		}
+//	}
+//
+// A lease is taken over once expired, so a holder must call Heartbeat well within the ttl and stop
+// working when it fails. Expiry is compared on the clocks of the workers, read through the Clock of
+// the client, which must be synchronized to a fraction of the ttl.
+package locks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linksports/dynamodb"
+)
+
+// HashKey is the hash key of the lock table, a string.
+const HashKey = "LockID"
+
+// DefaultPollInterval is how often AcquireLock retries a held lock.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// Errors returned by locks.
+var (
+	// ErrLockHeld is returned by TryAcquireLock while another holder's lease is valid.
+	ErrLockHeld = errors.New("lock held")
+	// ErrLockLost is returned by Heartbeat and Release once another holder took over the lock.
+	ErrLockLost = errors.New("lock lost")
+)
+
+// Record is an item of the lock table.
+type Record struct {
+	LockID string `dynamo:"LockID,hash"`
+	// Token identifies one acquisition of the lock.
+	Token string `dynamo:"Token"`
+	// ExpiresAt is the end of the lease in Unix milliseconds.
+	ExpiresAt int64 `dynamo:"ExpiresAt"`
+}
+
+// Lock is a lease on a lock, valid until it expires or is released.
+type Lock struct {
+	db     dynamodb.Dynamodb
+	table  string
+	id     string
+	token  string
+	ttl    time.Duration
+	mu     sync.Mutex
+	expiry time.Time
+}
+
+// CreateTable creates the lock table unless it exists.
+func CreateTable(ctx context.Context, db dynamodb.Dynamodb, table string) error {
+	return db.ApplyTableSpec(ctx, dynamodb.TableSpec{Name: table, HashKey: HashKey, OnDemand: true})
+}
+
+// AcquireLock takes the lock lockID for ttl, waiting while another holder has it until ctx is done.
+func AcquireLock(ctx context.Context, db dynamodb.Dynamodb, table, lockID string, ttl time.Duration) (*Lock, error) {
+	for {
+		lock, err := TryAcquireLock(db, table, lockID, ttl)
+		if !errors.Is(err, ErrLockHeld) {
+			return lock, err
+		}
+
+		select {
+		case <-time.After(DefaultPollInterval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", err, ctx.Err())
+		}
+	}
+}
+
+// TryAcquireLock takes the lock lockID for ttl, or fails with ErrLockHeld.
+func TryAcquireLock(db dynamodb.Dynamodb, table, lockID string, ttl time.Duration) (*Lock, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := db.Clock().Now()
+	expiry := now.Add(ttl)
+	_, err = db.PutIf(table, Record{LockID: lockID, Token: token, ExpiresAt: millis(expiry)},
+		dynamodb.FilterOr(
+			dynamodb.FilterNotExists(HashKey),
+			dynamodb.FilterAttr("ExpiresAt", dynamodb.DynamodbLess, millis(now)),
+		),
+	)
+	if errors.Is(err, dynamodb.ErrConditionFailed) {
+		return nil, fmt.Errorf("%w: %s", ErrLockHeld, lockID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{db: db, table: table, id: lockID, token: token, ttl: ttl, expiry: expiry}, nil
+}
+
+// ID :
+func (l *Lock) ID() string {
+	return l.id
+}
+
+// ExpiresAt is the end of the lease as of the last Heartbeat.
+func (l *Lock) ExpiresAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.expiry
+}
+
+// Heartbeat extends the lease by the ttl, or fails with ErrLockLost.
+func (l *Lock) Heartbeat(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	expiry := l.db.Clock().Now().Add(l.ttl)
+	_, err := l.db.PutIf(l.table, Record{LockID: l.id, Token: l.token, ExpiresAt: millis(expiry)}, l.held())
+	if errors.Is(err, dynamodb.ErrConditionFailed) {
+		return fmt.Errorf("%w: %s", ErrLockLost, l.id)
+	}
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expiry = expiry
+	return nil
+}
+
+// Release frees the lock for other holders, or fails with ErrLockLost.
+func (l *Lock) Release(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := l.db.DeleteIf(l.table, dynamodb.DynamodbKey{
+		Hash: func() (string, interface{}) { return HashKey, l.id },
+	}, l.held())
+	if errors.Is(err, dynamodb.ErrConditionFailed) {
+		return fmt.Errorf("%w: %s", ErrLockLost, l.id)
+	}
+	return err
+}
+
+// held is the condition of the item holding this acquisition.
+func (l *Lock) held() dynamodb.ScanFilter {
+	return dynamodb.FilterAttr("Token", dynamodb.DynamodbEqual, l.token)
+}
+
+func newToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,99 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDynamo(t *testing.T) dynamodb.Dynamodb {
+	return newDynamoWithClock(t, nil)
+}
+
+func newDynamoWithClock(t *testing.T, clock dynamodb.Clock) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+		Clock:    clock,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func TestLock(t *testing.T) {
+	db := newDynamo(t)
+	ctx := context.Background()
+
+	table := "locks-" + faker.UUIDDigit()
+	defer db.DeleteTableWithContext(ctx, table)
+	if !assert.NoError(t, CreateTable(ctx, db, table)) {
+		t.FailNow()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		lock, err := AcquireLock(ctx, db, table, "leader", time.Minute)
+		assert.NoError(t, err)
+
+		_, err = TryAcquireLock(db, table, "leader", time.Minute)
+		assert.True(t, errors.Is(err, ErrLockHeld))
+
+		expiry := lock.ExpiresAt()
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, lock.Heartbeat(ctx))
+		assert.True(t, lock.ExpiresAt().After(expiry))
+
+		assert.NoError(t, lock.Release(ctx))
+		other, err := TryAcquireLock(db, table, "leader", time.Minute)
+		assert.NoError(t, err)
+		assert.NoError(t, other.Release(ctx))
+	})
+
+	t.Run("Success: expired lease taken over", func(t *testing.T) {
+		lock, err := AcquireLock(ctx, db, table, "expiring", 50*time.Millisecond)
+		assert.NoError(t, err)
+
+		other, err := AcquireLock(ctx, db, table, "expiring", time.Minute)
+		assert.NoError(t, err)
+
+		assert.True(t, errors.Is(lock.Heartbeat(ctx), ErrLockLost))
+		assert.True(t, errors.Is(lock.Release(ctx), ErrLockLost))
+		assert.NoError(t, other.Release(ctx))
+	})
+
+	t.Run("Success: Clock of the client", func(t *testing.T) {
+		clock := dynamodb.NewManualClock(time.Now())
+		db := newDynamoWithClock(t, clock)
+
+		lock, err := TryAcquireLock(db, table, "clocked", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, clock.Now().Add(time.Minute), lock.ExpiresAt())
+
+		_, err = TryAcquireLock(db, table, "clocked", time.Minute)
+		assert.True(t, errors.Is(err, ErrLockHeld))
+
+		clock.Advance(2 * time.Minute)
+		other, err := TryAcquireLock(db, table, "clocked", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, errors.Is(lock.Heartbeat(ctx), ErrLockLost))
+		assert.NoError(t, other.Release(ctx))
+	})
+
+	t.Run("Failure: context done", func(t *testing.T) {
+		lock, err := AcquireLock(ctx, db, table, "busy", time.Minute)
+		assert.NoError(t, err)
+		defer lock.Release(ctx)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = AcquireLock(waitCtx, db, table, "busy", time.Minute)
+		assert.True(t, errors.Is(err, ErrLockHeld))
+	})
+}
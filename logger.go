@@ -0,0 +1,84 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Logger receives a debug record of every API call as alternating key value pairs.
+// *slog.Logger satisfies it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+func (con *dynamodb) logOperation(op OperationInfo, d time.Duration, err error, req *DebugRequest) {
+	logger := con.config.Logger
+	if logger == nil {
+		return
+	}
+
+	args := []interface{}{"operation", op.Name, "table", op.Table}
+	if key := keySummary(op.Input); key != "" {
+		args = append(args, "key", key)
+	}
+	args = append(args, req.debugArgs()...)
+	args = append(args, "duration", d)
+	if err != nil {
+		args = append(args, "error", err)
+	}
+	logger.Debug("dynamodb request", args...)
+}
+
+// keySummary describes the items an SDK input addresses, by key only.
+func keySummary(input interface{}) string {
+	switch in := input.(type) {
+	case *awsDynamodb.GetItemInput:
+		return formatKey(in.Key)
+	case *awsDynamodb.DeleteItemInput:
+		return formatKey(in.Key)
+	case *awsDynamodb.UpdateItemInput:
+		return formatKey(in.Key)
+	case *awsDynamodb.QueryInput:
+		return aws.StringValue(in.KeyConditionExpression)
+	case *awsDynamodb.BatchGetItemInput:
+		n := 0
+		for _, keys := range in.RequestItems {
+			n += len(keys.Keys)
+		}
+		return fmt.Sprintf("%d keys", n)
+	case *awsDynamodb.BatchWriteItemInput:
+		n := 0
+		for _, writes := range in.RequestItems {
+			n += len(writes)
+		}
+		return fmt.Sprintf("%d items", n)
+	}
+	return ""
+}
+
+func formatKey(key map[string]*awsDynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		av := key[name]
+		switch {
+		case av.S != nil:
+			parts[i] = name + "=" + *av.S
+		case av.N != nil:
+			parts[i] = name + "=" + *av.N
+		default:
+			parts[i] = fmt.Sprintf("%s=(%d bytes)", name, len(av.B))
+		}
+	}
+	return strings.Join(parts, ",")
+}
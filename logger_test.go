@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedLogger struct {
+	mu      sync.Mutex
+	records []map[string]interface{}
+}
+
+func (l *recordedLogger) Debug(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record := map[string]interface{}{"msg": msg}
+	for i := 0; i+1 < len(args); i += 2 {
+		record[args[i].(string)] = args[i+1]
+	}
+	l.records = append(l.records, record)
+}
+
+func TestLogger(t *testing.T) {
+	logger := &recordedLogger{}
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{Logger: logger})
+
+	id := faker.UUIDDigit()
+	_, err := dynamo.Delete(tableNameHashOnly, DynamodbKey{
+		Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), id },
+	})
+	assert.NoError(t, err)
+
+	_, err = dynamo.DescribeTable("not-exists")
+	assert.Error(t, err)
+
+	if assert.Len(t, logger.records, 2) {
+		assert.Equal(t, "DeleteItem", logger.records[0]["operation"])
+		assert.Equal(t, tableNameHashOnly, logger.records[0]["table"])
+		assert.Equal(t, "ID="+id, logger.records[0]["key"])
+		assert.Contains(t, logger.records[0], "duration")
+		assert.NotContains(t, logger.records[0], "error")
+		assert.Contains(t, logger.records[1], "error")
+	}
+}
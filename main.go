@@ -1,13 +1,24 @@
 package dynamodb
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
 	"github.com/guregu/dynamo"
 )
 
@@ -15,10 +26,135 @@ import (
 type DynamodbConfig struct {
 	Endpoint string
 	Region   string
+	// HTTPClient replaces the client of the session. Unset, and unless the session sets one, a client
+	// keeping up to MaxIdleConnsPerHost idle connections, DefaultMaxIdleConnsPerHost by default, is used.
+	HTTPClient          *http.Client
+	MaxIdleConnsPerHost int
+	// RequestTimeout bounds each HTTP attempt, including reading the response. Zero is unbounded.
+	RequestTimeout time.Duration
+	// AccessKeyID, SecretAccessKey and SessionToken are static credentials, replacing those of the session.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// CredentialsProvider replaces the credentials of the session unless static credentials are set.
+	CredentialsProvider credentials.Provider
+	// AssumeRoleARN is assumed through STS with the credentials above, or those of the session,
+	// such as for cross-account access. ExternalID is required by some trust policies.
+	AssumeRoleARN   string
+	ExternalID      string
+	RoleSessionName string
+	// MaxRetries of the SDK retryer when RetryPolicy is unset. Zero keeps the SDK default, retried
+	// again by dynamo until its RetryTimeout. Otherwise it bounds every retry and a negative value
+	// disables them, like MaxAttempts of a RetryPolicy.
+	MaxRetries int
+	// CursorSecret encrypts cursors with AES-GCM. It must be 16, 24 or 32 bytes.
+	// When empty, cursors are only base64 encoded.
+	CursorSecret []byte
+	// ReturnConsumedCapacity reports consumed capacity in responses.
+	ReturnConsumedCapacity bool
+	// OptimisticLock makes Put increment the version of Versioned items
+	// and fail with ErrVersionConflict when the stored version differs.
+	OptimisticLock bool
+	Guardrails     Guardrails
+	// RetryPolicy replaces the retry behavior of the AWS SDK when set.
+	RetryPolicy      *RetryPolicy
+	IndexConsistency IndexConsistency
+	// Clock defaults to the system clock.
+	Clock   Clock
+	Metrics Metrics
+	// Logger logs every API call at debug level.
+	Logger Logger
+	Audit  AuditMode
+	// Debug records the expressions and values of every API call for LastRequest, and adds them to
+	// the records of Logger. Values may hold personal data, so enable it while troubleshooting only.
+	Debug bool
+	// Codec serializes fields tagged codec. Defaults to JSONCodec.
+	Codec Codec
+	// Faults injects failures for resilience tests. Leave it nil in production.
+	Faults *Faults
+	// ReadClient serves GetItem, Query, Scan, BatchGetItem and TransactGetItems when set,
+	// such as a DAX client from github.com/aws/aws-dax-go. Writes and table admin go to DynamoDB.
+	ReadClient dynamodbiface.DynamoDBAPI
+	// TablePrefix is prepended to every table name, such as "dev-" for per-environment tables.
+	// Methods and events take and report the names without it.
+	TablePrefix string
+	// IDGenerator fills an empty string hash key on Put. Unset, the key is required.
+	IDGenerator IDGenerator
+	// DeadLetters records the items BulkPut and Subscribe give up on, instead of failing.
+	DeadLetters DeadLetterSink
+	// CoalesceGets makes concurrent Gets of the same item share one request, such as on a cache stampede.
+	// Gets with range conditions other than equality are not coalesced.
+	CoalesceGets bool
+	// TTLPolicies maps table names to the TTL set by every put of an item without one.
+	TTLPolicies map[string]TTLPolicy
+	// RateLimits maps table names to the capacity this client may consume on them.
+	RateLimits map[string]RateLimit
+	// KeyProvider encrypts fields tagged encrypted, such as a KMSKeyProvider.
+	KeyProvider KeyProvider
+	// LargeItemStore holds the attributes of items over LargeItemThreshold bytes, such as an
+	// S3LargeItemStore. LargeItemThreshold defaults to DefaultLargeItemThreshold.
+	LargeItemStore     LargeItemStore
+	LargeItemThreshold int
 }
 
 // DynamodbResponse :
 type DynamodbResponse struct {
+	// HasOldValue reports whether a previous item was unmarshaled by a WithOldValue call.
+	HasOldValue bool
+	// ConsumedCapacity is set when DynamodbConfig.ReturnConsumedCapacity is enabled.
+	ConsumedCapacity *ConsumedCapacity
+	// GeneratedID is the hash key filled in by DynamodbConfig.IDGenerator, if any.
+	GeneratedID string
+	RequestIDs
+}
+
+// DynamodbReadResponse :
+type DynamodbReadResponse struct {
+	// ConsumedCapacity is set when DynamodbConfig.ReturnConsumedCapacity is enabled.
+	ConsumedCapacity *ConsumedCapacity
+	// Pages is the number of query or scan pages read, each evaluating up to 1MB of items.
+	Pages int
+	// Count is the number of items returned and ScannedCount the number evaluated before filters.
+	Count        int64
+	ScannedCount int64
+	// Duration of the call, including retries.
+	Duration time.Duration
+	// Coalesced reports a Get served by the identical Get of another goroutine. See DynamodbConfig.CoalesceGets.
+	Coalesced bool
+	RequestIDs
+}
+
+func readResponse(ctx context.Context, cc *dynamo.ConsumedCapacity) *DynamodbReadResponse {
+	stats := recordedStats(ctx)
+	return &DynamodbReadResponse{
+		ConsumedCapacity: consumed(cc),
+		Pages:            stats.Pages,
+		Count:            stats.Count,
+		ScannedCount:     stats.ScannedCount,
+		Duration:         stats.Duration,
+		RequestIDs:       requestIDs(ctx),
+	}
+}
+
+// QueryStats describes the data access of a read, for per-request metrics.
+type QueryStats struct {
+	Count        int64
+	ScannedCount int64
+	Pages        int
+	// ConsumedCapacity is set when DynamodbConfig.ReturnConsumedCapacity is enabled.
+	ConsumedCapacity *ConsumedCapacity
+	Duration         time.Duration
+}
+
+// Stats returns the stats of the read.
+func (res *DynamodbReadResponse) Stats() QueryStats {
+	return QueryStats{
+		Count:            res.Count,
+		ScannedCount:     res.ScannedCount,
+		Pages:            res.Pages,
+		ConsumedCapacity: res.ConsumedCapacity,
+		Duration:         res.Duration,
+	}
 }
 
 // DynamodbPaged :
@@ -31,6 +167,8 @@ type DynamodbPaged struct {
 type ScanFilter struct {
 	Expr  string
 	Value interface{}
+	// Values replaces Value for expressions with several placeholders. See Filter.
+	Values []interface{}
 }
 
 // DynamodbOperator is an operation to apply in key comparisons.
@@ -51,13 +189,25 @@ const (
 	DynamodbGreaterOrEqual
 	DynamodbBeginsWith
 	DynamodbBetween
+
+	// Operators of FilterAttr and FilterSize only, which queries reject for the range key.
+	DynamodbContains
+	DynamodbNotContains
+	// DynamodbAttributeType takes a DynamodbType.
+	DynamodbAttributeType
+	// DynamodbNotNull matches an existing attribute and DynamodbNull a missing one. They take no value.
+	DynamodbNotNull
+	DynamodbNull
+	// DynamodbIn takes up to 100 values.
+	DynamodbIn
 )
 
 func (o *DynamodbOperator) value() dynamo.Operator {
 	if o == nil {
 		return dynamo.Equal
 	}
-	return [...]dynamo.Operator{dynamo.Equal, dynamo.NotEqual, dynamo.Less, dynamo.LessOrEqual, dynamo.Greater, dynamo.GreaterOrEqual, dynamo.BeginsWith, dynamo.Between}[*o]
+	return [...]dynamo.Operator{dynamo.Equal, dynamo.NotEqual, dynamo.Less, dynamo.LessOrEqual, dynamo.Greater, dynamo.GreaterOrEqual, dynamo.BeginsWith, dynamo.Between,
+		"CONTAINS", "NOT_CONTAINS", "ATTRIBUTE_TYPE", "NOT_NULL", "NULL", "IN"}[*o]
 }
 
 // DynamodbOrder :
@@ -73,6 +223,12 @@ const (
 type DynamodbOptions struct {
 	Operator *DynamodbOperator
 	Order    *DynamodbOrder
+	// Limit stops a query after this many matching items.
+	Limit int
+	// SearchLimit stops a query after evaluating this many items, before filters apply.
+	SearchLimit int
+	// UpperBound is the inclusive upper bound of DynamodbBetween, whose lower bound is the range value.
+	UpperBound interface{}
 }
 
 func (o *DynamodbOrder) value() dynamo.Order {
@@ -98,35 +254,152 @@ type DynamodbKey struct {
 // Dynamodb :
 type Dynamodb interface {
 	Get(tableName string, key DynamodbKey, result interface{}) error
+	GetWithResponse(tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error)
 	GetAll(tableName string, key DynamodbKey, result interface{}) error
+	GetAllWithResponse(tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error)
+	GetAllGrouped(tableName string, keys []DynamodbKey, result interface{}) error
+	QueryPrefix(tableName, hashValue, sortKeyPrefix string, result interface{}) error
+	Exists(tableName string, key DynamodbKey) (bool, error)
+	GetOrDefault(tableName string, key DynamodbKey, defaultItem, result interface{}) error
+	GetOrCreate(tableName string, key DynamodbKey, defaultItem, result interface{}) (bool, error)
 	BatchGet(tableName string, keys []*DynamodbKey, result interface{}) error
+	BatchGetWithResponse(tableName string, keys []*DynamodbKey, result interface{}) (*DynamodbReadResponse, error)
+	BatchGetEntities(tableName string, keys []*DynamodbKey, typeAttribute string, entities map[string]interface{}) (EntityItems, error)
+	MultiTableBatchGet(ops map[string]BatchGetSpec) (*DynamodbReadResponse, error)
 	Count(tableName string, key DynamodbKey) (int64, error)
+	CountWithResponse(tableName string, key DynamodbKey) (int64, *DynamodbReadResponse, error)
+	CountWithOptions(tableName string, key DynamodbKey, opts CountOptions) (CountResult, *DynamodbReadResponse, error)
 	Paging(tableName string, key DynamodbKey, paged DynamodbPaged, result interface{}) error
+	PagingWithResponse(tableName string, key DynamodbKey, paged DynamodbPaged, result interface{}) (*DynamodbReadResponse, error)
+	EncodeCursor(key DynamodbKey, pageKeys []*DynamodbAttributeValue) (string, error)
+	DecodeCursor(key DynamodbKey, cursor string) ([]*DynamodbAttributeValue, error)
 	Put(tableName string, item interface{}) (*DynamodbResponse, error)
+	PutIdempotent(tableName string, item interface{}, token string, ttl time.Duration) (*DynamodbResponse, error)
+	PutWithOldValue(tableName string, item interface{}, old interface{}) (*DynamodbResponse, error)
+	PutIfNotExists(tableName string, item interface{}) (*DynamodbResponse, error)
+	UpdateOnly(tableName string, item interface{}) (*DynamodbResponse, error)
+	PutWithMode(tableName string, item interface{}, mode WriteMode) (*DynamodbResponse, error)
+	PutIf(tableName string, item interface{}, conditions ...ScanFilter) (*DynamodbResponse, error)
+	BulkPut(ctx context.Context, tableName string, items interface{}, opts BulkPutOptions) (int, error)
 	Delete(tableName string, key DynamodbKey) (*DynamodbResponse, error)
+	DeleteWithOldValue(tableName string, key DynamodbKey, old interface{}) (*DynamodbResponse, error)
+	DeleteIf(tableName string, key DynamodbKey, conditions ...ScanFilter) (*DynamodbResponse, error)
+	DeleteAllByHash(ctx context.Context, tableName string, hashKey string, hashValue interface{}) (int, error)
+	SetSparse(tableName string, key DynamodbKey, attribute string, value interface{}) error
+	ClearSparse(tableName string, key DynamodbKey, attribute string) error
+	ScanSparseIndex(tableName, indexName string, result interface{}) error
 	Scan(tableName string, result interface{}, filters ...ScanFilter) error
-
+	ScanWithResponse(tableName string, result interface{}, filters ...ScanFilter) (*DynamodbReadResponse, error)
+	QueryIter(tableName string, key DynamodbKey) DynamodbIter
+	Query(tableName string) *DynamodbQuery
+	BestEffortGroup() *BestEffortGroup
+	TransactWrite(ctx context.Context, ops []TransactOp) error
+	TransactWriteSplit(ctx context.Context, ops []TransactOp, boundary func(op TransactOp) string) (committed int, err error)
+	GetAllStream(tableName string, key DynamodbKey, pageSize int) DynamodbIter
+	GetAllEach(ctx context.Context, tableName string, key DynamodbKey, out interface{}, fn func() error) error
+	ScanEach(ctx context.Context, tableName string, out interface{}, fn func() error, filters ...ScanFilter) error
+	ScanIter(tableName string, filters ...ScanFilter) DynamodbIter
+	ScanPage(ctx context.Context, tableName string, result interface{}, opts ScanPageOptions, filters ...ScanFilter) (string, error)
+	ScanParallel(tableName string, segments int, result interface{}, filters ...ScanFilter) error
+	ScanParallelWithOptions(tableName string, result interface{}, opts ParallelScanOptions, filters ...ScanFilter) error
+	ScanAnalyze(tableName string, top int, filters ...ScanFilter) (*ScanReport, error)
+	ExportTable(ctx context.Context, tableName string, w io.Writer, format ExportFormat) (int, error)
+	ExportToS3(ctx context.Context, tableName string, opts ExportToS3Options) (string, error)
+	ImportTable(ctx context.Context, tableName string, r io.Reader, opts ImportOptions) (int, error)
+	CopyTable(ctx context.Context, src, dst string, opts CopyOptions) (CopyResult, error)
+
+	TableExists(ctx context.Context, name string) (bool, error)
+	ListTables(ctx context.Context) ([]string, error)
+	Ping(ctx context.Context) error
+	ListTablesWithPrefix(ctx context.Context, prefix string) ([]string, error)
+	CreateTableWithContext(ctx context.Context, name string, entity interface{}, options CreateTableOptions) error
+	DeleteTableWithContext(ctx context.Context, name string) error
+	DeleteTableIfExists(ctx context.Context, name string, options DeleteTableOptions) error
 	ExistsTable(name string) bool
 	CreateTable(name string, entity interface{}) error
 	CreateTableWithLocalSecondaryIndex(name string, entity interface{}, indexName string) error
+	CreateTableWithOptions(name string, entity interface{}, options CreateTableOptions) error
 	DeleteTable(name string) error
+	DescribeTable(name string) (*TableDescription, error)
+	ApplyTableSpec(ctx context.Context, spec TableSpec) error
+	CreateTableFromSchema(ctx context.Context, name string, spec TableSpec) error
+	DescribeTableSpec(ctx context.Context, name string) (TableSpec, error)
+	ValidateSchema(ctx context.Context, tableName string, entity interface{}) error
+	ValidateTableSpec(ctx context.Context, spec TableSpec) error
+	UpdateTableAddGSI(name string, index IndexDefinition) error
+	UpdateTableDeleteGSI(name, indexName string) error
+	UpdateTableThroughput(name string, read, write int64) error
+	SwitchToOnDemand(name string) error
+	SwitchToProvisioned(name string, read, write int64) error
+	WaitUntilIndexActive(ctx context.Context, name, indexName string) error
+	WaitUntilIndexDeleted(ctx context.Context, name, indexName string) error
+	EnableTTL(tableName, attributeName string) error
+	DescribeTTL(tableName string) (*TTLDescription, error)
+	ExpiresIn(d time.Duration) int64
+	Clock() Clock
+	WaitUntilTableActive(ctx context.Context, name string) error
+	WaitUntilTableDeleted(ctx context.Context, name string) error
+	WithTableAsOf(ctx context.Context, tableName string, at time.Time, fn func(snapshot string) error) error
+	ReadAsOf(ctx context.Context, tableName string, at time.Time, key DynamodbKey, result interface{}) error
+	RenameAttribute(ctx context.Context, tableName, oldName, newName string, opts RenameOptions) (RenameResult, error)
+	Subscribe(tableName string, handler ChangeHandler) (*Subscription, error)
+	SubscribeWithOptions(tableName string, handler ChangeHandler, opts SubscribeOptions) (*Subscription, error)
+	Use(middleware ...Middleware)
+	LastRequest() *DebugRequest
+	OnTableEvent(handler TableEventHandler)
 }
 
 type dynamodb struct {
-	db *dynamo.DB
+	db      *dynamo.DB
+	streams dynamodbstreamsiface.DynamoDBStreamsAPI
+	config  *DynamodbConfig
+
+	middlewares middlewares
+	tableEvents tableEvents
+
+	tablesCreated int32
+	descriptions  sync.Map
+	inflight      sync.Map
+	flights       sync.Map
+	faults        faultCounters
+	limiters      map[string]*tableLimiter
+	lastRequest   lastRequest
 }
 
+// New builds the client on aws-sdk-go v1 and guregu/dynamo v1. There is no aws-sdk-go-v2 backend:
+// guregu/dynamo/v2 requires a newer Go release than the go 1.16 this module declares.
 func New(sess *session.Session, config *DynamodbConfig) (Dynamodb, error) {
 	return BuildDynamodb(sess, config)
 }
 
+// table returns the table of tableName, applying the configured prefix.
+func (con *dynamodb) table(tableName string) dynamo.Table {
+	return con.db.Table(con.tableName(tableName))
+}
+
+func (con *dynamodb) tableName(tableName string) string {
+	return con.config.TablePrefix + tableName
+}
+
 // BuildDynamodb :
 func BuildDynamodb(sess *session.Session, config *DynamodbConfig) (Dynamodb, error) {
+	sess, err := withCredentials(sess, config)
+	if err != nil {
+		return nil, err
+	}
 	client, err := connectDynamodb(sess, config)
 	if err != nil {
 		return nil, err
 	}
-	return &dynamodb{client}, nil
+
+	client.Handlers.Complete.PushBack(recordRequestID)
+	if config.Metrics != nil {
+		client.Handlers.Retry.PushFront(countThrottles(config.Metrics))
+	}
+
+	con := &dynamodb{config: config, streams: dynamodbstreams.New(sess, awsConfig(sess, config)), limiters: newLimiters(config)}
+	con.db = dynamo.NewFromIface(&middlewareClient{DynamoDBAPI: client, con: con, reads: config.ReadClient})
+	return con, nil
 }
 
 func query(table *dynamo.Table, key DynamodbKey) *dynamo.Query {
@@ -135,6 +408,7 @@ func query(table *dynamo.Table, key DynamodbKey) *dynamo.Query {
 
 	if key.Range != nil {
 		rKey, rValue, option := key.Range()
+		limit(req, option)
 
 		op := DynamodbEqual
 		if option != nil {
@@ -143,15 +417,16 @@ func query(table *dynamo.Table, key DynamodbKey) *dynamo.Query {
 			}
 
 			if order := option.Order; order != nil {
-				return req.Range(rKey, op.value(), rValue).Order(order.value())
+				return req.Range(rKey, op.value(), rangeValues(op, rValue, option)...).Order(order.value())
 			}
 		}
 
-		return req.Range(rKey, op.value(), rValue)
+		return req.Range(rKey, op.value(), rangeValues(op, rValue, option)...)
 	}
 
 	if key.LocalSecondaryIndex != nil {
 		lName, lKey, lValue, option := key.LocalSecondaryIndex()
+		limit(req, option)
 
 		op := DynamodbEqual
 		if option != nil {
@@ -160,118 +435,448 @@ func query(table *dynamo.Table, key DynamodbKey) *dynamo.Query {
 			}
 
 			if order := option.Order; order != nil {
-				return req.Index(string(lName)).Range(lKey, op.value(), lValue).Order(order.value())
+				return req.Index(string(lName)).Range(lKey, op.value(), rangeValues(op, lValue, option)...).Order(order.value())
 			}
 		}
 
-		return req.Index(string(lName)).Range(lKey, op.value(), lValue)
+		return req.Index(string(lName)).Range(lKey, op.value(), rangeValues(op, lValue, option)...)
 	}
 	return req
 }
 
-func (con *dynamodb) Get(tableName string, key DynamodbKey, result interface{}) error {
-	table := con.db.Table(tableName)
-	return query(&table, key).One(result)
+// rangeValues adds the upper bound of option to the range value of a DynamodbBetween.
+func rangeValues(op DynamodbOperator, value interface{}, option *DynamodbOptions) []interface{} {
+	if op == DynamodbBetween && option != nil {
+		return []interface{}{value, option.UpperBound}
+	}
+	return []interface{}{value}
 }
 
-func (con *dynamodb) GetAll(tableName string, key DynamodbKey, result interface{}) error {
-	table := con.db.Table(tableName)
-	return query(&table, key).All(result)
+func limit(req *dynamo.Query, option *DynamodbOptions) {
+	if option == nil {
+		return
+	}
+	if option.Limit > 0 {
+		req.Limit(int64(option.Limit))
+	}
+	if option.SearchLimit > 0 {
+		req.SearchLimit(int64(option.SearchLimit))
+	}
 }
 
-func (con *dynamodb) BatchGet(tableName string, keys []*DynamodbKey, result interface{}) error {
-	if len(keys) < 1 {
-		return errors.New("key empty")
-	}
+func (con *dynamodb) Get(tableName string, key DynamodbKey, result interface{}) error {
+	_, err := con.GetWithResponse(tableName, key, result)
+	return err
+}
 
-	m := make(map[interface{}]bool)
-	uniqKeys := []*DynamodbKey{}
+func (con *dynamodb) GetWithResponse(tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error) {
+	defer con.auditResult("Get", result)()
 
-	for _, key := range keys {
-		_, hValue := key.Hash()
+	if con.config.CoalesceGets {
+		if cacheKey, ok := getCacheKey(tableName, key); ok {
+			return con.coalescedGet(cacheKey, tableName, key, result)
+		}
+	}
 
-		if !m[hValue] {
-			m[hValue] = true
-			uniqKeys = append(uniqKeys, key)
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	table := con.table(tableName)
+	var err error
+	if hasCodedFields(result) {
+		var av map[string]*awsDynamodb.AttributeValue
+		if err = query(&table, key).ConsumedCapacity(cc).OneWithContext(ctx, &av); err == nil {
+			err = con.decodeItem(tableName, av, result)
 		}
+	} else {
+		err = query(&table, key).ConsumedCapacity(cc).OneWithContext(ctx, result)
 	}
+	return readResponse(ctx, cc), err
+}
 
-	itemKeyNames := make([]string, 2)
-	itemKeys := make([]dynamo.Keyed, len(uniqKeys))
+func (con *dynamodb) GetAll(tableName string, key DynamodbKey, result interface{}) error {
+	_, err := con.GetAllWithResponse(tableName, key, result)
+	return err
+}
 
-	for i, k := range uniqKeys {
-		hKey, hValue := k.Hash()
+func (con *dynamodb) GetAllWithResponse(tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error) {
+	defer con.auditResult("GetAll", result)()
 
-		if i == 0 {
-			itemKeyNames[0] = hKey
-		}
+	ctx, cancel := callContext()
+	defer cancel()
 
-		if f := k.Range; f != nil {
-			rKey, rValue, _ := f()
-			if i == 0 {
-				itemKeyNames[1] = rKey
-			}
-			itemKeys[i] = dynamo.Keys{hValue, rValue}
-		} else {
-			itemKeys[i] = dynamo.Keys{hValue}
+	cc := con.capacity()
+	table := con.table(tableName)
+	var err error
+	if hasCodedFields(result) {
+		var items []map[string]*awsDynamodb.AttributeValue
+		if err = query(&table, key).ConsumedCapacity(cc).AllWithContext(ctx, &items); err == nil {
+			err = con.decodeItems(tableName, items, result)
 		}
+	} else {
+		err = query(&table, key).ConsumedCapacity(cc).AllWithContext(ctx, result)
+	}
+	return readResponse(ctx, cc), err
+}
+
+// Exists reports whether the item of key exists, reading only its key attributes.
+func (con *dynamodb) Exists(tableName string, key DynamodbKey) (bool, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+
+	table := con.table(tableName)
+	req := query(&table, key)
+	hKey, _ := key.Hash()
+	projection := []string{hKey}
+	if key.Range != nil {
+		rKey, _, _ := key.Range()
+		projection = append(projection, rKey)
+	}
+
+	var av map[string]*awsDynamodb.AttributeValue
+	err := req.Project(projection...).OneWithContext(ctx, &av)
+	if errors.Is(err, dynamo.ErrNotFound) {
+		return false, nil
 	}
+	return err == nil, err
+}
 
-	table := con.db.Table(tableName)
-	if err := table.Batch(itemKeyNames...).Get(itemKeys...).All(result); err != nil {
+// QueryPrefix reads the items of hashValue whose sort key begins with sortKeyPrefix, such as
+// every "ORDER#" of a user. An empty prefix reads all the items of hashValue.
+// The key names are read from the description of the table.
+func (con *dynamodb) QueryPrefix(tableName, hashValue, sortKeyPrefix string, result interface{}) error {
+	desc, err := con.describeCached(tableName)
+	if err != nil {
 		return err
 	}
+	if len(desc.RangeKey) < 1 {
+		return fmt.Errorf("table %s has no sort key", tableName)
+	}
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return desc.HashKey, hashValue },
+	}
+	// DynamoDB rejects an empty key condition value, and every sort key begins with ""
+	if len(sortKeyPrefix) > 0 {
+		op := DynamodbBeginsWith
+		key.Range = func() (string, interface{}, *DynamodbOptions) {
+			return desc.RangeKey, sortKeyPrefix, &DynamodbOptions{Operator: &op}
+		}
+	}
+	return con.GetAll(tableName, key, result)
+}
+
+func (con *dynamodb) BatchGet(tableName string, keys []*DynamodbKey, result interface{}) error {
+	_, err := con.BatchGetWithResponse(tableName, keys, result)
+	return err
+}
+
+func (con *dynamodb) BatchGetWithResponse(tableName string, keys []*DynamodbKey, result interface{}) (*DynamodbReadResponse, error) {
+	if len(keys) < 1 {
+		return &DynamodbReadResponse{}, errors.New("key empty")
+	}
+	if err := con.guardBulk(len(keys)); err != nil {
+		return &DynamodbReadResponse{}, err
+	}
+
+	defer con.auditResult("BatchGet", result)()
+
+	uniqKeys, err := uniqueKeys(tableName, keys)
+	if err != nil {
+		return &DynamodbReadResponse{}, err
+	}
+	con.auditKeys("BatchGet", len(keys), len(uniqKeys))
+
+	ctx, cancel := callContext()
+	defer cancel()
 
-	return nil
+	cc := con.capacity()
+	items, unprocessed, err := con.batchGet(ctx, map[string][]*DynamodbKey{tableName: uniqKeys}, cc)
+	if err == nil && len(items[tableName]) < 1 && len(unprocessed) < 1 {
+		err = dynamo.ErrNotFound
+	}
+	if err == nil {
+		err = con.decodeBatch(tableName, items[tableName], result)
+	}
+	if err == nil {
+		err = unprocessedError(unprocessed)
+	}
+	return readResponse(ctx, cc), err
 }
 
 func (con *dynamodb) Count(tableName string, key DynamodbKey) (int64, error) {
-	table := con.db.Table(tableName)
-	return query(&table, key).Count()
+	count, _, err := con.CountWithResponse(tableName, key)
+	return count, err
+}
+
+func (con *dynamodb) CountWithResponse(tableName string, key DynamodbKey) (int64, *DynamodbReadResponse, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	table := con.table(tableName)
+	count, err := query(&table, key).ConsumedCapacity(cc).CountWithContext(ctx)
+	return count, readResponse(ctx, cc), err
+}
+
+// CountOptions :
+type CountOptions struct {
+	// Index counts a global or local secondary index, whose hash key is the hash of the key.
+	Index   string
+	Filters []ScanFilter
+}
+
+// CountResult :
+type CountResult struct {
+	// Count is the number of items matching the filters.
+	Count int64
+	// ScannedCount is the number of items matching the key, before the filters.
+	ScannedCount int64
+}
+
+// CountWithOptions is CountWithResponse on an index or with filters. Both counts are reported,
+// so their ratio tells how much read capacity the filters discard.
+func (con *dynamodb) CountWithOptions(tableName string, key DynamodbKey, opts CountOptions) (CountResult, *DynamodbReadResponse, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	table := con.table(tableName)
+	req := query(&table, key)
+	if len(opts.Index) > 0 {
+		req.Index(opts.Index)
+	}
+	for _, f := range opts.Filters {
+		req.Filter(f.Expr, f.args()...)
+	}
+
+	count, err := req.ConsumedCapacity(cc).CountWithContext(ctx)
+	res := readResponse(ctx, cc)
+	return CountResult{Count: count, ScannedCount: res.ScannedCount}, res, err
 }
 
 func (con *dynamodb) Paging(tableName string, key DynamodbKey, paged DynamodbPaged, result interface{}) error {
-	pagingKey := map[string]*awsDynamodb.AttributeValue{}
-	for _, attr := range paged.PageKeys {
-		switch value := attr.Value.(type) {
-		case int:
-		case int8:
-		case int16:
-		case int32:
-		case int64:
-			pagingKey[attr.Key] = &awsDynamodb.AttributeValue{
-				N: aws.String(strconv.Itoa(int(value))),
-			}
-		case string:
-			pagingKey[attr.Key] = &awsDynamodb.AttributeValue{
-				S: aws.String(value),
-			}
-		}
+	_, err := con.PagingWithResponse(tableName, key, paged, result)
+	return err
+}
+
+func (con *dynamodb) PagingWithResponse(tableName string, key DynamodbKey, paged DynamodbPaged, result interface{}) (*DynamodbReadResponse, error) {
+	defer con.auditResult("Paging", result)()
+
+	startKey, err := pagingKey(paged.PageKeys)
+	if err != nil {
+		return &DynamodbReadResponse{}, err
 	}
 
-	table := con.db.Table(tableName)
-	return query(&table, key).StartFrom(pagingKey).Limit(int64(paged.Limit)).All(result)
+	ctx, cancel := callContext()
+	defer cancel()
 
+	cc := con.capacity()
+	table := con.table(tableName)
+	err = con.readItems(tableName, result, func(out interface{}) error {
+		return query(&table, key).StartFrom(startKey).Limit(int64(paged.Limit)).ConsumedCapacity(cc).AllWithContext(ctx, out)
+	})
+	if isInvalidStartKey(err) {
+		err = fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return readResponse(ctx, cc), err
 }
 
 func (con *dynamodb) Put(tableName string, item interface{}) (*DynamodbResponse, error) {
-	err := con.db.Table(tableName).Put(item).Run()
-	return &DynamodbResponse{}, err
+	return con.PutWithMode(tableName, item, WriteUpsert)
+}
+
+// Attributes written by PutIdempotent to remember the token of the last write.
+const (
+	IdempotencyTokenAttribute   = "IdempotencyToken"
+	IdempotencyExpiresAttribute = "IdempotencyExpiresAt"
+)
+
+// PutIdempotent writes item unless the stored item was already written with the same token
+// within ttl. A replayed token is treated as success, so at-least-once producers can retry freely.
+func (con *dynamodb) PutIdempotent(tableName string, item interface{}, token string, ttl time.Duration) (*DynamodbResponse, error) {
+	if len(token) < 1 {
+		return &DynamodbResponse{}, errors.New("token empty")
+	}
+
+	put, err := con.encodePut(tableName, item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	encoded, ok := put.(map[string]*awsDynamodb.AttributeValue)
+	if !ok {
+		if encoded, err = dynamo.MarshalItem(put); err != nil {
+			return &DynamodbResponse{}, err
+		}
+	}
+	// copy so that a map passed by the caller is left alone
+	av := make(map[string]*awsDynamodb.AttributeValue, len(encoded)+2)
+	for k, v := range encoded {
+		av[k] = v
+	}
+
+	now := con.clock().Now()
+	av[IdempotencyTokenAttribute] = &awsDynamodb.AttributeValue{
+		S: aws.String(token),
+	}
+	av[IdempotencyExpiresAttribute] = &awsDynamodb.AttributeValue{
+		N: aws.String(strconv.FormatInt(now.Add(ttl).Unix(), 10)),
+	}
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	err = con.table(tableName).Put(av).
+		ConsumedCapacity(cc).
+		If("attribute_not_exists($) OR $ <> ? OR $ < ?",
+			IdempotencyTokenAttribute,
+			IdempotencyTokenAttribute, token,
+			IdempotencyExpiresAttribute, now.Unix()).
+		RunWithContext(ctx)
+	if isConditionalCheckFailed(err) {
+		err = nil
+	}
+
+	return &DynamodbResponse{ConsumedCapacity: consumed(cc), RequestIDs: requestIDs(ctx)}, err
+}
+
+func isConditionalCheckFailed(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == awsDynamodb.ErrCodeConditionalCheckFailedException
+}
+
+// PutWithOldValue is Put that unmarshals the replaced item into old.
+func (con *dynamodb) PutWithOldValue(tableName string, item interface{}, old interface{}) (*DynamodbResponse, error) {
+	defer con.auditResult("PutWithOldValue", old)()
+
+	lock, err := con.lockVersion(item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	put, err := con.encodePut(tableName, item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	err = con.readItem(tableName, old, func(out interface{}) error {
+		return lock.apply(con.table(tableName).Put(put)).ConsumedCapacity(cc).OldValueWithContext(ctx, out)
+	})
+	if !errors.Is(err, dynamo.ErrNotFound) {
+		err = lock.result(err)
+	}
+	res, err := oldValueResponse(ctx, cc, err)
+	if err != nil {
+		return res, err
+	}
+	return res, con.waitIndexes(tableName, item)
 }
 
 func (con *dynamodb) Delete(tableName string, key DynamodbKey) (*DynamodbResponse, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	err := deleteItem(con.table(tableName), key).ConsumedCapacity(cc).RunWithContext(ctx)
+	return &DynamodbResponse{ConsumedCapacity: consumed(cc), RequestIDs: requestIDs(ctx)}, err
+}
+
+// PutIf is Put when all the conditions hold on the stored item, such as
+// ScanFilter{Expr: "Version = ?", Value: 3}, and fails with ErrConditionFailed otherwise.
+// A missing item fails any condition on its attributes except attribute_not_exists.
+func (con *dynamodb) PutIf(tableName string, item interface{}, conditions ...ScanFilter) (*DynamodbResponse, error) {
+	put, err := con.encodePut(tableName, item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	req := con.table(tableName).Put(put)
+	for _, c := range conditions {
+		req.If(c.Expr, c.args()...)
+	}
+	err = req.ConsumedCapacity(cc).RunWithContext(ctx)
+	if isConditionalCheckFailed(err) {
+		err = fmt.Errorf("%w: %v", ErrConditionFailed, err)
+	}
+	return &DynamodbResponse{ConsumedCapacity: consumed(cc), RequestIDs: requestIDs(ctx)}, err
+}
+
+// DeleteIf is Delete when all the conditions hold on the stored item, such as
+// ScanFilter{Expr: "Status = ?", Value: "archived"}, and fails with ErrConditionFailed otherwise.
+// A missing item fails any condition on its attributes.
+func (con *dynamodb) DeleteIf(tableName string, key DynamodbKey, conditions ...ScanFilter) (*DynamodbResponse, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	req := deleteItem(con.table(tableName), key)
+	for _, c := range conditions {
+		req.If(c.Expr, c.args()...)
+	}
+	err := req.ConsumedCapacity(cc).RunWithContext(ctx)
+	if isConditionalCheckFailed(err) {
+		err = fmt.Errorf("%w: %v", ErrConditionFailed, err)
+	}
+	return &DynamodbResponse{ConsumedCapacity: consumed(cc), RequestIDs: requestIDs(ctx)}, err
+}
+
+// DeleteWithOldValue is Delete that unmarshals the deleted item into old.
+func (con *dynamodb) DeleteWithOldValue(tableName string, key DynamodbKey, old interface{}) (*DynamodbResponse, error) {
+	defer con.auditResult("DeleteWithOldValue", old)()
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	err := con.readItem(tableName, old, func(out interface{}) error {
+		return deleteItem(con.table(tableName), key).ConsumedCapacity(cc).OldValueWithContext(ctx, out)
+	})
+	return oldValueResponse(ctx, cc, err)
+}
+
+func deleteItem(table dynamo.Table, key DynamodbKey) *dynamo.Delete {
 	hKey, hValue := key.Hash()
-	req := con.db.Table(tableName).Delete(hKey, hValue)
+	req := table.Delete(hKey, hValue)
 
-	var err error
 	if key.Range != nil {
 		rKey, rValue, _ := key.Range()
-		err = req.Range(rKey, rValue).Run()
-	} else {
-		err = req.Run()
+		req.Range(rKey, rValue)
 	}
 
-	return &DynamodbResponse{}, err
+	return req
+}
+
+func updateItem(table dynamo.Table, key DynamodbKey) *dynamo.Update {
+	hKey, hValue := key.Hash()
+	req := table.Update(hKey, hValue)
+
+	if key.Range != nil {
+		rKey, rValue, _ := key.Range()
+		req.Range(rKey, rValue)
+	}
+
+	return req
+}
+
+func oldValueResponse(ctx context.Context, cc *dynamo.ConsumedCapacity, err error) (*DynamodbResponse, error) {
+	res := &DynamodbResponse{ConsumedCapacity: consumed(cc), RequestIDs: requestIDs(ctx)}
+	if errors.Is(err, dynamo.ErrNotFound) {
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+	res.HasOldValue = true
+	return res, nil
 }
 
 // ScanFilter is only for script. Do not use from application.
@@ -283,49 +888,89 @@ func (con *dynamodb) Delete(tableName string, key DynamodbKey) (*DynamodbRespons
 // contains (path, operand)
 // size (path)
 func (con *dynamodb) Scan(tableName string, result interface{}, filters ...ScanFilter) error {
-	if len(filters) > 0 {
-		tmp := con.db.Table(tableName).Scan()
-		for _, f := range filters {
-			tmp.Filter(f.Expr, f.Value)
+	_, err := con.ScanWithResponse(tableName, result, filters...)
+	return err
+}
+
+func (con *dynamodb) ScanWithResponse(tableName string, result interface{}, filters ...ScanFilter) (*DynamodbReadResponse, error) {
+	defer con.auditResult("Scan", result)()
+
+	ctx, cancel := con.scanContext(recordContext(context.Background()))
+	defer cancel()
+
+	cc := con.capacity()
+	var err error
+	if hasCodedFields(result) {
+		var items []map[string]*awsDynamodb.AttributeValue
+		if err = scan(con.table(tableName), filters...).ConsumedCapacity(cc).AllWithContext(ctx, &items); err == nil {
+			err = con.decodeItems(tableName, items, result)
 		}
-		return tmp.All(result)
+	} else {
+		err = scan(con.table(tableName), filters...).ConsumedCapacity(cc).AllWithContext(ctx, result)
+	}
+	return readResponse(ctx, cc), scanError(ctx, err)
+}
+
+func scan(table dynamo.Table, filters ...ScanFilter) *dynamo.Scan {
+	req := table.Scan()
+	for _, f := range filters {
+		req.Filter(f.Expr, f.args()...)
 	}
+	return req
+}
 
-	return con.db.Table(tableName).Scan().All(result)
+func connectDynamodb(sess *session.Session, dbConfig *DynamodbConfig) (*awsDynamodb.DynamoDB, error) {
+	return awsDynamodb.New(sess, awsConfig(sess, dbConfig)), nil
 }
 
-func connectDynamodb(sess *session.Session, dbConfig *DynamodbConfig) (*dynamo.DB, error) {
+// awsConfig is shared by the DynamoDB and DynamoDB Streams clients.
+func awsConfig(sess *session.Session, dbConfig *DynamodbConfig) *aws.Config {
 	config := aws.NewConfig().WithRegion(dbConfig.Region)
 
 	if len(dbConfig.Endpoint) > 0 {
 		config = config.WithEndpoint(dbConfig.Endpoint)
 	}
 
-	db := dynamo.New(sess, config)
-	return db, nil
-}
-
-func (con *dynamodb) ExistsTable(name string) bool {
-	list, _ := con.db.ListTables().All()
+	if client := httpClient(sess, dbConfig); client != nil {
+		config = config.WithHTTPClient(client)
+	}
 
-	for _, tableName := range list {
-		fmt.Println(tableName)
-		if tableName == name {
-			return true
-		}
+	switch {
+	case dbConfig.RetryPolicy != nil:
+		config = request.WithRetryer(config, retryer{policy: *dbConfig.RetryPolicy})
+	case dbConfig.MaxRetries < 0:
+		config = config.WithMaxRetries(0)
+	case dbConfig.MaxRetries > 0:
+		config = config.WithMaxRetries(dbConfig.MaxRetries)
 	}
 
-	return false
+	return config
+}
+
+// ExistsTable reports false when the existence cannot be checked.
+//
+// Deprecated: use TableExists, which returns the error.
+func (con *dynamodb) ExistsTable(name string) bool {
+	exists, _ := con.TableExists(context.Background(), name)
+	return exists
 }
 
+// Deprecated: use CreateTableWithContext.
 func (con *dynamodb) CreateTable(name string, entity interface{}) error {
-	return con.db.CreateTable(name, entity).Run()
+	return con.CreateTableWithContext(context.Background(), name, entity, CreateTableOptions{})
 }
 
+// CreateTableWithLocalSecondaryIndex creates the table with a keys-only projection for indexName,
+// which must be declared by a localIndex struct tag on entity.
 func (con *dynamodb) CreateTableWithLocalSecondaryIndex(name string, entity interface{}, indexName string) error {
-	return con.db.CreateTable(name, entity).Project(indexName, dynamo.KeysOnlyProjection).Run()
+	return con.CreateTableWithOptions(name, entity, CreateTableOptions{
+		LSIs: []IndexDefinition{
+			{Name: indexName, Projection: DynamodbProjectionKeysOnly},
+		},
+	})
 }
 
+// Deprecated: use DeleteTableWithContext.
 func (con *dynamodb) DeleteTable(name string) error {
-	return con.db.Table(name).DeleteTable().Run()
+	return con.DeleteTableWithContext(context.Background(), name)
 }
@@ -1,9 +1,13 @@
 package dynamodb
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -42,11 +46,15 @@ func (HashAndRange) RangeKey() string {
 }
 
 func newDynamo(t *testing.T) Dynamodb {
+	return newDynamoWithConfig(t, &DynamodbConfig{})
+}
+
+func newDynamoWithConfig(t *testing.T, config *DynamodbConfig) Dynamodb {
+	config.Endpoint = "http://localhost:8000"
+	config.Region = "us-east-1"
+
 	sess := session.New()
-	db, err := New(sess, &DynamodbConfig{
-		Endpoint: "http://localhost:8000",
-		Region:   "us-east-1",
-	})
+	db, err := New(sess, config)
 
 	if t == nil {
 		if err != nil {
@@ -71,6 +79,10 @@ func TestMain(m *testing.M) {
 			fmt.Println(err.Error())
 			os.Exit(99)
 		}
+		if err := db.WaitUntilTableActive(context.Background(), tableNameHashOnly); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(99)
+		}
 	}
 
 	if !db.ExistsTable(tableNameHashAndRange) {
@@ -78,6 +90,10 @@ func TestMain(m *testing.M) {
 			fmt.Println(err.Error())
 			os.Exit(99)
 		}
+		if err := db.WaitUntilTableActive(context.Background(), tableNameHashAndRange); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(99)
+		}
 	}
 
 	status := m.Run()
@@ -170,6 +186,102 @@ func TestPut(t *testing.T) {
 	})
 }
 
+func TestPutIdempotent(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	var expect HashOnly
+	faker.FakeData(&expect)
+	token := faker.UUIDDigit()
+
+	_, err := dynamo.PutIdempotent(tableNameHashOnly, &expect, token, time.Hour)
+	assert.NoError(t, err)
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), expect.Id },
+	}
+
+	t.Run("Success: replayed token is ignored", func(t *testing.T) {
+		replay := expect
+		replay.Name = "replayed"
+
+		_, err := dynamo.PutIdempotent(tableNameHashOnly, &replay, token, time.Hour)
+		assert.NoError(t, err)
+
+		var datum HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &datum))
+		assert.Equal(t, expect.Name, datum.Name)
+	})
+
+	t.Run("Success: new token overwrites", func(t *testing.T) {
+		next := expect
+		next.Name = "next"
+
+		_, err := dynamo.PutIdempotent(tableNameHashOnly, &next, faker.UUIDDigit(), time.Hour)
+		assert.NoError(t, err)
+
+		var datum HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &datum))
+		assert.Equal(t, next.Name, datum.Name)
+	})
+
+	t.Run("Failure: token blank", func(t *testing.T) {
+		_, err := dynamo.PutIdempotent(tableNameHashOnly, &expect, "", time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+func TestPutWithOldValue(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	var first HashOnly
+	faker.FakeData(&first)
+
+	t.Run("Success: no previous item", func(t *testing.T) {
+		var old HashOnly
+		res, err := dynamo.PutWithOldValue(tableNameHashOnly, &first, &old)
+
+		assert.NoError(t, err)
+		assert.False(t, res.HasOldValue)
+	})
+
+	t.Run("Success: overwrite", func(t *testing.T) {
+		second := first
+		second.Name = "overwritten"
+
+		var old HashOnly
+		res, err := dynamo.PutWithOldValue(tableNameHashOnly, &second, &old)
+
+		assert.NoError(t, err)
+		assert.True(t, res.HasOldValue)
+		assert.Equal(t, first, old)
+	})
+}
+
+func TestDeleteWithOldValue(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	var expect HashAndRange
+	faker.FakeData(&expect)
+	dynamo.Put(tableNameHashAndRange, &expect)
+
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), expect.Id },
+		Range: func() (string, interface{}, *DynamodbOptions) {
+			return HashAndRange{}.RangeKey(), expect.CreatedAt, nil
+		},
+	}
+
+	var old HashAndRange
+	res, err := dynamo.DeleteWithOldValue(tableNameHashAndRange, key, &old)
+	assert.NoError(t, err)
+	assert.True(t, res.HasOldValue)
+	assert.Equal(t, expect, old)
+
+	res, err = dynamo.DeleteWithOldValue(tableNameHashAndRange, key, &old)
+	assert.NoError(t, err)
+	assert.False(t, res.HasOldValue)
+}
+
 func TestDelete(t *testing.T) {
 	dynamo := newDynamo(t)
 	t.Run("Hash only", func(t *testing.T) {
@@ -300,6 +412,179 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetAllBetween(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKey := faker.UUIDDigit()
+	for _, sortKey := range []string{"2021-01-01", "2021-01-02", "2021-01-03", "2021-01-04"} {
+		_, err := dynamo.Put(tableNameHashAndRange, &HashAndRange{Id: hashKey, CreatedAt: sortKey})
+		assert.NoError(t, err)
+	}
+
+	op, order := DynamodbBetween, DynamodbOrderDesc
+	var items []HashAndRange
+	err := dynamo.GetAll(tableNameHashAndRange, DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+		Range: func() (string, interface{}, *DynamodbOptions) {
+			return "CreatedAt", "2021-01-02", &DynamodbOptions{Operator: &op, Order: &order, UpperBound: "2021-01-03"}
+		},
+	}, &items)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []HashAndRange{{Id: hashKey, CreatedAt: "2021-01-03"}, {Id: hashKey, CreatedAt: "2021-01-02"}}, items)
+}
+
+func TestDeleteIf(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	item := HashOnly{Id: faker.UUIDDigit(), Status: 1}
+	_, err := dynamo.Put(tableNameHashOnly, item)
+	assert.NoError(t, err)
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", item.Id },
+	}
+
+	t.Run("Failure: condition", func(t *testing.T) {
+		_, err := dynamo.DeleteIf(tableNameHashOnly, key, ScanFilter{Expr: "Status = ?", Value: 2})
+		assert.True(t, errors.Is(err, ErrConditionFailed))
+
+		exists, err := dynamo.Exists(tableNameHashOnly, key)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := dynamo.DeleteIf(tableNameHashOnly, key, ScanFilter{Expr: "Status = ?", Value: 1})
+		assert.NoError(t, err)
+
+		exists, err := dynamo.Exists(tableNameHashOnly, key)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestPutIf(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	item := HashOnly{Id: faker.UUIDDigit(), Status: 1}
+	_, err := dynamo.Put(tableNameHashOnly, item)
+	assert.NoError(t, err)
+
+	t.Run("Failure: condition", func(t *testing.T) {
+		_, err := dynamo.PutIf(tableNameHashOnly, HashOnly{Id: item.Id, Status: 3}, ScanFilter{Expr: "Status = ?", Value: 2})
+		assert.True(t, errors.Is(err, ErrConditionFailed))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := dynamo.PutIf(tableNameHashOnly, HashOnly{Id: item.Id, Status: 2}, ScanFilter{Expr: "Status = ?", Value: 1})
+		assert.NoError(t, err)
+
+		var got HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", item.Id },
+		}, &got))
+		assert.Equal(t, 2, got.Status)
+	})
+}
+
+func TestExists(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	item := HashAndRange{Id: faker.UUIDDigit(), CreatedAt: faker.Date(), Name: faker.Name()}
+	_, err := dynamo.Put(tableNameHashAndRange, item)
+	assert.NoError(t, err)
+
+	key := func(createdAt string) DynamodbKey {
+		return DynamodbKey{
+			Hash:  func() (string, interface{}) { return HashAndRange{}.HashKey(), item.Id },
+			Range: func() (string, interface{}, *DynamodbOptions) { return "CreatedAt", createdAt, nil },
+		}
+	}
+
+	t.Run("Success: exists", func(t *testing.T) {
+		exists, err := dynamo.Exists(tableNameHashAndRange, key(item.CreatedAt))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Success: not exists", func(t *testing.T) {
+		exists, err := dynamo.Exists(tableNameHashAndRange, key("missing"))
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestQueryPrefix(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKey := faker.UUIDDigit()
+	for _, sortKey := range []string{"ORDER#1", "ORDER#2", "PROFILE"} {
+		_, err := dynamo.Put(tableNameHashAndRange, &HashAndRange{Id: hashKey, CreatedAt: sortKey})
+		assert.NoError(t, err)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var items []HashAndRange
+		assert.NoError(t, dynamo.QueryPrefix(tableNameHashAndRange, hashKey, "ORDER#", &items))
+		assert.Equal(t, []HashAndRange{{Id: hashKey, CreatedAt: "ORDER#1"}, {Id: hashKey, CreatedAt: "ORDER#2"}}, items)
+	})
+
+	t.Run("Success: empty prefix", func(t *testing.T) {
+		var items []HashAndRange
+		assert.NoError(t, dynamo.QueryPrefix(tableNameHashAndRange, hashKey, "", &items))
+		assert.Len(t, items, 3)
+	})
+
+	t.Run("Failure: no sort key", func(t *testing.T) {
+		var items []HashOnly
+		assert.Error(t, dynamo.QueryPrefix(tableNameHashOnly, hashKey, "ORDER#", &items))
+	})
+}
+
+func TestGetAllLimit(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	hashKey := faker.UUIDDigit()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		var d HashAndRange
+		faker.FakeData(&d)
+		d.Id = hashKey
+		d.CreatedAt = now.AddDate(0, 0, i).Format(time.RFC3339)
+		dynamo.Put(tableNameHashAndRange, &d)
+	}
+
+	op := DynamodbGreater
+	order := DynamodbOrderDesc
+
+	t.Run("Limit", func(t *testing.T) {
+		var data []HashAndRange
+		err := dynamo.GetAll(tableNameHashAndRange, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+			Range: func() (string, interface{}, *DynamodbOptions) {
+				return HashAndRange{}.RangeKey(), "", &DynamodbOptions{Operator: &op, Order: &order, Limit: 3}
+			},
+		}, &data)
+
+		assert.NoError(t, err)
+		if assert.Len(t, data, 3) {
+			assert.Equal(t, now.AddDate(0, 0, 4).Format(time.RFC3339), data[0].CreatedAt)
+		}
+	})
+
+	t.Run("SearchLimit", func(t *testing.T) {
+		count, err := dynamo.Count(tableNameHashAndRange, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+			Range: func() (string, interface{}, *DynamodbOptions) {
+				return HashAndRange{}.RangeKey(), "", &DynamodbOptions{Operator: &op, SearchLimit: 2}
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
 func TestCount(t *testing.T) {
 	dynamo := newDynamo(t)
 
@@ -343,6 +628,86 @@ func TestCount(t *testing.T) {
 	assert.Equal(t, n, int(count))
 }
 
+func TestCountWithResponse(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	// four items of 350KB span two 1MB evaluation pages
+	hashKey := faker.UUIDDigit()
+	for i := 0; i < 4; i++ {
+		dynamo.Put(tableNameHashAndRange, &HashAndRange{
+			Id:        hashKey,
+			CreatedAt: strconv.Itoa(i),
+			Name:      strings.Repeat("x", 350*1024),
+		})
+	}
+
+	count, res, err := dynamo.CountWithResponse(tableNameHashAndRange, DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, 2, res.Pages)
+}
+
+func TestCountWithOptions(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "count-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, WithIndex{}, CreateTableOptions{
+		OnDemand: true,
+		GSIs:     []IndexDefinition{{Name: "Name-index", HashKey: "Name"}},
+		Wait:     true,
+	}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	hashKey, userName := faker.UUIDDigit(), faker.Name()
+	for i := 0; i < 4; i++ {
+		_, err := dynamo.Put(name, WithIndex{Id: hashKey, CreatedAt: strconv.Itoa(i), Name: userName, Status: i % 2})
+		assert.NoError(t, err)
+	}
+
+	t.Run("Success: filters", func(t *testing.T) {
+		result, _, err := dynamo.CountWithOptions(name, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", hashKey },
+		}, CountOptions{Filters: []ScanFilter{{Expr: "Status = ?", Value: 1}}})
+		assert.NoError(t, err)
+		assert.Equal(t, CountResult{Count: 2, ScannedCount: 4}, result)
+	})
+
+	t.Run("Success: index", func(t *testing.T) {
+		result, _, err := dynamo.CountWithOptions(name, DynamodbKey{
+			Hash: func() (string, interface{}) { return "Name", userName },
+		}, CountOptions{Index: "Name-index"})
+		assert.NoError(t, err)
+		assert.Equal(t, CountResult{Count: 4, ScannedCount: 4}, result)
+	})
+}
+
+func TestQueryStats(t *testing.T) {
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{ReturnConsumedCapacity: true})
+
+	hashKey := faker.UUIDDigit()
+	for i := 0; i < 3; i++ {
+		_, err := dynamo.Put(tableNameHashAndRange, &HashAndRange{Id: hashKey, CreatedAt: strconv.Itoa(i)})
+		assert.NoError(t, err)
+	}
+
+	var items []HashAndRange
+	res, err := dynamo.GetAllWithResponse(tableNameHashAndRange, DynamodbKey{
+		Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), hashKey },
+	}, &items)
+	assert.NoError(t, err)
+
+	stats := res.Stats()
+	assert.Equal(t, int64(3), stats.Count)
+	assert.Equal(t, int64(3), stats.ScannedCount)
+	assert.Equal(t, 1, stats.Pages)
+	assert.NotNil(t, stats.ConsumedCapacity)
+	assert.Greater(t, int64(stats.Duration), int64(0))
+}
+
 func TestPaging(t *testing.T) {
 	dynamo := newDynamo(t)
 
@@ -405,3 +770,24 @@ func TestPaging(t *testing.T) {
 
 	assert.Equal(t, itemsCount/pageSize, pageCount)
 }
+
+func TestPagingInvalidCursor(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	var page []*HashAndRange
+	err := dynamo.Paging(
+		tableNameHashAndRange,
+		DynamodbKey{
+			Hash: func() (string, interface{}) { return HashAndRange{}.HashKey(), faker.UUIDDigit() },
+		},
+		DynamodbPaged{
+			Limit: 1,
+			PageKeys: []*DynamodbAttributeValue{
+				{Key: "Unknown", Value: "value"},
+			},
+		},
+		&page,
+	)
+
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+}
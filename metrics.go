@@ -0,0 +1,216 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"expvar"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Metrics collects statistics of every API call, labeled by operation and table.
+// A throttled attempt retried by the SDK counts as a throttle but not as an error.
+type Metrics interface {
+	IncOperation(op, table string)
+	IncError(op, table string)
+	IncThrottle(op, table string)
+	ObserveLatency(op, table string, d time.Duration)
+	ObserveItems(op, table string, n int)
+}
+
+// Histogram buckets used by ExpvarMetrics and PrometheusMetrics, latencies in milliseconds.
+var (
+	LatencyBuckets = []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+	ItemBuckets    = []float64{0, 1, 10, 25, 100, 1000}
+)
+
+// ExpvarMetrics publishes Metrics as expvar variables, served at /debug/vars.
+// Latencies are in milliseconds. Keys are "table:op".
+type ExpvarMetrics struct {
+	operations *expvar.Map
+	errors     *expvar.Map
+	throttles  *expvar.Map
+	latency    *expvar.Map
+	items      *expvar.Map
+
+	mu sync.Mutex
+}
+
+// NewExpvarMetrics publishes the metrics under name, which must be unique within the process.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		operations: new(expvar.Map).Init(),
+		errors:     new(expvar.Map).Init(),
+		throttles:  new(expvar.Map).Init(),
+		latency:    new(expvar.Map).Init(),
+		items:      new(expvar.Map).Init(),
+	}
+
+	root := expvar.NewMap(name)
+	root.Set("operations", m.operations)
+	root.Set("errors", m.errors)
+	root.Set("throttles", m.throttles)
+	root.Set("latency_ms", m.latency)
+	root.Set("items", m.items)
+	return m
+}
+
+func metricKey(op, table string) string {
+	return table + ":" + op
+}
+
+func (m *ExpvarMetrics) IncOperation(op, table string) {
+	m.operations.Add(metricKey(op, table), 1)
+}
+
+func (m *ExpvarMetrics) IncError(op, table string) {
+	m.errors.Add(metricKey(op, table), 1)
+}
+
+func (m *ExpvarMetrics) IncThrottle(op, table string) {
+	m.throttles.Add(metricKey(op, table), 1)
+}
+
+func (m *ExpvarMetrics) ObserveLatency(op, table string, d time.Duration) {
+	m.histogram(m.latency, metricKey(op, table), LatencyBuckets).observe(float64(d) / float64(time.Millisecond))
+}
+
+func (m *ExpvarMetrics) ObserveItems(op, table string, n int) {
+	m.histogram(m.items, metricKey(op, table), ItemBuckets).observe(float64(n))
+}
+
+func (m *ExpvarMetrics) histogram(vars *expvar.Map, key string, buckets []float64) *histogram {
+	if h, ok := vars.Get(key).(*histogram); ok {
+		return h
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok := vars.Get(key).(*histogram); ok {
+		return h
+	}
+	h := &histogram{buckets: buckets, counts: make([]int64, len(buckets)+1)}
+	vars.Set(key, h)
+	return h
+}
+
+// histogram is an expvar.Var counting observations per upper bound.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []int64
+	count   int64
+	sum     float64
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i := 0
+	for i < len(h.buckets) && v > h.buckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += v
+}
+
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	type bucket struct {
+		Le    float64 `json:"le"`
+		Count int64   `json:"count"`
+	}
+	out := struct {
+		Count   int64    `json:"count"`
+		Sum     float64  `json:"sum"`
+		Buckets []bucket `json:"buckets"`
+	}{Count: h.count, Sum: h.sum}
+
+	var cumulative int64
+	for i, le := range h.buckets {
+		cumulative += h.counts[i]
+		out.Buckets = append(out.Buckets, bucket{Le: le, Count: cumulative})
+	}
+
+	b, _ := json.Marshal(out)
+	return string(b)
+}
+
+func (con *dynamodb) observe(op OperationInfo, d time.Duration, output interface{}, err error) {
+	metrics := con.config.Metrics
+	if metrics == nil {
+		return
+	}
+
+	metrics.IncOperation(op.Name, op.Table)
+	metrics.ObserveLatency(op.Name, op.Table, d)
+	if err != nil {
+		metrics.IncError(op.Name, op.Table)
+		return
+	}
+	if n, ok := itemCount(output); ok {
+		metrics.ObserveItems(op.Name, op.Table, n)
+	}
+}
+
+// itemCount counts the items returned by reads, given a pointer to the SDK output.
+func itemCount(output interface{}) (int, bool) {
+	switch out := output.(type) {
+	case **awsDynamodb.GetItemOutput:
+		if *out != nil && (*out).Item != nil {
+			return 1, true
+		}
+		return 0, true
+	case **awsDynamodb.QueryOutput:
+		if *out != nil {
+			return int(aws.Int64Value((*out).Count)), true
+		}
+	case **awsDynamodb.ScanOutput:
+		if *out != nil {
+			return int(aws.Int64Value((*out).Count)), true
+		}
+	case **awsDynamodb.BatchGetItemOutput:
+		if *out != nil {
+			n := 0
+			for _, items := range (*out).Responses {
+				n += len(items)
+			}
+			return n, true
+		}
+	case **awsDynamodb.TransactGetItemsOutput:
+		if *out != nil {
+			return len((*out).Responses), true
+		}
+	}
+	return 0, false
+}
+
+// countThrottles counts throttled attempts before the SDK retries them.
+func countThrottles(metrics Metrics) func(r *request.Request) {
+	return func(r *request.Request) {
+		if request.IsErrorThrottle(r.Error) {
+			metrics.IncThrottle(r.Operation.Name, requestTable(r.Params))
+		}
+	}
+}
+
+func requestTable(params interface{}) string {
+	v := reflect.ValueOf(params)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	if field := v.Elem().FieldByName("TableName"); field.IsValid() {
+		if name, ok := field.Interface().(*string); ok {
+			return aws.StringValue(name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedMetrics struct {
+	mu         sync.Mutex
+	operations map[string]int
+	errors     map[string]int
+	items      map[string]int
+}
+
+func (m *recordedMetrics) IncOperation(op, table string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations[metricKey(op, table)]++
+}
+
+func (m *recordedMetrics) IncError(op, table string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[metricKey(op, table)]++
+}
+
+func (m *recordedMetrics) IncThrottle(op, table string) {}
+
+func (m *recordedMetrics) ObserveLatency(op, table string, d time.Duration) {}
+
+func (m *recordedMetrics) ObserveItems(op, table string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[metricKey(op, table)] += n
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := &recordedMetrics{operations: map[string]int{}, errors: map[string]int{}, items: map[string]int{}}
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{Metrics: metrics})
+
+	item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	_, err := dynamo.Put(tableNameHashOnly, item)
+	assert.NoError(t, err)
+
+	var result HashOnly
+	assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+		Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+	}, &result))
+
+	_, err = dynamo.DescribeTable("not-exists")
+	assert.Error(t, err)
+
+	assert.Equal(t, 1, metrics.operations[metricKey("PutItem", tableNameHashOnly)])
+	assert.Equal(t, 1, metrics.operations[metricKey("GetItem", tableNameHashOnly)])
+	assert.Equal(t, 1, metrics.items[metricKey("GetItem", tableNameHashOnly)])
+	assert.Equal(t, 1, metrics.errors[metricKey("DescribeTable", "not-exists")])
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := NewExpvarMetrics("dynamodb-test-" + faker.UUIDDigit())
+	metrics.IncOperation("Query", "users")
+	metrics.ObserveLatency("Query", "users", 3*time.Millisecond)
+	metrics.ObserveLatency("Query", "users", time.Minute)
+
+	assert.Equal(t, "1", metrics.operations.Get("users:Query").String())
+
+	var histogram struct {
+		Count   int64
+		Buckets []struct {
+			Le    float64
+			Count int64
+		}
+	}
+	assert.NoError(t, json.Unmarshal([]byte(metrics.latency.Get("users:Query").String()), &histogram))
+	assert.Equal(t, int64(2), histogram.Count)
+	assert.Equal(t, int64(0), histogram.Buckets[1].Count)
+	assert.Equal(t, int64(1), histogram.Buckets[2].Count)
+	assert.Equal(t, int64(1), histogram.Buckets[len(histogram.Buckets)-1].Count)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics("")
+	metrics.IncOperation("Query", "users")
+	metrics.IncOperation("Query", "users")
+	metrics.IncThrottle("Query", `a"b`)
+	metrics.ObserveLatency("Query", "users", 3*time.Millisecond)
+	metrics.ObserveLatency("Query", "users", time.Minute)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	assert.Contains(t, body, "# TYPE dynamodb_operations_total counter\n")
+	assert.Contains(t, body, `dynamodb_operations_total{op="Query",table="users"} 2`)
+	assert.Contains(t, body, `dynamodb_throttles_total{op="Query",table="a\"b"} 1`)
+	assert.Contains(t, body, "# TYPE dynamodb_latency_seconds histogram\n")
+	assert.Contains(t, body, `dynamodb_latency_seconds_bucket{op="Query",table="users",le="0.0025"} 0`)
+	assert.Contains(t, body, `dynamodb_latency_seconds_bucket{op="Query",table="users",le="0.005"} 1`)
+	assert.Contains(t, body, `dynamodb_latency_seconds_bucket{op="Query",table="users",le="+Inf"} 2`)
+	assert.Contains(t, body, `dynamodb_latency_seconds_count{op="Query",table="users"} 2`)
+}
@@ -0,0 +1,353 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// OperationInfo describes one DynamoDB API call.
+type OperationInfo struct {
+	// Name is the API operation, such as GetItem or Query.
+	Name string
+	// Table is empty for operations spanning several tables.
+	Table string
+	// Input is the SDK input of the call, such as *dynamodb.GetItemInput.
+	Input interface{}
+}
+
+// Handler continues an operation.
+type Handler func(ctx context.Context) error
+
+// Middleware wraps every API call made by the client. It must call next to perform the call,
+// or return an error to abort it.
+type Middleware func(ctx context.Context, op OperationInfo, next Handler) error
+
+var errNextNotCalled = errors.New("middleware returned without calling next")
+
+type middlewares struct {
+	mu    sync.RWMutex
+	chain []Middleware
+}
+
+func (m *middlewares) use(middleware ...Middleware) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chain = append(m.chain[:len(m.chain):len(m.chain)], middleware...)
+}
+
+func (m *middlewares) invoke(ctx context.Context, op OperationInfo, fn Handler) error {
+	m.mu.RLock()
+	chain := m.chain
+	m.mu.RUnlock()
+
+	called := false
+	var next Handler = func(ctx context.Context) error {
+		called = true
+		return fn(ctx)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		middleware, inner := chain[i], next
+		next = func(ctx context.Context) error {
+			return middleware(ctx, op, inner)
+		}
+	}
+
+	if err := next(ctx); err != nil || called {
+		return err
+	}
+	return errNextNotCalled
+}
+
+// Use registers middleware, which runs in registration order around every API call.
+func (con *dynamodb) Use(middleware ...Middleware) {
+	con.middlewares.use(middleware...)
+}
+
+// middlewareClient runs the calls made by dynamo and this package through the middlewares.
+type middlewareClient struct {
+	dynamodbiface.DynamoDBAPI
+	con *dynamodb
+	// reads serves the read operations when set.
+	reads dynamodbiface.DynamoDBAPI
+}
+
+func (c *middlewareClient) reader() dynamodbiface.DynamoDBAPI {
+	if c.reads != nil {
+		return c.reads
+	}
+	return c.DynamoDBAPI
+}
+
+// invoke runs fn, which stores the SDK output of the call into output.
+func (c *middlewareClient) invoke(ctx context.Context, name string, table *string, input, output interface{}, fn Handler) error {
+	op := OperationInfo{Name: name, Table: aws.StringValue(table), Input: input}
+	err := c.con.middlewares.invoke(ctx, op, func(ctx context.Context) error {
+		if err := c.con.limitRate(ctx, op); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		err := c.con.injectFault(ctx, op)
+		if err == nil {
+			err = fn(ctx)
+		}
+		if err == nil {
+			c.con.meterRate(op, output)
+		}
+		d := time.Since(start)
+		c.con.observe(op, d, output, err)
+		c.con.logOperation(op, d, err, c.con.recordRequest(op, start, d, err))
+		return err
+	})
+	return c.con.spentRetries(err)
+}
+
+// batchTable returns the table of a batch request on a single table.
+func batchTable(tables []string) *string {
+	if len(tables) != 1 {
+		return nil
+	}
+	return &tables[0]
+}
+
+func (c *middlewareClient) GetItemWithContext(ctx aws.Context, in *awsDynamodb.GetItemInput, opts ...request.Option) (out *awsDynamodb.GetItemOutput, err error) {
+	err = c.invoke(ctx, "GetItem", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.reader().GetItemWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		err = c.con.rehydrate(ctx, out.Item)
+	}
+	return out, err
+}
+
+func (c *middlewareClient) PutItemWithContext(ctx aws.Context, in *awsDynamodb.PutItemInput, opts ...request.Option) (out *awsDynamodb.PutItemOutput, err error) {
+	if in, err = c.con.offloadPut(ctx, in); err != nil {
+		return nil, err
+	}
+	err = c.invoke(ctx, "PutItem", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.PutItemWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		err = c.con.rehydrate(ctx, out.Attributes)
+	}
+	return out, err
+}
+
+func (c *middlewareClient) UpdateItemWithContext(ctx aws.Context, in *awsDynamodb.UpdateItemInput, opts ...request.Option) (out *awsDynamodb.UpdateItemOutput, err error) {
+	err = c.invoke(ctx, "UpdateItem", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.UpdateItemWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		err = c.con.rehydrate(ctx, out.Attributes)
+	}
+	return out, err
+}
+
+func (c *middlewareClient) DeleteItemWithContext(ctx aws.Context, in *awsDynamodb.DeleteItemInput, opts ...request.Option) (out *awsDynamodb.DeleteItemOutput, err error) {
+	err = c.invoke(ctx, "DeleteItem", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.DeleteItemWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		err = c.con.rehydrate(ctx, out.Attributes)
+	}
+	return out, err
+}
+
+func (c *middlewareClient) QueryWithContext(ctx aws.Context, in *awsDynamodb.QueryInput, opts ...request.Option) (out *awsDynamodb.QueryOutput, err error) {
+	err = c.invoke(ctx, "Query", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.reader().QueryWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		recordPage(ctx, out.Count, out.ScannedCount)
+		err = c.con.rehydrateAll(ctx, out.Items)
+	}
+	return out, err
+}
+
+func (c *middlewareClient) ScanWithContext(ctx aws.Context, in *awsDynamodb.ScanInput, opts ...request.Option) (out *awsDynamodb.ScanOutput, err error) {
+	err = c.invoke(ctx, "Scan", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.reader().ScanWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		recordPage(ctx, out.Count, out.ScannedCount)
+		err = c.con.rehydrateAll(ctx, out.Items)
+	}
+	return out, err
+}
+
+func (c *middlewareClient) BatchGetItemWithContext(ctx aws.Context, in *awsDynamodb.BatchGetItemInput, opts ...request.Option) (out *awsDynamodb.BatchGetItemOutput, err error) {
+	tables := make([]string, 0, len(in.RequestItems))
+	for table := range in.RequestItems {
+		tables = append(tables, table)
+	}
+
+	op := OperationInfo{Name: "BatchGetItem", Table: aws.StringValue(batchTable(tables))}
+	err = c.invoke(ctx, op.Name, batchTable(tables), in, &out, func(ctx context.Context) (err error) {
+		if !c.con.partialBatch(op) {
+			out, err = c.reader().BatchGetItemWithContext(ctx, in, opts...)
+			return err
+		}
+
+		send, held := holdKeys(in)
+		if out, err = c.reader().BatchGetItemWithContext(ctx, send, opts...); err != nil {
+			return err
+		}
+		if out.UnprocessedKeys == nil {
+			out.UnprocessedKeys = held
+			return nil
+		}
+		for table, keys := range held {
+			if unprocessed, ok := out.UnprocessedKeys[table]; ok {
+				keys.Keys = append(unprocessed.Keys, keys.Keys...)
+			}
+			out.UnprocessedKeys[table] = keys
+		}
+		return nil
+	})
+	if err == nil {
+		for _, items := range out.Responses {
+			if err = c.con.rehydrateAll(ctx, items); err != nil {
+				break
+			}
+		}
+	}
+	return out, err
+}
+
+func (c *middlewareClient) BatchWriteItemWithContext(ctx aws.Context, in *awsDynamodb.BatchWriteItemInput, opts ...request.Option) (out *awsDynamodb.BatchWriteItemOutput, err error) {
+	if in, err = c.con.offloadBatch(ctx, in); err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(in.RequestItems))
+	for table := range in.RequestItems {
+		tables = append(tables, table)
+	}
+
+	op := OperationInfo{Name: "BatchWriteItem", Table: aws.StringValue(batchTable(tables))}
+	err = c.invoke(ctx, op.Name, batchTable(tables), in, &out, func(ctx context.Context) (err error) {
+		if !c.con.partialBatch(op) {
+			out, err = c.DynamoDBAPI.BatchWriteItemWithContext(ctx, in, opts...)
+			return err
+		}
+
+		send, held := holdWrites(in)
+		if out, err = c.DynamoDBAPI.BatchWriteItemWithContext(ctx, send, opts...); err != nil {
+			return err
+		}
+		if out.UnprocessedItems == nil {
+			out.UnprocessedItems = make(map[string][]*awsDynamodb.WriteRequest)
+		}
+		for table, requests := range held {
+			out.UnprocessedItems[table] = append(out.UnprocessedItems[table], requests...)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (c *middlewareClient) TransactGetItems(in *awsDynamodb.TransactGetItemsInput) (*awsDynamodb.TransactGetItemsOutput, error) {
+	return c.TransactGetItemsWithContext(aws.BackgroundContext(), in)
+}
+
+func (c *middlewareClient) TransactGetItemsWithContext(ctx aws.Context, in *awsDynamodb.TransactGetItemsInput, opts ...request.Option) (out *awsDynamodb.TransactGetItemsOutput, err error) {
+	err = c.invoke(ctx, "TransactGetItems", nil, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.reader().TransactGetItemsWithContext(ctx, in, opts...)
+		return err
+	})
+	if err == nil {
+		for _, res := range out.Responses {
+			if err = c.con.rehydrate(ctx, res.Item); err != nil {
+				break
+			}
+		}
+	}
+	return out, err
+}
+
+func (c *middlewareClient) TransactWriteItemsWithContext(ctx aws.Context, in *awsDynamodb.TransactWriteItemsInput, opts ...request.Option) (out *awsDynamodb.TransactWriteItemsOutput, err error) {
+	if in, err = c.con.offloadTransact(ctx, in); err != nil {
+		return nil, err
+	}
+	err = c.invoke(ctx, "TransactWriteItems", nil, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.TransactWriteItemsWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) ListTablesWithContext(ctx aws.Context, in *awsDynamodb.ListTablesInput, opts ...request.Option) (out *awsDynamodb.ListTablesOutput, err error) {
+	err = c.invoke(ctx, "ListTables", nil, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.ListTablesWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) CreateTableWithContext(ctx aws.Context, in *awsDynamodb.CreateTableInput, opts ...request.Option) (out *awsDynamodb.CreateTableOutput, err error) {
+	err = c.invoke(ctx, "CreateTable", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.CreateTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) DescribeTableWithContext(ctx aws.Context, in *awsDynamodb.DescribeTableInput, opts ...request.Option) (out *awsDynamodb.DescribeTableOutput, err error) {
+	err = c.invoke(ctx, "DescribeTable", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.DescribeTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) UpdateTableWithContext(ctx aws.Context, in *awsDynamodb.UpdateTableInput, opts ...request.Option) (out *awsDynamodb.UpdateTableOutput, err error) {
+	err = c.invoke(ctx, "UpdateTable", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.UpdateTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) DeleteTableWithContext(ctx aws.Context, in *awsDynamodb.DeleteTableInput, opts ...request.Option) (out *awsDynamodb.DeleteTableOutput, err error) {
+	err = c.invoke(ctx, "DeleteTable", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.DeleteTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) DescribeTimeToLiveWithContext(ctx aws.Context, in *awsDynamodb.DescribeTimeToLiveInput, opts ...request.Option) (out *awsDynamodb.DescribeTimeToLiveOutput, err error) {
+	err = c.invoke(ctx, "DescribeTimeToLive", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.DescribeTimeToLiveWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) UpdateTimeToLiveWithContext(ctx aws.Context, in *awsDynamodb.UpdateTimeToLiveInput, opts ...request.Option) (out *awsDynamodb.UpdateTimeToLiveOutput, err error) {
+	err = c.invoke(ctx, "UpdateTimeToLive", in.TableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.UpdateTimeToLiveWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *middlewareClient) RestoreTableToPointInTimeWithContext(ctx aws.Context, in *awsDynamodb.RestoreTableToPointInTimeInput, opts ...request.Option) (out *awsDynamodb.RestoreTableToPointInTimeOutput, err error) {
+	err = c.invoke(ctx, "RestoreTableToPointInTime", in.TargetTableName, in, &out, func(ctx context.Context) (err error) {
+		out, err = c.DynamoDBAPI.RestoreTableToPointInTimeWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
@@ -0,0 +1,111 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUse(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dynamo := newDynamo(t)
+
+		var calls []string
+		var ops []OperationInfo
+		dynamo.Use(
+			func(ctx context.Context, op OperationInfo, next Handler) error {
+				calls = append(calls, "outer")
+				ops = append(ops, op)
+				return next(ctx)
+			},
+			func(ctx context.Context, op OperationInfo, next Handler) error {
+				calls = append(calls, "inner")
+				return next(ctx)
+			},
+		)
+
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		var result HashOnly
+		err = dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+		}, &result)
+		assert.NoError(t, err)
+		assert.Equal(t, item, result)
+
+		assert.Equal(t, []string{"outer", "inner", "outer", "inner"}, calls)
+		if assert.Len(t, ops, 2) {
+			assert.Equal(t, "PutItem", ops[0].Name)
+			assert.Equal(t, tableNameHashOnly, ops[0].Table)
+			assert.Equal(t, "GetItem", ops[1].Name)
+		}
+	})
+
+	t.Run("Failure: aborted", func(t *testing.T) {
+		dynamo := newDynamo(t)
+
+		denied := errors.New("denied")
+		dynamo.Use(func(ctx context.Context, op OperationInfo, next Handler) error {
+			if op.Name == "DeleteItem" {
+				return denied
+			}
+			return next(ctx)
+		})
+
+		_, err := dynamo.Delete(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), faker.UUIDDigit() },
+		})
+		assert.ErrorIs(t, err, denied)
+	})
+
+	t.Run("Failure: next not called", func(t *testing.T) {
+		dynamo := newDynamo(t)
+
+		dynamo.Use(func(ctx context.Context, op OperationInfo, next Handler) error {
+			return nil
+		})
+
+		_, err := dynamo.Put(tableNameHashOnly, HashOnly{Id: faker.UUIDDigit()})
+		assert.Error(t, err)
+	})
+}
+
+type countingReads struct {
+	dynamodbiface.DynamoDBAPI
+	gets int
+}
+
+func (c *countingReads) GetItemWithContext(ctx aws.Context, in *awsDynamodb.GetItemInput, opts ...request.Option) (*awsDynamodb.GetItemOutput, error) {
+	c.gets++
+	return c.DynamoDBAPI.GetItemWithContext(ctx, in, opts...)
+}
+
+func TestReadClient(t *testing.T) {
+	reads := &countingReads{DynamoDBAPI: awsDynamodb.New(session.New(), aws.NewConfig().
+		WithEndpoint("http://localhost:8000").
+		WithRegion("us-east-1"))}
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{ReadClient: reads})
+
+	item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	_, err := dynamo.Put(tableNameHashOnly, item)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reads.gets)
+
+	var result HashOnly
+	err = dynamo.Get(tableNameHashOnly, DynamodbKey{
+		Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+	}, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, item, result)
+	assert.Equal(t, 1, reads.gets)
+}
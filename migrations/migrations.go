@@ -0,0 +1,246 @@
+// Package migrations applies ordered schema migrations to DynamoDB tables, recording the applied
+// versions in a metadata table:
+//
+//	m := migrations.New(db, migrations.Options{})
+//	m.Register(
+//		migrations.Migration{Version: 1, Name: "create users", Up: migrations.CreateTable(spec)},
+//		migrations.Migration{Version: 2, Name: "index email", Up: migrations.AddIndex("users", index)},
+//	)
+//	err := m.Migrate(ctx)
+//
+// Migrations should be idempotent, which the helpers of this package are, so a migration interrupted
+// by a crash can run again.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/linksports/dynamodb"
+)
+
+// DefaultTable is the metadata table used when Options.Table is unset.
+const DefaultTable = "schema_migrations"
+
+// ErrMigrationRunning is returned by Migrate when another process is applying the same migration.
+// A process that crashed while applying a migration leaves it running; delete its item from the
+// metadata table to retry.
+var ErrMigrationRunning = errors.New("migration running")
+
+// Migration statuses
+const (
+	StatusRunning = "running"
+	StatusApplied = "applied"
+)
+
+// Migration :
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db dynamodb.Dynamodb) error
+}
+
+// Options :
+type Options struct {
+	// Table is the metadata table, created on the first Migrate. Defaults to DefaultTable.
+	Table string
+}
+
+// Record is an item of the metadata table.
+type Record struct {
+	Version   int       `dynamo:"Version,hash"`
+	Name      string    `dynamo:"Name"`
+	Status    string    `dynamo:"Status"`
+	StartedAt time.Time `dynamo:"StartedAt"`
+	AppliedAt time.Time `dynamo:"AppliedAt"`
+}
+
+// Migrator :
+type Migrator struct {
+	db         dynamodb.Dynamodb
+	table      string
+	migrations []Migration
+}
+
+// New :
+func New(db dynamodb.Dynamodb, opts Options) *Migrator {
+	table := opts.Table
+	if len(table) < 1 {
+		table = DefaultTable
+	}
+	return &Migrator{db: db, table: table}
+}
+
+// Register adds migrations, which are applied in the order of their versions.
+func (m *Migrator) Register(migrations ...Migration) {
+	m.migrations = append(m.migrations, migrations...)
+}
+
+// Migrate applies the pending migrations in order and stops at the first failure,
+// which is not recorded so the migration runs again next time.
+func (m *Migrator) Migrate(ctx context.Context) error {
+	migrations := make([]Migration, len(m.migrations))
+	copy(migrations, m.migrations)
+	sort.SliceStable(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return fmt.Errorf("duplicate migration version %d", migrations[i].Version)
+		}
+	}
+
+	err := m.db.ApplyTableSpec(ctx, dynamodb.TableSpec{
+		Name:        m.table,
+		HashKey:     "Version",
+		HashKeyType: dynamodb.DynamodbKeyTypeNumber,
+		OnDemand:    true,
+	})
+	if err != nil {
+		return err
+	}
+
+	records, err := m.Records()
+	if err != nil {
+		return err
+	}
+	applied := map[int]bool{}
+	for _, record := range records {
+		applied[record.Version] = record.Status == StatusApplied
+	}
+
+	for _, migration := range migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	record := Record{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Status:    StatusRunning,
+		StartedAt: time.Now(),
+	}
+	if _, err := m.db.PutIfNotExists(m.table, record); err != nil {
+		if errors.Is(err, dynamodb.ErrItemExists) {
+			return fmt.Errorf("%w: %d %s", ErrMigrationRunning, migration.Version, migration.Name)
+		}
+		return err
+	}
+
+	if err := migration.Up(ctx, m.db); err != nil {
+		m.db.Delete(m.table, versionKey(migration.Version))
+		return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+
+	record.Status, record.AppliedAt = StatusApplied, time.Now()
+	_, err := m.db.Put(m.table, record)
+	return err
+}
+
+// Records returns the applied and running migrations.
+func (m *Migrator) Records() ([]Record, error) {
+	var records []Record
+	if err := m.db.Scan(m.table, &records); err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Version < records[j].Version })
+	return records, nil
+}
+
+// Version returns the highest applied version, or 0 before any migration.
+func (m *Migrator) Version() (int, error) {
+	records, err := m.Records()
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for _, record := range records {
+		if record.Status == StatusApplied && record.Version > version {
+			version = record.Version
+		}
+	}
+	return version, nil
+}
+
+func versionKey(version int) dynamodb.DynamodbKey {
+	return dynamodb.DynamodbKey{
+		Hash: func() (string, interface{}) { return "Version", version },
+	}
+}
+
+// CreateTable creates the table of spec, or converges it if it exists.
+func CreateTable(spec dynamodb.TableSpec) func(ctx context.Context, db dynamodb.Dynamodb) error {
+	return func(ctx context.Context, db dynamodb.Dynamodb) error {
+		return db.ApplyTableSpec(ctx, spec)
+	}
+}
+
+// AddIndex adds a global secondary index to the table unless it has an index of that name.
+// It returns once the index is active, which takes as long as backfilling the index.
+func AddIndex(tableName string, index dynamodb.IndexDefinition) func(ctx context.Context, db dynamodb.Dynamodb) error {
+	return func(ctx context.Context, db dynamodb.Dynamodb) error {
+		spec, err := db.DescribeTableSpec(ctx, tableName)
+		if err != nil {
+			return err
+		}
+		for _, existing := range spec.GSIs {
+			if existing.Name == index.Name {
+				return nil
+			}
+		}
+
+		spec.GSIs = append(spec.GSIs, index)
+		return db.ApplyTableSpec(ctx, spec)
+	}
+}
+
+// EnableTTL enables TTL on attribute unless it is enabled already.
+func EnableTTL(tableName, attribute string) func(ctx context.Context, db dynamodb.Dynamodb) error {
+	return func(ctx context.Context, db dynamodb.Dynamodb) error {
+		spec, err := db.DescribeTableSpec(ctx, tableName)
+		if err != nil {
+			return err
+		}
+
+		spec.TTLAttribute = attribute
+		return db.ApplyTableSpec(ctx, spec)
+	}
+}
+
+// Backfill sets attribute on every item of the table lacking it, to the value returned for the item.
+// A nil value leaves the item unchanged. Items written during the backfill may be missed.
+func Backfill(tableName, attribute string, value func(item map[string]*awsDynamodb.AttributeValue) (interface{}, error)) func(ctx context.Context, db dynamodb.Dynamodb) error {
+	return func(ctx context.Context, db dynamodb.Dynamodb) error {
+		spec, err := db.DescribeTableSpec(ctx, tableName)
+		if err != nil {
+			return err
+		}
+
+		var item map[string]*awsDynamodb.AttributeValue
+		return db.ScanEach(ctx, tableName, &item, func() error {
+			v, err := value(item)
+			if err != nil || v == nil {
+				return err
+			}
+
+			hash, rng := item[spec.HashKey], item[spec.RangeKey]
+			key := dynamodb.DynamodbKey{
+				Hash: func() (string, interface{}) { return spec.HashKey, hash },
+			}
+			if len(spec.RangeKey) > 0 {
+				key.Range = func() (string, interface{}, *dynamodb.DynamodbOptions) { return spec.RangeKey, rng, nil }
+			}
+			return db.SetSparse(tableName, key, attribute, v)
+		}, dynamodb.FilterNotExists(attribute))
+	}
+}
@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bxcodec/faker/v3"
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type User struct {
+	Id    string `dynamo:"ID,hash"`
+	Email string `dynamo:"Email"`
+	Plan  string `dynamo:"Plan"`
+}
+
+func newDynamo(t *testing.T) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	db := newDynamo(t)
+	ctx := context.Background()
+
+	meta, users := "migrations-"+faker.UUIDDigit(), "users-"+faker.UUIDDigit()
+	defer db.DeleteTableWithContext(ctx, meta)
+	defer db.DeleteTableWithContext(ctx, users)
+
+	m := New(db, Options{Table: meta})
+	m.Register(
+		Migration{Version: 2, Name: "index email", Up: AddIndex(users, dynamodb.IndexDefinition{Name: "Email-index", HashKey: "Email"})},
+		Migration{Version: 1, Name: "create users", Up: CreateTable(dynamodb.TableSpec{Name: users, HashKey: "ID", OnDemand: true})},
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, m.Migrate(ctx))
+
+		version, err := m.Version()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, version)
+
+		desc, err := db.DescribeTable(users)
+		assert.NoError(t, err)
+		assert.Len(t, desc.GSIs, 1)
+	})
+
+	t.Run("Success: backfill", func(t *testing.T) {
+		user := User{Id: faker.UUIDDigit(), Email: faker.Email()}
+		_, err := db.Put(users, user)
+		assert.NoError(t, err)
+
+		m.Register(
+			Migration{Version: 3, Name: "default plan", Up: Backfill(users, "Plan", func(item map[string]*awsDynamodb.AttributeValue) (interface{}, error) {
+				return "free", nil
+			})},
+			Migration{Version: 4, Name: "ttl", Up: EnableTTL(users, "ExpiresAt")},
+		)
+		assert.NoError(t, m.Migrate(ctx))
+
+		var got User
+		assert.NoError(t, db.Get(users, dynamodb.DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", user.Id },
+		}, &got))
+		assert.Equal(t, "free", got.Plan)
+
+		ttl, err := db.DescribeTTL(users)
+		assert.NoError(t, err)
+		assert.Equal(t, "ExpiresAt", ttl.Attribute)
+	})
+
+	t.Run("Success: applied once", func(t *testing.T) {
+		runs := 0
+		m := New(db, Options{Table: meta})
+		m.Register(Migration{Version: 1, Up: func(ctx context.Context, db dynamodb.Dynamodb) error {
+			runs++
+			return nil
+		}})
+		assert.NoError(t, m.Migrate(ctx))
+		assert.Equal(t, 0, runs)
+	})
+
+	t.Run("Failure: not recorded", func(t *testing.T) {
+		m := New(db, Options{Table: meta})
+		m.Register(Migration{Version: 5, Name: "broken", Up: func(ctx context.Context, db dynamodb.Dynamodb) error {
+			return errors.New("broken")
+		}})
+		assert.Error(t, m.Migrate(ctx))
+
+		version, err := m.Version()
+		assert.NoError(t, err)
+		assert.Equal(t, 4, version)
+	})
+
+	t.Run("Failure: duplicate version", func(t *testing.T) {
+		m := New(db, Options{Table: meta})
+		m.Register(Migration{Version: 1}, Migration{Version: 1})
+		assert.Error(t, m.Migrate(ctx))
+	})
+}
@@ -0,0 +1,490 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// Defaults used for unset FailoverPolicy fields.
+const (
+	DefaultFailureThreshold = 3
+	DefaultRegionCooldown   = 30 * time.Second
+	DefaultProbeInterval    = 10 * time.Second
+)
+
+// Region scores are moving averages in which each request weighs latencyDecay.
+// A region failing every request scores errorPenalty+1 times its latency.
+const (
+	latencyDecay = 0.2
+	errorPenalty = 10
+)
+
+// FailoverPolicy controls a NewMultiRegion client.
+type FailoverPolicy struct {
+	// WriteFallback sends writes to the other regions, in order, when the primary fails.
+	// Global Tables resolve concurrent writes to one item with last writer wins.
+	WriteFallback bool
+	// FailureThreshold is the number of consecutive failures after which a region is skipped.
+	FailureThreshold int
+	// Cooldown is how long an unhealthy region is skipped before it is tried again.
+	Cooldown time.Duration
+	// ShouldFailover reports whether err is worth retrying in another region.
+	// Defaults to IsRetryable errors and timeouts.
+	ShouldFailover func(err error) bool
+	// LatencyRouting sends reads to the healthy region with the best score, made of its rolling
+	// latency and error rate, instead of in config order. Writes keep the config order.
+	LatencyRouting bool
+	// ProbeInterval is how often, with LatencyRouting, a read goes first to the region
+	// used least recently, so demoted regions are measured again and can recover.
+	ProbeInterval time.Duration
+}
+
+// NewMultiRegion returns a client of the replicas of Global Tables, one per config. The first config is
+// the primary region, and the others are listed from the nearest. Reads go to the first healthy region,
+// or the best scored one with LatencyRouting, and fail over to the next on errors; writes go to the
+// primary unless WriteFallback is set.
+// Table admin, iterators, parallel and bulk operations and the other methods use the primary.
+func NewMultiRegion(sess *session.Session, configs []DynamodbConfig, policy FailoverPolicy) (Dynamodb, error) {
+	if len(configs) < 1 {
+		return nil, errors.New("no region configured")
+	}
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = DefaultFailureThreshold
+	}
+	if policy.Cooldown <= 0 {
+		policy.Cooldown = DefaultRegionCooldown
+	}
+	if policy.ShouldFailover == nil {
+		policy.ShouldFailover = shouldFailover
+	}
+	if policy.ProbeInterval <= 0 {
+		policy.ProbeInterval = DefaultProbeInterval
+	}
+
+	m := &multiRegion{policy: policy, lastProbe: time.Now()}
+	for i := range configs {
+		config := configs[i]
+		db, err := New(sess, &config)
+		if err != nil {
+			return nil, err
+		}
+		m.regions = append(m.regions, &region{db: db})
+	}
+	m.Dynamodb = m.regions[0].db
+	return m, nil
+}
+
+func shouldFailover(err error) bool {
+	return IsRetryable(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+type region struct {
+	db Dynamodb
+
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+	latency   float64
+	errorRate float64
+	lastUsed  time.Time
+}
+
+func (r *region) healthy(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return now.After(r.downUntil)
+}
+
+// score is lower for healthier regions. A region never used scores 0, so it is measured first.
+func (r *region) score() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.latency * (1 + errorPenalty*r.errorRate)
+}
+
+func (r *region) used() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastUsed
+}
+
+func (r *region) record(failed bool, elapsed time.Duration, policy FailoverPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errored := 0.0
+	if failed {
+		errored = 1
+	}
+	if r.lastUsed.IsZero() {
+		r.latency, r.errorRate = float64(elapsed), errored
+	} else {
+		r.latency += latencyDecay * (float64(elapsed) - r.latency)
+		r.errorRate += latencyDecay * (errored - r.errorRate)
+	}
+	r.lastUsed = time.Now()
+
+	if !failed {
+		r.failures = 0
+		return
+	}
+	r.failures++
+	if r.failures >= policy.FailureThreshold {
+		r.downUntil = time.Now().Add(policy.Cooldown)
+		r.failures = 0
+	}
+}
+
+type multiRegion struct {
+	Dynamodb
+	regions []*region
+	policy  FailoverPolicy
+
+	mu        sync.Mutex
+	lastProbe time.Time
+}
+
+// read runs fn on the healthy regions in order until one succeeds or fails with an error
+// not worth failing over. When every region is unhealthy, all are tried.
+func (m *multiRegion) read(fn func(db Dynamodb) error) error {
+	regions := m.candidates(m.regions)
+	if m.policy.LatencyRouting {
+		regions = m.rank(regions)
+	}
+	return m.try(regions, fn)
+}
+
+func (m *multiRegion) write(fn func(db Dynamodb) error) error {
+	if !m.policy.WriteFallback {
+		return fn(m.regions[0].db)
+	}
+	return m.try(m.candidates(m.regions), fn)
+}
+
+func (m *multiRegion) candidates(regions []*region) []*region {
+	now := time.Now()
+	healthy := make([]*region, 0, len(regions))
+	for _, r := range regions {
+		if r.healthy(now) {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) < 1 {
+		return regions
+	}
+	return healthy
+}
+
+// rank orders regions by score. Every ProbeInterval the region used least recently goes first instead.
+func (m *multiRegion) rank(regions []*region) []*region {
+	ranked := make([]*region, len(regions))
+	copy(ranked, regions)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score() < ranked[j].score() })
+
+	m.mu.Lock()
+	probe := time.Since(m.lastProbe) >= m.policy.ProbeInterval
+	if probe {
+		m.lastProbe = time.Now()
+	}
+	m.mu.Unlock()
+	if !probe {
+		return ranked
+	}
+
+	oldest := 0
+	for i, r := range ranked {
+		if r.used().Before(ranked[oldest].used()) {
+			oldest = i
+		}
+	}
+	probed := ranked[oldest]
+	copy(ranked[1:oldest+1], ranked[:oldest])
+	ranked[0] = probed
+	return ranked
+}
+
+func (m *multiRegion) try(regions []*region, fn func(db Dynamodb) error) error {
+	var err error
+	for _, r := range regions {
+		start := time.Now()
+		err = fn(r.db)
+		failover := err != nil && m.policy.ShouldFailover(err)
+		r.record(failover, time.Since(start), m.policy)
+		if !failover {
+			return err
+		}
+	}
+	return err
+}
+
+func (m *multiRegion) Get(tableName string, key DynamodbKey, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.Get(tableName, key, result) })
+}
+
+func (m *multiRegion) GetWithResponse(tableName string, key DynamodbKey, result interface{}) (res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		res, err = db.GetWithResponse(tableName, key, result)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) GetAll(tableName string, key DynamodbKey, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.GetAll(tableName, key, result) })
+}
+
+func (m *multiRegion) GetAllWithResponse(tableName string, key DynamodbKey, result interface{}) (res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		res, err = db.GetAllWithResponse(tableName, key, result)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) GetAllGrouped(tableName string, keys []DynamodbKey, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.GetAllGrouped(tableName, keys, result) })
+}
+
+func (m *multiRegion) QueryPrefix(tableName, hashValue, sortKeyPrefix string, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.QueryPrefix(tableName, hashValue, sortKeyPrefix, result) })
+}
+
+func (m *multiRegion) Exists(tableName string, key DynamodbKey) (exists bool, err error) {
+	err = m.read(func(db Dynamodb) error {
+		exists, err = db.Exists(tableName, key)
+		return err
+	})
+	return exists, err
+}
+
+func (m *multiRegion) GetOrDefault(tableName string, key DynamodbKey, defaultItem, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.GetOrDefault(tableName, key, defaultItem, result) })
+}
+
+// GetOrCreate may put the default item, so it goes where writes go.
+func (m *multiRegion) GetOrCreate(tableName string, key DynamodbKey, defaultItem, result interface{}) (created bool, err error) {
+	err = m.write(func(db Dynamodb) error {
+		created, err = db.GetOrCreate(tableName, key, defaultItem, result)
+		return err
+	})
+	return created, err
+}
+
+func (m *multiRegion) BatchGet(tableName string, keys []*DynamodbKey, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.BatchGet(tableName, keys, result) })
+}
+
+func (m *multiRegion) BatchGetWithResponse(tableName string, keys []*DynamodbKey, result interface{}) (res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		res, err = db.BatchGetWithResponse(tableName, keys, result)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) BatchGetEntities(tableName string, keys []*DynamodbKey, typeAttribute string, entities map[string]interface{}) (items EntityItems, err error) {
+	err = m.read(func(db Dynamodb) error {
+		items, err = db.BatchGetEntities(tableName, keys, typeAttribute, entities)
+		return err
+	})
+	return items, err
+}
+
+func (m *multiRegion) MultiTableBatchGet(ops map[string]BatchGetSpec) (res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		res, err = db.MultiTableBatchGet(ops)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) Count(tableName string, key DynamodbKey) (count int64, err error) {
+	err = m.read(func(db Dynamodb) error {
+		count, err = db.Count(tableName, key)
+		return err
+	})
+	return count, err
+}
+
+func (m *multiRegion) CountWithResponse(tableName string, key DynamodbKey) (count int64, res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		count, res, err = db.CountWithResponse(tableName, key)
+		return err
+	})
+	return count, res, err
+}
+
+func (m *multiRegion) CountWithOptions(tableName string, key DynamodbKey, opts CountOptions) (count CountResult, res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		count, res, err = db.CountWithOptions(tableName, key, opts)
+		return err
+	})
+	return count, res, err
+}
+
+func (m *multiRegion) Paging(tableName string, key DynamodbKey, paged DynamodbPaged, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.Paging(tableName, key, paged, result) })
+}
+
+func (m *multiRegion) PagingWithResponse(tableName string, key DynamodbKey, paged DynamodbPaged, result interface{}) (res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		res, err = db.PagingWithResponse(tableName, key, paged, result)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) Scan(tableName string, result interface{}, filters ...ScanFilter) error {
+	return m.read(func(db Dynamodb) error { return db.Scan(tableName, result, filters...) })
+}
+
+func (m *multiRegion) ScanWithResponse(tableName string, result interface{}, filters ...ScanFilter) (res *DynamodbReadResponse, err error) {
+	err = m.read(func(db Dynamodb) error {
+		res, err = db.ScanWithResponse(tableName, result, filters...)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) ScanSparseIndex(tableName, indexName string, result interface{}) error {
+	return m.read(func(db Dynamodb) error { return db.ScanSparseIndex(tableName, indexName, result) })
+}
+
+func (m *multiRegion) ScanPage(ctx context.Context, tableName string, result interface{}, opts ScanPageOptions, filters ...ScanFilter) (cursor string, err error) {
+	err = m.read(func(db Dynamodb) error {
+		cursor, err = db.ScanPage(ctx, tableName, result, opts, filters...)
+		return err
+	})
+	return cursor, err
+}
+
+func (m *multiRegion) GetAllEach(ctx context.Context, tableName string, key DynamodbKey, out interface{}, fn func() error) error {
+	return m.readEach(fn, func(db Dynamodb, fn func() error) error {
+		return db.GetAllEach(ctx, tableName, key, out, fn)
+	})
+}
+
+func (m *multiRegion) ScanEach(ctx context.Context, tableName string, out interface{}, fn func() error, filters ...ScanFilter) error {
+	return m.readEach(fn, func(db Dynamodb, fn func() error) error {
+		return db.ScanEach(ctx, tableName, out, fn, filters...)
+	})
+}
+
+// readEach is read for the streaming reads calling fn per item. It fails over only until fn is first
+// called, so fn never sees an item twice; later errors are returned as they are.
+func (m *multiRegion) readEach(fn func() error, each func(db Dynamodb, fn func() error) error) error {
+	called := false
+	var streamErr error
+	err := m.read(func(db Dynamodb) error {
+		err := each(db, func() error {
+			called = true
+			return fn()
+		})
+		if called {
+			streamErr = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return streamErr
+}
+
+func (m *multiRegion) Put(tableName string, item interface{}) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.Put(tableName, item)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) PutIdempotent(tableName string, item interface{}, token string, ttl time.Duration) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.PutIdempotent(tableName, item, token, ttl)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) PutWithOldValue(tableName string, item interface{}, old interface{}) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.PutWithOldValue(tableName, item, old)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) PutIfNotExists(tableName string, item interface{}) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.PutIfNotExists(tableName, item)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) UpdateOnly(tableName string, item interface{}) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.UpdateOnly(tableName, item)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) PutWithMode(tableName string, item interface{}, mode WriteMode) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.PutWithMode(tableName, item, mode)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) PutIf(tableName string, item interface{}, conditions ...ScanFilter) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.PutIf(tableName, item, conditions...)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) Delete(tableName string, key DynamodbKey) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.Delete(tableName, key)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) DeleteWithOldValue(tableName string, key DynamodbKey, old interface{}) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.DeleteWithOldValue(tableName, key, old)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) DeleteIf(tableName string, key DynamodbKey, conditions ...ScanFilter) (res *DynamodbResponse, err error) {
+	err = m.write(func(db Dynamodb) error {
+		res, err = db.DeleteIf(tableName, key, conditions...)
+		return err
+	})
+	return res, err
+}
+
+func (m *multiRegion) SetSparse(tableName string, key DynamodbKey, attribute string, value interface{}) error {
+	return m.write(func(db Dynamodb) error { return db.SetSparse(tableName, key, attribute, value) })
+}
+
+func (m *multiRegion) ClearSparse(tableName string, key DynamodbKey, attribute string) error {
+	return m.write(func(db Dynamodb) error { return db.ClearSparse(tableName, key, attribute) })
+}
+
+func (m *multiRegion) TransactWrite(ctx context.Context, ops []TransactOp) error {
+	return m.write(func(db Dynamodb) error { return db.TransactWrite(ctx, ops) })
+}
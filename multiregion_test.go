@@ -0,0 +1,119 @@
+package dynamodb
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMultiRegion(t *testing.T) {
+	configs := []DynamodbConfig{
+		{Endpoint: "http://localhost:1", Region: "us-east-1", RetryPolicy: &RetryPolicy{MaxAttempts: 1}},
+		{Endpoint: "http://localhost:8000", Region: "us-west-2"},
+	}
+
+	item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+	}
+
+	t.Run("Failure: writes stay on the primary", func(t *testing.T) {
+		db, err := NewMultiRegion(session.New(), configs, FailoverPolicy{})
+		assert.NoError(t, err)
+
+		_, err = db.Put(tableNameHashOnly, item)
+		assert.Error(t, err)
+	})
+
+	t.Run("Success: fallback", func(t *testing.T) {
+		db, err := NewMultiRegion(session.New(), configs, FailoverPolicy{WriteFallback: true, FailureThreshold: 1})
+		assert.NoError(t, err)
+
+		_, err = db.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		var datum HashOnly
+		assert.NoError(t, db.Get(tableNameHashOnly, key, &datum))
+		assert.Equal(t, item, datum)
+	})
+
+	t.Run("Failure: no region", func(t *testing.T) {
+		_, err := NewMultiRegion(session.New(), nil, FailoverPolicy{})
+		assert.Error(t, err)
+	})
+}
+
+func TestLatencyRouting(t *testing.T) {
+	policy := FailoverPolicy{LatencyRouting: true, FailureThreshold: 100, ProbeInterval: time.Hour}
+	slow, fast, failing := &region{}, &region{}, &region{}
+	m := &multiRegion{regions: []*region{slow, fast, failing}, policy: policy, lastProbe: time.Now()}
+
+	slow.record(false, 100*time.Millisecond, policy)
+	fast.record(false, 10*time.Millisecond, policy)
+	failing.record(true, 5*time.Millisecond, policy)
+
+	t.Run("Success: ranked by score", func(t *testing.T) {
+		assert.Equal(t, []*region{fast, failing, slow}, m.rank(m.regions))
+
+		for i := 0; i < 5; i++ {
+			slow.record(false, 10*time.Millisecond, policy)
+		}
+		assert.Equal(t, []*region{fast, slow, failing}, m.rank(m.regions))
+	})
+
+	t.Run("Success: probe", func(t *testing.T) {
+		m.lastProbe = time.Now().Add(-policy.ProbeInterval)
+		slow.record(false, 100*time.Millisecond, policy)
+		fast.record(false, 10*time.Millisecond, policy)
+
+		assert.Equal(t, []*region{failing, fast, slow}, m.rank(m.regions))
+		assert.Equal(t, []*region{fast, slow, failing}, m.rank(m.regions))
+	})
+}
+
+// primaryOnly are the methods of Dynamodb a multiRegion leaves to the primary: table admin, reads with
+// their own cursors or workers, and writes that cannot be repeated in another region once started.
+var primaryOnly = map[string]bool{
+	"ApplyTableSpec": true, "BestEffortGroup": true, "BulkPut": true, "Clock": true, "CopyTable": true,
+	"CreateTable": true, "CreateTableFromSchema": true, "CreateTableWithContext": true,
+	"CreateTableWithLocalSecondaryIndex": true, "CreateTableWithOptions": true, "DecodeCursor": true,
+	"DeleteAllByHash": true, "DeleteTable": true, "DeleteTableIfExists": true, "DeleteTableWithContext": true,
+	"DescribeTTL": true, "DescribeTable": true, "DescribeTableSpec": true, "EnableTTL": true, "EncodeCursor": true,
+	"ExistsTable": true, "ExpiresIn": true, "ExportTable": true, "ExportToS3": true, "GetAllStream": true,
+	"ImportTable": true, "LastRequest": true, "ListTables": true, "ListTablesWithPrefix": true,
+	"OnTableEvent": true, "Ping": true, "Query": true, "QueryIter": true, "ReadAsOf": true,
+	"RenameAttribute": true, "ScanAnalyze": true, "ScanIter": true, "ScanParallel": true,
+	"ScanParallelWithOptions": true, "Subscribe": true, "SubscribeWithOptions": true, "SwitchToOnDemand": true,
+	"SwitchToProvisioned": true, "TableExists": true, "TransactWriteSplit": true, "UpdateTableAddGSI": true,
+	"UpdateTableDeleteGSI": true, "UpdateTableThroughput": true, "Use": true, "ValidateSchema": true,
+	"ValidateTableSpec": true, "WaitUntilIndexActive": true, "WaitUntilIndexDeleted": true,
+	"WaitUntilTableActive": true, "WaitUntilTableDeleted": true, "WithTableAsOf": true,
+}
+
+// TestMultiRegionMethods fails when a method added to Dynamodb reaches the primary through the
+// embedded client without failover. Override it in multiregion.go, or add it to primaryOnly.
+func TestMultiRegionMethods(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "multiregion.go", nil, 0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	overridden := map[string]bool{}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			overridden[fn.Name.Name] = true
+		}
+	}
+
+	methods := reflect.TypeOf((*Dynamodb)(nil)).Elem()
+	for i := 0; i < methods.NumMethod(); i++ {
+		name := methods.Method(i).Name
+		assert.True(t, overridden[name] != primaryOnly[name], "%s: override it or list it in primaryOnly", name)
+	}
+}
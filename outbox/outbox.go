@@ -0,0 +1,246 @@
+// Package outbox publishes events reliably with the transactional outbox pattern: an event is
+// written in the same transaction as the items it describes, then delivered by a relay.
+//
+//	box := outbox.New(db, "outbox")
+//	err := box.TransactWithOutbox(ctx, []dynamodb.TransactOp{
+//		{Table: "orders", Put: order},
+//	}, outbox.Event{Topic: "order.created", Payload: order})
+//
+//	relay := box.Relay(func(ctx context.Context, record *outbox.Record) error {
+//		return bus.Publish(ctx, record.Topic, record.Payload)
+//	}, outbox.RelayOptions{})
+//	go relay.Run(ctx)
+//
+// Events are published at least once, in enqueue order on a best-effort basis: a relay crashing
+// between publishing and marking a record publishes it again, so subscribers must tolerate duplicates.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/linksports/dynamodb"
+)
+
+// DefaultPollInterval is how often a relay polls when RelayOptions.PollInterval is unset.
+const DefaultPollInterval = time.Second
+
+// DefaultRetention is how long published records are kept when RelayOptions.Retention is unset.
+const DefaultRetention = 24 * time.Hour
+
+// DefaultBatchSize is the number of records published per poll when RelayOptions.BatchSize is unset.
+const DefaultBatchSize = 100
+
+// Record statuses
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+)
+
+// PendingIndex is the sparse index of the pending records, keyed by Pending and sorted by ID.
+const PendingIndex = "Pending-index"
+
+// pending is the value of Record.Pending while a record is pending. All pending records share it,
+// so that one query of PendingIndex returns them oldest first.
+const pending = 1
+
+// Event :
+type Event struct {
+	Topic string
+	// Payload is stored JSON encoded.
+	Payload interface{}
+}
+
+// Record is an item of the outbox table.
+type Record struct {
+	// ID is a ULID, which sorts by enqueue time.
+	ID     string `dynamo:"ID,hash"`
+	Topic  string `dynamo:"Topic"`
+	Status string `dynamo:"Status"`
+	// Payload is the JSON encoded payload of the event.
+	Payload string `dynamo:"Payload"`
+	// CreatedAt in Unix milliseconds.
+	CreatedAt int64 `dynamo:"CreatedAt"`
+	// ExpiresAt in Unix seconds, set once published.
+	ExpiresAt int64 `dynamo:"ExpiresAt,omitempty"`
+	// Pending is set until the record is published, which takes it out of PendingIndex.
+	Pending int `dynamo:"Pending,sparse"`
+}
+
+// Decode unmarshals the payload into v.
+func (r *Record) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(r.Payload), v)
+}
+
+// Outbox :
+type Outbox struct {
+	db    dynamodb.Dynamodb
+	table string
+	ids   dynamodb.ULIDGenerator
+}
+
+// New :
+func New(db dynamodb.Dynamodb, table string) *Outbox {
+	return &Outbox{db: db, table: table, ids: dynamodb.ULIDGenerator{Clock: db.Clock()}}
+}
+
+// CreateTable creates the table unless it exists, expiring the published records with TTL,
+// and adds PendingIndex to a table created without it.
+func (o *Outbox) CreateTable(ctx context.Context) error {
+	return o.db.ApplyTableSpec(ctx, dynamodb.TableSpec{
+		Name:         o.table,
+		HashKey:      "ID",
+		OnDemand:     true,
+		TTLAttribute: "ExpiresAt",
+		GSIs: []dynamodb.IndexDefinition{{
+			Name:         PendingIndex,
+			HashKey:      "Pending",
+			HashKeyType:  dynamodb.DynamodbKeyTypeNumber,
+			RangeKey:     "ID",
+			RangeKeyType: dynamodb.DynamodbKeyTypeString,
+			Projection:   dynamodb.DynamodbProjectionAll,
+		}},
+	})
+}
+
+// TransactWithOutbox applies writes and records event in one transaction, so the event is published
+// if and only if the writes commit. writes may hold up to dynamodb.MaxTransactItems-1 operations.
+func (o *Outbox) TransactWithOutbox(ctx context.Context, writes []dynamodb.TransactOp, event Event) error {
+	if len(event.Topic) < 1 {
+		return errors.New("topic empty")
+	}
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+	id, err := o.ids.NewID()
+	if err != nil {
+		return err
+	}
+
+	ops := make([]dynamodb.TransactOp, 0, len(writes)+1)
+	ops = append(ops, writes...)
+	ops = append(ops, dynamodb.TransactOp{
+		Table: o.table,
+		Put: Record{
+			ID:        id,
+			Topic:     event.Topic,
+			Status:    StatusPending,
+			Payload:   string(payload),
+			CreatedAt: o.db.Clock().Now().UnixNano() / int64(time.Millisecond),
+			Pending:   pending,
+		},
+	})
+	return o.db.TransactWrite(ctx, ops)
+}
+
+// PublishFunc delivers a record to subscribers. A record whose publish fails stays pending and is
+// retried on the next poll.
+type PublishFunc func(ctx context.Context, record *Record) error
+
+// RelayOptions :
+type RelayOptions struct {
+	// PollInterval defaults to DefaultPollInterval.
+	PollInterval time.Duration
+	// Retention is how long published records are kept before TTL deletes them. Defaults to DefaultRetention.
+	Retention time.Duration
+	// BatchSize defaults to DefaultBatchSize.
+	BatchSize int
+	// OnError receives the errors of the polls of Run. Defaults to the standard logger.
+	OnError func(err error)
+}
+
+// Relay publishes the pending records of an outbox.
+type Relay struct {
+	outbox  *Outbox
+	publish PublishFunc
+	opts    RelayOptions
+}
+
+// Relay returns a relay publishing the records of o with publish.
+func (o *Outbox) Relay(publish PublishFunc, opts RelayOptions) *Relay {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) {
+			log.Print("outbox relay: ", err)
+		}
+	}
+	return &Relay{outbox: o, publish: publish, opts: opts}
+}
+
+// Run polls until ctx is done, which it returns. Errors of single polls are passed to
+// RelayOptions.OnError and retried on the next poll.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Poll(ctx); err != nil && ctx.Err() == nil {
+			r.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll publishes up to BatchSize pending records, oldest first, and returns the number published.
+// It stops at the first record that fails to publish, so later records do not overtake it.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	pending, err := r.pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for i := range pending {
+		record := &pending[i]
+		if err := r.publish(ctx, record); err != nil {
+			return published, fmt.Errorf("publish %s: %w", record.ID, err)
+		}
+
+		record.Status, record.Pending = StatusPublished, 0
+		record.ExpiresAt = r.outbox.db.ExpiresIn(r.opts.Retention)
+		_, err := r.outbox.db.PutIf(r.outbox.table, *record, dynamodb.FilterAttr("Status", dynamodb.DynamodbEqual, StatusPending))
+		if err != nil && !errors.Is(err, dynamodb.ErrConditionFailed) {
+			return published, fmt.Errorf("mark %s: %w", record.ID, err)
+		}
+		published++
+	}
+	return published, nil
+}
+
+// pending returns the oldest pending records from PendingIndex, which holds nothing else.
+// The index is eventually consistent, so a record just published may be returned, and published,
+// once more.
+func (r *Relay) pending(ctx context.Context) ([]Record, error) {
+	records := make([]Record, 0, r.opts.BatchSize)
+	iter := r.outbox.db.Query(r.outbox.table).
+		Index(PendingIndex).
+		Hash("Pending", pending).
+		Limit(r.opts.BatchSize).
+		Iter()
+	var record Record
+	for iter.Next(ctx, &record) {
+		records = append(records, record)
+		record = Record{}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
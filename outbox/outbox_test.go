@@ -0,0 +1,174 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDynamo(t *testing.T) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func newDynamoWithClock(t *testing.T, clock dynamodb.Clock) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+		Clock:    clock,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+type order struct {
+	ID    string `dynamo:"ID,hash"`
+	Total int    `dynamo:"Total"`
+}
+
+func TestOutbox(t *testing.T) {
+	db := newDynamo(t)
+	ctx := context.Background()
+
+	table := "outbox-" + faker.UUIDDigit()
+	orders := "orders-" + faker.UUIDDigit()
+	defer db.DeleteTableWithContext(ctx, table)
+	defer db.DeleteTableWithContext(ctx, orders)
+	box := New(db, table)
+	if !assert.NoError(t, box.CreateTable(ctx)) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, db.ApplyTableSpec(ctx, dynamodb.TableSpec{Name: orders, HashKey: "ID", OnDemand: true})) {
+		t.FailNow()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		for i := 1; i <= 2; i++ {
+			o := order{ID: faker.UUIDDigit(), Total: i}
+			assert.NoError(t, box.TransactWithOutbox(ctx, []dynamodb.TransactOp{{Table: orders, Put: o}}, Event{Topic: "order.created", Payload: o}))
+		}
+
+		var published []order
+		relay := box.Relay(func(ctx context.Context, record *Record) error {
+			var o order
+			if err := record.Decode(&o); err != nil {
+				return err
+			}
+			published = append(published, o)
+			return nil
+		}, RelayOptions{})
+
+		n, err := relay.Poll(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		if assert.Len(t, published, 2) {
+			assert.Equal(t, 1, published[0].Total)
+			assert.Equal(t, 2, published[1].Total)
+		}
+
+		n, err = relay.Poll(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("Failure: publish failed", func(t *testing.T) {
+		o := order{ID: faker.UUIDDigit(), Total: 3}
+		assert.NoError(t, box.TransactWithOutbox(ctx, []dynamodb.TransactOp{{Table: orders, Put: o}}, Event{Topic: "order.created", Payload: o}))
+
+		failure := errors.New("broker down")
+		_, err := box.Relay(func(ctx context.Context, record *Record) error { return failure }, RelayOptions{}).Poll(ctx)
+		assert.True(t, errors.Is(err, failure))
+
+		n, err := box.Relay(func(ctx context.Context, record *Record) error { return nil }, RelayOptions{}).Poll(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("Failure: transaction canceled", func(t *testing.T) {
+		o := order{ID: faker.UUIDDigit()}
+		err := box.TransactWithOutbox(ctx, []dynamodb.TransactOp{{
+			Table:      orders,
+			Put:        o,
+			Conditions: []dynamodb.ScanFilter{dynamodb.FilterExists("ID")},
+		}}, Event{Topic: "order.created", Payload: o})
+		assert.Error(t, err)
+
+		n, err := box.Relay(func(ctx context.Context, record *Record) error { return nil }, RelayOptions{}).Poll(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("Success: clock", func(t *testing.T) {
+		clock := dynamodb.NewManualClock(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+		box := New(newDynamoWithClock(t, clock), table)
+		o := order{ID: faker.UUIDDigit()}
+		assert.NoError(t, box.TransactWithOutbox(ctx, []dynamodb.TransactOp{{Table: orders, Put: o}}, Event{Topic: "order.clocked", Payload: o}))
+
+		var published Record
+		n, err := box.Relay(func(ctx context.Context, record *Record) error {
+			published = *record
+			return nil
+		}, RelayOptions{Retention: time.Hour}).Poll(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, clock.Now().UnixNano()/int64(time.Millisecond), published.CreatedAt)
+
+		var stored Record
+		assert.NoError(t, db.Get(table, dynamodb.DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", published.ID },
+		}, &stored))
+		assert.Equal(t, clock.Now().Add(time.Hour).Unix(), stored.ExpiresAt)
+	})
+
+	t.Run("Failure: Run reports poll errors", func(t *testing.T) {
+		o := order{ID: faker.UUIDDigit()}
+		assert.NoError(t, box.TransactWithOutbox(ctx, []dynamodb.TransactOp{{Table: orders, Put: o}}, Event{Topic: "order.created", Payload: o}))
+
+		failure := errors.New("broker down")
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var reported error
+		relay := box.Relay(func(ctx context.Context, record *Record) error { return failure }, RelayOptions{
+			PollInterval: time.Millisecond,
+			OnError: func(err error) {
+				reported = err
+				cancel()
+			},
+		})
+		assert.True(t, errors.Is(relay.Run(ctx), context.Canceled))
+		assert.True(t, errors.Is(reported, failure))
+
+		n, err := box.Relay(func(ctx context.Context, record *Record) error { return nil }, RelayOptions{}).Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("Success: PendingIndex added to an existing table", func(t *testing.T) {
+		table := "outbox-" + faker.UUIDDigit()
+		defer db.DeleteTableWithContext(ctx, table)
+		if !assert.NoError(t, db.ApplyTableSpec(ctx, dynamodb.TableSpec{Name: table, HashKey: "ID", OnDemand: true})) {
+			t.FailNow()
+		}
+
+		assert.NoError(t, New(db, table).CreateTable(ctx))
+		spec, err := db.DescribeTableSpec(ctx, table)
+		assert.NoError(t, err)
+		if assert.Len(t, spec.GSIs, 1) {
+			assert.Equal(t, PendingIndex, spec.GSIs[0].Name)
+		}
+	})
+}
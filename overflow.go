@@ -0,0 +1,221 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultLargeItemThreshold is the item size above which attributes overflow to the LargeItemStore,
+// leaving room under the 400KB limit of DynamoDB for the pointers.
+const DefaultLargeItemThreshold = 350 * 1024
+
+// largeItemRef is the only attribute of the map replacing an attribute moved to the LargeItemStore.
+const largeItemRef = "_LargeItemRef"
+
+// LargeItemStore holds the attributes of items over DynamodbConfig.LargeItemThreshold. Every put
+// moves the largest attributes other than the keys of the table and its indexes to the store until
+// the item fits, and every read loads them back, so callers see whole items.
+//
+// Stored attributes cannot be used in conditions, filters or updates. Objects of overwritten or
+// deleted items are not removed, leave that to an expiration rule of the store.
+type LargeItemStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// S3LargeItemStore stores attributes as objects of an S3 bucket.
+type S3LargeItemStore struct {
+	client s3iface.S3API
+	bucket string
+	prefix string
+}
+
+// NewS3LargeItemStore prepends prefix, such as "dynamodb/", to the object keys.
+func NewS3LargeItemStore(client s3iface.S3API, bucket, prefix string) *S3LargeItemStore {
+	return &S3LargeItemStore{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Put :
+func (s *S3LargeItemStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+// Get :
+func (s *S3LargeItemStore) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return ioutil.ReadAll(out.Body)
+}
+
+// offload returns item, or a copy with its largest attributes moved to the LargeItemStore when it
+// is over the threshold. table is the name sent to DynamoDB.
+func (con *dynamodb) offload(ctx context.Context, table string, item map[string]*awsDynamodb.AttributeValue) (map[string]*awsDynamodb.AttributeValue, error) {
+	store := con.config.LargeItemStore
+	threshold := int64(con.config.LargeItemThreshold)
+	if threshold <= 0 {
+		threshold = DefaultLargeItemThreshold
+	}
+	if store == nil || itemSize(item) <= threshold {
+		return item, nil
+	}
+
+	desc, err := con.describeCached(strings.TrimPrefix(table, con.config.TablePrefix))
+	if err != nil {
+		return nil, err
+	}
+	// Key attributes of the table and its indexes must keep their values to be written and indexed.
+	keys := map[string]bool{desc.HashKey: true, desc.RangeKey: true}
+	for _, index := range append(append([]IndexDefinition{}, desc.GSIs...), desc.LSIs...) {
+		keys[index.HashKey], keys[index.RangeKey] = true, true
+	}
+	names := make([]string, 0, len(item))
+	for name := range item {
+		if !keys[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return attributeSize(item[names[i]]) > attributeSize(item[names[j]])
+	})
+
+	copied := make(map[string]*awsDynamodb.AttributeValue, len(item))
+	for name, av := range item {
+		copied[name] = av
+	}
+	for _, name := range names {
+		if itemSize(copied) <= threshold {
+			break
+		}
+
+		data, err := json.Marshal(copied[name])
+		if err != nil {
+			return nil, err
+		}
+		id, err := UUIDGenerator{}.NewID()
+		if err != nil {
+			return nil, err
+		}
+		key := table + "/" + id
+		if err := store.Put(ctx, key, data); err != nil {
+			return nil, fmt.Errorf("offload %s: %w", name, err)
+		}
+		copied[name] = &awsDynamodb.AttributeValue{M: map[string]*awsDynamodb.AttributeValue{
+			largeItemRef: {S: aws.String(key)},
+		}}
+	}
+	return copied, nil
+}
+
+// rehydrate loads the attributes of item moved to the LargeItemStore.
+func (con *dynamodb) rehydrate(ctx context.Context, item map[string]*awsDynamodb.AttributeValue) error {
+	store := con.config.LargeItemStore
+	if store == nil {
+		return nil
+	}
+
+	for name, av := range item {
+		if av == nil || len(av.M) != 1 || av.M[largeItemRef] == nil || av.M[largeItemRef].S == nil {
+			continue
+		}
+
+		data, err := store.Get(ctx, *av.M[largeItemRef].S)
+		if err != nil {
+			return fmt.Errorf("rehydrate %s: %w", name, err)
+		}
+		var value awsDynamodb.AttributeValue
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("rehydrate %s: %w", name, err)
+		}
+		item[name] = &value
+	}
+	return nil
+}
+
+func (con *dynamodb) rehydrateAll(ctx context.Context, items []map[string]*awsDynamodb.AttributeValue) error {
+	for _, item := range items {
+		if err := con.rehydrate(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (con *dynamodb) offloadPut(ctx context.Context, in *awsDynamodb.PutItemInput) (*awsDynamodb.PutItemInput, error) {
+	if con.config.LargeItemStore == nil {
+		return in, nil
+	}
+
+	item, err := con.offload(ctx, aws.StringValue(in.TableName), in.Item)
+	if err != nil {
+		return nil, err
+	}
+	put := *in
+	put.Item = item
+	return &put, nil
+}
+
+func (con *dynamodb) offloadBatch(ctx context.Context, in *awsDynamodb.BatchWriteItemInput) (*awsDynamodb.BatchWriteItemInput, error) {
+	if con.config.LargeItemStore == nil {
+		return in, nil
+	}
+
+	batch := *in
+	batch.RequestItems = make(map[string][]*awsDynamodb.WriteRequest, len(in.RequestItems))
+	for table, requests := range in.RequestItems {
+		batch.RequestItems[table] = make([]*awsDynamodb.WriteRequest, len(requests))
+		for i, req := range requests {
+			if req.PutRequest != nil {
+				item, err := con.offload(ctx, table, req.PutRequest.Item)
+				if err != nil {
+					return nil, err
+				}
+				req = &awsDynamodb.WriteRequest{PutRequest: &awsDynamodb.PutRequest{Item: item}}
+			}
+			batch.RequestItems[table][i] = req
+		}
+	}
+	return &batch, nil
+}
+
+func (con *dynamodb) offloadTransact(ctx context.Context, in *awsDynamodb.TransactWriteItemsInput) (*awsDynamodb.TransactWriteItemsInput, error) {
+	if con.config.LargeItemStore == nil {
+		return in, nil
+	}
+
+	transact := *in
+	transact.TransactItems = make([]*awsDynamodb.TransactWriteItem, len(in.TransactItems))
+	for i, op := range in.TransactItems {
+		if op.Put != nil {
+			item, err := con.offload(ctx, aws.StringValue(op.Put.TableName), op.Put.Item)
+			if err != nil {
+				return nil, err
+			}
+			put := *op.Put
+			put.Item = item
+			op = &awsDynamodb.TransactWriteItem{Put: &put}
+		}
+		transact.TransactItems[i] = op
+	}
+	return &transact, nil
+}
@@ -0,0 +1,109 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryItemStore struct {
+	objects sync.Map
+}
+
+func (s *memoryItemStore) Put(ctx context.Context, key string, data []byte) error {
+	s.objects.Store(key, data)
+	return nil
+}
+
+func (s *memoryItemStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := s.objects.Load(key)
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data.([]byte), nil
+}
+
+func TestLargeItemStore(t *testing.T) {
+	store := &memoryItemStore{}
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{LargeItemStore: store, LargeItemThreshold: 1024})
+
+	t.Run("Success: offloaded", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit(), Name: strings.Repeat("a", 2048), Status: 1}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+		key := DynamodbKey{
+			Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+		}
+
+		var raw map[string]interface{}
+		assert.NoError(t, newDynamo(t).Get(tableNameHashOnly, key, &raw))
+		assert.Contains(t, raw["Name"], largeItemRef)
+
+		var got HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, key, &got))
+		assert.Equal(t, item.Name, got.Name)
+
+		var all []HashOnly
+		assert.NoError(t, dynamo.Scan(tableNameHashOnly, &all, FilterAttr("ID", DynamodbEqual, item.Id)))
+		if assert.Len(t, all, 1) {
+			assert.Equal(t, item.Name, all[0].Name)
+		}
+	})
+
+	t.Run("Success: old values rehydrated", func(t *testing.T) {
+		first := HashOnly{Id: faker.UUIDDigit(), Name: strings.Repeat("a", 2048)}
+		second := HashOnly{Id: first.Id, Name: strings.Repeat("b", 2048)}
+		_, err := dynamo.Put(tableNameHashOnly, first)
+		assert.NoError(t, err)
+
+		var old HashOnly
+		_, err = dynamo.PutWithOldValue(tableNameHashOnly, second, &old)
+		assert.NoError(t, err)
+		assert.Equal(t, first.Name, old.Name)
+
+		old = HashOnly{}
+		_, err = dynamo.DeleteWithOldValue(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return first.HashKey(), first.Id },
+		}, &old)
+		assert.NoError(t, err)
+		assert.Equal(t, second.Name, old.Name)
+	})
+
+	t.Run("Success: index keys stay in the table", func(t *testing.T) {
+		ctx := context.Background()
+		name := "overflow-" + faker.UUIDDigit()
+		assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, WithIndex{}, CreateTableOptions{
+			OnDemand: true,
+			GSIs:     []IndexDefinition{{Name: "Name-index", HashKey: "Name"}},
+			Wait:     true,
+		}))
+		defer dynamo.DeleteTableWithContext(ctx, name)
+
+		item := WithIndex{Id: faker.UUIDDigit(), CreatedAt: faker.Timestamp(), Name: strings.Repeat("a", 2000)}
+		_, err := dynamo.Put(name, item)
+		assert.NoError(t, err)
+
+		var raw []map[string]interface{}
+		assert.NoError(t, dynamo.Query(name).Index("Name-index").Hash("Name", item.Name).All(&raw))
+		if assert.Len(t, raw, 1) {
+			assert.Equal(t, item.Name, raw[0]["Name"])
+		}
+	})
+
+	t.Run("Success: small items stay in the table", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		var raw map[string]interface{}
+		assert.NoError(t, newDynamo(t).Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+		}, &raw))
+		assert.Equal(t, item.Name, raw["Name"])
+	})
+}
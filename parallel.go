@@ -0,0 +1,144 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/guregu/dynamo"
+)
+
+// ScanParallel is only for script like Scan. Do not use from application.
+// The table is split into segments scanned concurrently, and the results are
+// appended to result, which must be a pointer to a slice.
+func (con *dynamodb) ScanParallel(tableName string, segments int, result interface{}, filters ...ScanFilter) error {
+	if segments < 1 {
+		return errors.New("segments must be positive")
+	}
+	return con.ScanParallelWithOptions(tableName, result, ParallelScanOptions{Workers: segments, Segments: segments}, filters...)
+}
+
+// DefaultSegmentsPerWorker is the number of segments per worker when ParallelScanOptions.Segments is unset.
+const DefaultSegmentsPerWorker = 8
+
+// ParallelScanOptions :
+type ParallelScanOptions struct {
+	Workers int
+	// Segments is the number of segments the table is split into, which defaults to
+	// DefaultSegmentsPerWorker per worker. Workers take the next unscanned segment whenever they
+	// finish one, so many more segments than workers keep fast workers busy while a large segment is
+	// still being read.
+	//
+	// This is over-segmentation, not work stealing: DynamoDB fixes the segments of a scan up front
+	// and the pages of a segment must be read in order, so the rest of a slow segment cannot be split.
+	Segments int
+}
+
+// ScanParallelWithOptions is ScanParallel with more segments than workers, each worker scanning
+// the next unscanned segment when it finishes one. The results are appended in segment order.
+func (con *dynamodb) ScanParallelWithOptions(tableName string, result interface{}, opts ParallelScanOptions, filters ...ScanFilter) error {
+	workers := opts.Workers
+	if workers < 1 {
+		return errors.New("workers must be positive")
+	}
+	segments := opts.Segments
+	if segments < 1 {
+		segments = workers * DefaultSegmentsPerWorker
+	}
+	if workers > segments {
+		workers = segments
+	}
+
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("result must be a pointer to a slice")
+	}
+	defer con.auditResult("ScanParallel", result)()
+
+	// Coded fields are decoded once the segments are merged.
+	partType := rv.Elem().Type()
+	coded := hasCodedFields(result)
+	if coded {
+		partType = reflect.TypeOf([]map[string]*awsDynamodb.AttributeValue{})
+	}
+
+	ctx, cancel := con.scanContext(context.Background())
+	defer cancel()
+
+	parts := make([]reflect.Value, segments)
+	next := make(chan int, segments)
+	for i := 0; i < segments; i++ {
+		next <- i
+	}
+	close(next)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				if ctx.Err() != nil {
+					return
+				}
+
+				part := reflect.New(partType)
+				db := dynamo.NewFromIface(&segmentClient{con.db.Client(), int64(i), int64(segments)})
+				if err := scan(db.Table(con.tableName(tableName)), filters...).AllWithContext(ctx, part.Interface()); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+				parts[i] = part.Elem()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return scanError(ctx, firstErr)
+	}
+
+	if coded {
+		for _, part := range parts {
+			if err := con.decodeItems(tableName, part.Interface().([]map[string]*awsDynamodb.AttributeValue), result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	merged := rv.Elem()
+	for _, part := range parts {
+		merged = reflect.AppendSlice(merged, part)
+	}
+	rv.Elem().Set(merged)
+
+	return nil
+}
+
+// segmentClient restricts every scan issued through it to one segment of the table.
+type segmentClient struct {
+	dynamodbiface.DynamoDBAPI
+	segment       int64
+	totalSegments int64
+}
+
+func (c *segmentClient) ScanWithContext(ctx aws.Context, input *awsDynamodb.ScanInput, opts ...request.Option) (*awsDynamodb.ScanOutput, error) {
+	in := *input
+	in.Segment = aws.Int64(c.segment)
+	in.TotalSegments = aws.Int64(c.totalSegments)
+	return c.DynamoDBAPI.ScanWithContext(ctx, &in, opts...)
+}
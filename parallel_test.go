@@ -0,0 +1,56 @@
+package dynamodb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanParallel(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := faker.UUIDDigit()
+	n := rand.Intn(20) + 1
+	for i := 0; n > i; i++ {
+		var d HashOnly
+		faker.FakeData(&d)
+		d.Name = name
+		dynamo.Put(tableNameHashOnly, &d)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var items []HashOnly
+		err := dynamo.ScanParallel(tableNameHashOnly, 4, &items, ScanFilter{Expr: "'Name' = ?", Value: name})
+
+		assert.NoError(t, err)
+		assert.Len(t, items, n)
+	})
+
+	t.Run("Success: over-segmented", func(t *testing.T) {
+		var items []HashOnly
+		err := dynamo.ScanParallelWithOptions(tableNameHashOnly, &items, ParallelScanOptions{Workers: 3},
+			ScanFilter{Expr: "'Name' = ?", Value: name})
+
+		assert.NoError(t, err)
+		assert.Len(t, items, n)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("segments zero", func(t *testing.T) {
+			var items []HashOnly
+			assert.Error(t, dynamo.ScanParallel(tableNameHashOnly, 0, &items))
+		})
+
+		t.Run("workers zero", func(t *testing.T) {
+			var items []HashOnly
+			assert.Error(t, dynamo.ScanParallelWithOptions(tableNameHashOnly, &items, ParallelScanOptions{}))
+		})
+
+		t.Run("result not slice", func(t *testing.T) {
+			var item HashOnly
+			assert.Error(t, dynamo.ScanParallel(tableNameHashOnly, 2, &item))
+		})
+	})
+}
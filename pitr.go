@@ -0,0 +1,53 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// restoreWaitAttempts polls a restored table every 20 seconds for up to 4 hours.
+const restoreWaitAttempts = 720
+
+// WithTableAsOf restores tableName as it was at the given time into a temporary table,
+// calls fn with its name and deletes it afterwards. Point-in-time recovery must be enabled.
+// Restores take minutes to hours, which makes this a tool for incident forensics only.
+// The temporary table is deleted even when ctx is canceled, which waits for the restore to finish.
+func (con *dynamodb) WithTableAsOf(ctx context.Context, tableName string, at time.Time, fn func(snapshot string) error) error {
+	snapshot := fmt.Sprintf("%s-asof-%d-%d", tableName, at.Unix(), time.Now().UnixNano())
+
+	_, err := con.db.Client().RestoreTableToPointInTimeWithContext(ctx, &awsDynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName: aws.String(con.tableName(tableName)),
+		TargetTableName: aws.String(con.tableName(snapshot)),
+		RestoreDateTime: aws.Time(at),
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		con.waitTableActive(context.Background(), snapshot, request.WithWaiterMaxAttempts(restoreWaitAttempts))
+		con.DeleteTable(snapshot)
+	}()
+
+	if err := con.waitTableActive(ctx, snapshot, request.WithWaiterMaxAttempts(restoreWaitAttempts)); err != nil {
+		return err
+	}
+
+	return fn(snapshot)
+}
+
+// ReadAsOf gets the item of key as it was at the given time. See WithTableAsOf.
+// Encrypted fields are bound to tableName, so they are decrypted as items of tableName.
+func (con *dynamodb) ReadAsOf(ctx context.Context, tableName string, at time.Time, key DynamodbKey, result interface{}) error {
+	return con.WithTableAsOf(ctx, tableName, at, func(snapshot string) error {
+		table := con.table(snapshot)
+		return con.readItem(tableName, result, func(out interface{}) error {
+			return query(&table, key).OneWithContext(ctx, out)
+		})
+	})
+}
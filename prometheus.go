@@ -0,0 +1,158 @@
+package dynamodb
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrometheusMetrics collects Metrics in memory and serves them in the Prometheus text exposition
+// format, so a scrape target needs no client library: mount it with http.Handle("/metrics", m).
+// Metric names are prefixed by the namespace and labeled by op and table; latencies are in seconds.
+type PrometheusMetrics struct {
+	namespace string
+
+	mu         sync.Mutex
+	operations map[metricLabels]int64
+	errors     map[metricLabels]int64
+	throttles  map[metricLabels]int64
+	latency    map[metricLabels]*histogram
+	items      map[metricLabels]*histogram
+}
+
+type metricLabels struct {
+	op    string
+	table string
+}
+
+// NewPrometheusMetrics prefixes the metric names with namespace, "dynamodb" when empty.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	if len(namespace) < 1 {
+		namespace = "dynamodb"
+	}
+	return &PrometheusMetrics{
+		namespace:  namespace,
+		operations: map[metricLabels]int64{},
+		errors:     map[metricLabels]int64{},
+		throttles:  map[metricLabels]int64{},
+		latency:    map[metricLabels]*histogram{},
+		items:      map[metricLabels]*histogram{},
+	}
+}
+
+func (m *PrometheusMetrics) IncOperation(op, table string) {
+	m.inc(m.operations, op, table)
+}
+
+func (m *PrometheusMetrics) IncError(op, table string) {
+	m.inc(m.errors, op, table)
+}
+
+func (m *PrometheusMetrics) IncThrottle(op, table string) {
+	m.inc(m.throttles, op, table)
+}
+
+func (m *PrometheusMetrics) ObserveLatency(op, table string, d time.Duration) {
+	m.histogram(m.latency, op, table, latencySeconds()).observe(d.Seconds())
+}
+
+func (m *PrometheusMetrics) ObserveItems(op, table string, n int) {
+	m.histogram(m.items, op, table, ItemBuckets).observe(float64(n))
+}
+
+func (m *PrometheusMetrics) inc(counters map[metricLabels]int64, op, table string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counters[metricLabels{op: op, table: table}]++
+}
+
+func (m *PrometheusMetrics) histogram(histograms map[metricLabels]*histogram, op, table string, buckets []float64) *histogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	labels := metricLabels{op: op, table: table}
+	h, ok := histograms[labels]
+	if !ok {
+		h = &histogram{buckets: buckets, counts: make([]int64, len(buckets)+1)}
+		histograms[labels] = h
+	}
+	return h
+}
+
+// latencySeconds is LatencyBuckets, which are in milliseconds, in seconds.
+func latencySeconds() []float64 {
+	buckets := make([]float64, len(LatencyBuckets))
+	for i, ms := range LatencyBuckets {
+		buckets[i] = ms / 1000
+	}
+	return buckets
+}
+
+// ServeHTTP writes the metrics in the text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeCounter(out, "operations_total", "API calls.", m.operations)
+	m.writeCounter(out, "errors_total", "API calls that failed.", m.errors)
+	m.writeCounter(out, "throttles_total", "Throttled attempts, including the ones retried.", m.throttles)
+	m.writeHistogram(out, "latency_seconds", "Latency of API calls.", m.latency)
+	m.writeHistogram(out, "items", "Items returned by reads.", m.items)
+}
+
+func (m *PrometheusMetrics) writeCounter(out *bufio.Writer, name, help string, counters map[metricLabels]int64) {
+	name = m.namespace + "_" + name
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, labels := range sortedLabels(counters, nil) {
+		fmt.Fprintf(out, "%s{%s} %d\n", name, labels.String(), counters[labels])
+	}
+}
+
+func (m *PrometheusMetrics) writeHistogram(out *bufio.Writer, name, help string, histograms map[metricLabels]*histogram) {
+	name = m.namespace + "_" + name
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, labels := range sortedLabels(nil, histograms) {
+		h := histograms[labels]
+		h.mu.Lock()
+		var cumulative int64
+		for i, le := range h.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(out, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels.String(), strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(out, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels.String(), h.count)
+		fmt.Fprintf(out, "%s_sum{%s} %s\n", name, labels.String(), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(out, "%s_count{%s} %d\n", name, labels.String(), h.count)
+		h.mu.Unlock()
+	}
+}
+
+func sortedLabels(counters map[metricLabels]int64, histograms map[metricLabels]*histogram) []metricLabels {
+	labels := make([]metricLabels, 0, len(counters)+len(histograms))
+	for l := range counters {
+		labels = append(labels, l)
+	}
+	for l := range histograms {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].table != labels[j].table {
+			return labels[i].table < labels[j].table
+		}
+		return labels[i].op < labels[j].op
+	})
+	return labels
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (l metricLabels) String() string {
+	return `op="` + labelEscaper.Replace(l.op) + `",table="` + labelEscaper.Replace(l.table) + `"`
+}
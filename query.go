@@ -0,0 +1,167 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// DynamodbQuery builds a query by chaining, as an alternative to DynamodbKey:
+//
+//	db.Query(table).Hash("ID", id).Range("CreatedAt", DynamodbGreater, t).Order(DynamodbOrderDesc).Limit(10).All(&out)
+type DynamodbQuery struct {
+	con   *dynamodb
+	table string
+
+	hashKey     string
+	hashValue   interface{}
+	rangeKey    string
+	rangeOp     DynamodbOperator
+	rangeValue  []interface{}
+	index       string
+	filters     []ScanFilter
+	order       *DynamodbOrder
+	limit       int64
+	searchLimit int64
+}
+
+func (con *dynamodb) Query(tableName string) *DynamodbQuery {
+	return &DynamodbQuery{con: con, table: tableName}
+}
+
+// Hash sets the hash key, which is required.
+func (q *DynamodbQuery) Hash(name string, value interface{}) *DynamodbQuery {
+	q.hashKey, q.hashValue = name, value
+	return q
+}
+
+// Range compares the range key. DynamodbBetween takes two values.
+func (q *DynamodbQuery) Range(name string, op DynamodbOperator, values ...interface{}) *DynamodbQuery {
+	q.rangeKey, q.rangeOp, q.rangeValue = name, op, values
+	return q
+}
+
+// Index queries a secondary index instead of the table.
+func (q *DynamodbQuery) Index(name string) *DynamodbQuery {
+	q.index = name
+	return q
+}
+
+// Filter filters the results after they are read. Several filters are combined with AND.
+func (q *DynamodbQuery) Filter(filters ...ScanFilter) *DynamodbQuery {
+	q.filters = append(q.filters, filters...)
+	return q
+}
+
+// Order :
+func (q *DynamodbQuery) Order(order DynamodbOrder) *DynamodbQuery {
+	q.order = &order
+	return q
+}
+
+// Limit stops after this many matching items.
+func (q *DynamodbQuery) Limit(limit int) *DynamodbQuery {
+	q.limit = int64(limit)
+	return q
+}
+
+// SearchLimit stops after evaluating this many items, before filters apply.
+func (q *DynamodbQuery) SearchLimit(limit int) *DynamodbQuery {
+	q.searchLimit = int64(limit)
+	return q
+}
+
+func (q *DynamodbQuery) build() (*dynamo.Query, error) {
+	if len(q.hashKey) < 1 {
+		return nil, errors.New("key empty")
+	}
+
+	req := q.con.table(q.table).Get(q.hashKey, q.hashValue)
+	if len(q.rangeKey) > 0 {
+		req.Range(q.rangeKey, q.rangeOp.value(), q.rangeValue...)
+	}
+	if len(q.index) > 0 {
+		req.Index(q.index)
+	}
+	for _, f := range q.filters {
+		req.Filter(f.Expr, f.args()...)
+	}
+	if q.order != nil {
+		req.Order(q.order.value())
+	}
+	if q.limit > 0 {
+		req.Limit(q.limit)
+	}
+	if q.searchLimit > 0 {
+		req.SearchLimit(q.searchLimit)
+	}
+	return req, nil
+}
+
+// One unmarshals the first result into out.
+func (q *DynamodbQuery) One(out interface{}) error {
+	defer q.con.auditResult("Query", out)()
+
+	req, err := q.build()
+	if err != nil {
+		return err
+	}
+	if hasCodedFields(out) {
+		var av map[string]*awsDynamodb.AttributeValue
+		if err := req.One(&av); err != nil {
+			return err
+		}
+		return q.con.decodeItem(q.table, av, out)
+	}
+	return req.One(out)
+}
+
+// All unmarshals the results into out, which must be a pointer to a slice.
+func (q *DynamodbQuery) All(out interface{}) error {
+	defer q.con.auditResult("Query", out)()
+
+	req, err := q.build()
+	if err != nil {
+		return err
+	}
+	if hasCodedFields(out) {
+		var items []map[string]*awsDynamodb.AttributeValue
+		if err := req.All(&items); err != nil {
+			return err
+		}
+		return q.con.decodeItems(q.table, items, out)
+	}
+	return req.All(out)
+}
+
+// Count :
+func (q *DynamodbQuery) Count() (int64, error) {
+	req, err := q.build()
+	if err != nil {
+		return 0, err
+	}
+	return req.Count()
+}
+
+// Iter :
+func (q *DynamodbQuery) Iter() DynamodbIter {
+	req, err := q.build()
+	if err != nil {
+		return &errIter{err: err}
+	}
+	return &dynamodbIter{con: q.con, table: q.table, iter: req.Iter()}
+}
+
+type errIter struct {
+	err error
+}
+
+func (i *errIter) Next(ctx context.Context, out interface{}) bool {
+	return false
+}
+
+func (i *errIter) Err() error {
+	return i.err
+}
@@ -0,0 +1,59 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	id := faker.UUIDDigit()
+	for _, createdAt := range []string{"2021-01-01", "2021-01-02", "2021-01-03"} {
+		_, err := dynamo.Put(tableNameHashAndRange, HashAndRange{Id: id, CreatedAt: createdAt, Name: faker.Name()})
+		assert.NoError(t, err)
+	}
+
+	t.Run("All", func(t *testing.T) {
+		var items []HashAndRange
+		err := dynamo.Query(tableNameHashAndRange).
+			Hash("ID", id).
+			Range("CreatedAt", DynamodbGreater, "2021-01-01").
+			Order(DynamodbOrderDesc).
+			Limit(1).
+			All(&items)
+		assert.NoError(t, err)
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, "2021-01-03", items[0].CreatedAt)
+		}
+	})
+
+	t.Run("Between", func(t *testing.T) {
+		count, err := dynamo.Query(tableNameHashAndRange).
+			Hash("ID", id).
+			Range("CreatedAt", DynamodbBetween, "2021-01-01", "2021-01-02").
+			Count()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("Iter", func(t *testing.T) {
+		iter := dynamo.Query(tableNameHashAndRange).Hash("ID", id).Iter()
+		var item HashAndRange
+		n := 0
+		for iter.Next(context.Background(), &item) {
+			n++
+		}
+		assert.NoError(t, iter.Err())
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("Failure: hash missing", func(t *testing.T) {
+		var item HashAndRange
+		assert.Error(t, dynamo.Query(tableNameHashAndRange).One(&item))
+		assert.Error(t, dynamo.Query(tableNameHashAndRange).Iter().Err())
+	})
+}
@@ -0,0 +1,178 @@
+// Package queue is a work queue on a DynamoDB table, for job queues where SQS is not available:
+//
+//	q := queue.New(db, "jobs", "emails")
+//	_, err := q.Enqueue(ctx, Email{To: to})
+//
+//	msg, err := q.Dequeue(ctx, time.Minute)
+//	if errors.Is(err, queue.ErrEmpty) {
+//		return nil
+//	}
+//	var email Email
+//	err = msg.Decode(&email)
+//	err = q.Ack(ctx, msg)
+//
+// Each queue is a partition of the table, whose range key orders the messages by enqueue time.
+// A dequeued message is hidden for the visibility timeout and delivered again unless acknowledged,
+// so consumers must tolerate duplicates. Order is kept on a best-effort basis only.
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linksports/dynamodb"
+)
+
+// Errors returned by queues.
+var (
+	// ErrEmpty is returned by Dequeue when no message is visible.
+	ErrEmpty = errors.New("queue empty")
+	// ErrReceiptExpired is returned by Ack when the message was deleted or dequeued again since.
+	ErrReceiptExpired = errors.New("receipt expired")
+)
+
+// Message is an item of the table.
+type Message struct {
+	Queue string `dynamo:"Queue,hash"`
+	// ID is a ULID, which sorts by enqueue time.
+	ID   string `dynamo:"ID,range"`
+	Body string `dynamo:"Body"`
+	// VisibleAt in Unix milliseconds.
+	VisibleAt int64 `dynamo:"VisibleAt"`
+	// Receipt identifies the last delivery, which Ack must present.
+	Receipt  string `dynamo:"Receipt"`
+	Attempts int    `dynamo:"Attempts"`
+}
+
+// Decode unmarshals the JSON body into v.
+func (m *Message) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(m.Body), v)
+}
+
+// Queue :
+type Queue struct {
+	db    dynamodb.Dynamodb
+	table string
+	name  string
+	ids   dynamodb.ULIDGenerator
+}
+
+// New returns the queue name of table. Several queues can share a table.
+func New(db dynamodb.Dynamodb, table, name string) *Queue {
+	return &Queue{db: db, table: table, name: name, ids: dynamodb.ULIDGenerator{Clock: db.Clock()}}
+}
+
+// CreateTable creates the table unless it exists.
+func (q *Queue) CreateTable(ctx context.Context) error {
+	return q.db.ApplyTableSpec(ctx, dynamodb.TableSpec{
+		Name:     q.table,
+		HashKey:  "Queue",
+		RangeKey: "ID",
+		OnDemand: true,
+	})
+}
+
+// Enqueue adds a message with body, encoded as JSON, and returns its ID.
+func (q *Queue) Enqueue(ctx context.Context, body interface{}) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	id, err := q.ids.NewID()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = q.db.Put(q.table, Message{
+		Queue:     q.name,
+		ID:        id,
+		Body:      string(data),
+		VisibleAt: millis(q.db.Clock().Now()),
+	})
+	return id, err
+}
+
+// Dequeue claims the oldest visible message, hiding it from other consumers for visibility.
+// It fails with ErrEmpty when no message is visible.
+func (q *Queue) Dequeue(ctx context.Context, visibility time.Duration) (*Message, error) {
+	now := q.db.Clock().Now()
+	iter := q.db.Query(q.table).
+		Hash("Queue", q.name).
+		Filter(dynamodb.FilterAttr("VisibleAt", dynamodb.DynamodbLessOrEqual, millis(now))).
+		Iter()
+
+	var msg Message
+	for iter.Next(ctx, &msg) {
+		claimed, err := q.claim(msg, now.Add(visibility))
+		if err != nil {
+			return nil, err
+		}
+		if claimed != nil {
+			return claimed, nil
+		}
+		// Another consumer claimed it first.
+		msg = Message{}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrEmpty
+}
+
+// claim hides msg until visibleAt unless another consumer changed it since it was read,
+// in which case it returns nil.
+func (q *Queue) claim(msg Message, visibleAt time.Time) (*Message, error) {
+	receipt, err := newReceipt()
+	if err != nil {
+		return nil, err
+	}
+
+	read := msg.VisibleAt
+	msg.VisibleAt, msg.Receipt, msg.Attempts = millis(visibleAt), receipt, msg.Attempts+1
+	_, err = q.db.PutIf(q.table, msg, dynamodb.FilterAttr("VisibleAt", dynamodb.DynamodbEqual, read))
+	if errors.Is(err, dynamodb.ErrConditionFailed) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// Ack deletes a dequeued message, or fails with ErrReceiptExpired when it was dequeued again
+// after its visibility timeout.
+func (q *Queue) Ack(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := q.db.DeleteIf(q.table, dynamodb.DynamodbKey{
+		Hash:  func() (string, interface{}) { return "Queue", q.name },
+		Range: func() (string, interface{}, *dynamodb.DynamodbOptions) { return "ID", msg.ID, nil },
+	}, dynamodb.FilterAttr("Receipt", dynamodb.DynamodbEqual, msg.Receipt))
+	if errors.Is(err, dynamodb.ErrConditionFailed) {
+		return fmt.Errorf("%w: %s", ErrReceiptExpired, msg.ID)
+	}
+	return err
+}
+
+func newReceipt() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
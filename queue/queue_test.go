@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/linksports/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDynamo(t *testing.T) dynamodb.Dynamodb {
+	return newDynamoWithClock(t, nil)
+}
+
+func newDynamoWithClock(t *testing.T, clock dynamodb.Clock) dynamodb.Dynamodb {
+	db, err := dynamodb.New(session.New(), &dynamodb.DynamodbConfig{
+		Endpoint: "http://localhost:8000",
+		Region:   "us-east-1",
+		Clock:    clock,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+type job struct {
+	N int
+}
+
+func TestQueue(t *testing.T) {
+	db := newDynamo(t)
+	ctx := context.Background()
+
+	table := "queue-" + faker.UUIDDigit()
+	defer db.DeleteTableWithContext(ctx, table)
+	if !assert.NoError(t, New(db, table, "").CreateTable(ctx)) {
+		t.FailNow()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		q := New(db, table, "fifo")
+		for i := 1; i <= 3; i++ {
+			_, err := q.Enqueue(ctx, job{N: i})
+			assert.NoError(t, err)
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		for i := 1; i <= 3; i++ {
+			msg, err := q.Dequeue(ctx, time.Minute)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			var j job
+			assert.NoError(t, msg.Decode(&j))
+			assert.Equal(t, i, j.N)
+			assert.Equal(t, 1, msg.Attempts)
+			assert.NoError(t, q.Ack(ctx, msg))
+		}
+
+		_, err := q.Dequeue(ctx, time.Minute)
+		assert.True(t, errors.Is(err, ErrEmpty))
+	})
+
+	t.Run("Success: redelivered after visibility timeout", func(t *testing.T) {
+		q := New(db, table, "redeliver")
+		_, err := q.Enqueue(ctx, job{N: 1})
+		assert.NoError(t, err)
+
+		first, err := q.Dequeue(ctx, 50*time.Millisecond)
+		assert.NoError(t, err)
+		_, err = q.Dequeue(ctx, time.Minute)
+		assert.True(t, errors.Is(err, ErrEmpty))
+
+		time.Sleep(100 * time.Millisecond)
+		second, err := q.Dequeue(ctx, time.Minute)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, 2, second.Attempts)
+
+		assert.True(t, errors.Is(q.Ack(ctx, first), ErrReceiptExpired))
+		assert.NoError(t, q.Ack(ctx, second))
+	})
+
+	t.Run("Success: visibility on the Clock of the client", func(t *testing.T) {
+		clock := dynamodb.NewManualClock(time.Now())
+		q := New(newDynamoWithClock(t, clock), table, "clocked")
+		_, err := q.Enqueue(ctx, job{N: 1})
+		assert.NoError(t, err)
+
+		first, err := q.Dequeue(ctx, time.Hour)
+		assert.NoError(t, err)
+		_, err = q.Dequeue(ctx, time.Hour)
+		assert.True(t, errors.Is(err, ErrEmpty))
+
+		clock.Advance(2 * time.Hour)
+		second, err := q.Dequeue(ctx, time.Hour)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, first.ID, second.ID)
+		assert.NoError(t, q.Ack(ctx, second))
+	})
+}
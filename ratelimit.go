@@ -0,0 +1,186 @@
+package dynamodb
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// RateLimit keeps the calls of one client on a table under a share of its capacity, such as a batch
+// job running next to the production workload. Zero values are unlimited.
+//
+// The units are metered from the ConsumedCapacity returned by DynamoDB, so a call is only delayed
+// once earlier calls used up the budget, and one large call may overshoot it briefly.
+type RateLimit struct {
+	MaxReadUnitsPerSec  float64
+	MaxWriteUnitsPerSec float64
+}
+
+// readOperations are metered against MaxReadUnitsPerSec, the other item operations against MaxWriteUnitsPerSec.
+var readOperations = map[string]bool{
+	"GetItem":          true,
+	"Query":            true,
+	"Scan":             true,
+	"BatchGetItem":     true,
+	"TransactGetItems": true,
+}
+
+var writeOperations = map[string]bool{
+	"PutItem":            true,
+	"UpdateItem":         true,
+	"DeleteItem":         true,
+	"BatchWriteItem":     true,
+	"TransactWriteItems": true,
+}
+
+// tokenBucket holds up to one second of units. Metered calls may take it below zero, which delays
+// the next calls until it refills.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: rate, last: time.Now()}
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+}
+
+// wait blocks until the bucket holds tokens.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		b.refill()
+		debt := -b.tokens
+		b.mu.Unlock()
+		if debt < 0 {
+			return nil
+		}
+
+		d := time.Duration(debt/b.rate*float64(time.Second)) + time.Millisecond
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *tokenBucket) take(units float64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.tokens -= units
+}
+
+type tableLimiter struct {
+	read, write *tokenBucket
+}
+
+func (l *tableLimiter) bucket(op string) *tokenBucket {
+	if readOperations[op] {
+		return l.read
+	}
+	return l.write
+}
+
+// newLimiters returns the limiters of DynamodbConfig.RateLimits by the table names sent to DynamoDB.
+func newLimiters(config *DynamodbConfig) map[string]*tableLimiter {
+	if len(config.RateLimits) < 1 {
+		return nil
+	}
+	limiters := make(map[string]*tableLimiter, len(config.RateLimits))
+	for table, limit := range config.RateLimits {
+		limiters[config.TablePrefix+table] = &tableLimiter{
+			read:  newTokenBucket(limit.MaxReadUnitsPerSec),
+			write: newTokenBucket(limit.MaxWriteUnitsPerSec),
+		}
+	}
+	return limiters
+}
+
+// limitRate waits for the budget of the tables of op, and asks DynamoDB for the consumed capacity to meter.
+func (con *dynamodb) limitRate(ctx context.Context, op OperationInfo) error {
+	if con.limiters == nil || !readOperations[op.Name] && !writeOperations[op.Name] {
+		return nil
+	}
+
+	in := reflect.ValueOf(op.Input)
+	if in.Kind() != reflect.Ptr || in.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	if field := in.Elem().FieldByName("ReturnConsumedCapacity"); field.IsValid() && field.IsNil() {
+		field.Set(reflect.ValueOf(aws.String(awsDynamodb.ReturnConsumedCapacityTotal)))
+	}
+
+	tables := []string{op.Table}
+	if items := in.Elem().FieldByName("RequestItems"); items.IsValid() && items.Kind() == reflect.Map {
+		tables = tables[:0]
+		for _, key := range items.MapKeys() {
+			tables = append(tables, key.String())
+		}
+	}
+	for _, table := range tables {
+		if limiter := con.limiters[table]; limiter != nil {
+			if err := limiter.bucket(op.Name).wait(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// meterRate takes the capacity consumed by op, given a pointer to its SDK output, from the buckets of its tables.
+func (con *dynamodb) meterRate(op OperationInfo, output interface{}) {
+	if con.limiters == nil || !readOperations[op.Name] && !writeOperations[op.Name] {
+		return
+	}
+
+	out := reflect.ValueOf(output)
+	for out.Kind() == reflect.Ptr && !out.IsNil() {
+		out = out.Elem()
+	}
+	if out.Kind() != reflect.Struct {
+		return
+	}
+
+	field := out.FieldByName("ConsumedCapacity")
+	if !field.IsValid() {
+		return
+	}
+	var consumed []*awsDynamodb.ConsumedCapacity
+	switch cc := field.Interface().(type) {
+	case *awsDynamodb.ConsumedCapacity:
+		consumed = []*awsDynamodb.ConsumedCapacity{cc}
+	case []*awsDynamodb.ConsumedCapacity:
+		consumed = cc
+	}
+	for _, cc := range consumed {
+		if cc == nil {
+			continue
+		}
+		if limiter := con.limiters[aws.StringValue(cc.TableName)]; limiter != nil {
+			limiter.bucket(op.Name).take(aws.Float64Value(cc.CapacityUnits))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimits(t *testing.T) {
+	t.Run("Success: writes delayed", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{RateLimits: map[string]RateLimit{
+			tableNameHashOnly: {MaxWriteUnitsPerSec: 1},
+		}})
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := dynamo.Put(tableNameHashOnly, HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()})
+			assert.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(900*time.Millisecond))
+	})
+
+	t.Run("Success: other tables unlimited", func(t *testing.T) {
+		dynamo := newDynamoWithConfig(t, &DynamodbConfig{RateLimits: map[string]RateLimit{
+			tableNameHashAndRange: {MaxWriteUnitsPerSec: 1},
+		}})
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := dynamo.Put(tableNameHashOnly, HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()})
+			assert.NoError(t, err)
+		}
+		assert.Less(t, int64(time.Since(start)), int64(900*time.Millisecond))
+	})
+}
+
+func TestMeterRate(t *testing.T) {
+	con := &dynamodb{config: &DynamodbConfig{}, limiters: newLimiters(&DynamodbConfig{
+		TablePrefix: "dev-",
+		RateLimits:  map[string]RateLimit{"users": {MaxReadUnitsPerSec: 10}},
+	})}
+
+	in := &awsDynamodb.GetItemInput{TableName: aws.String("dev-users")}
+	op := OperationInfo{Name: "GetItem", Table: "dev-users", Input: in}
+	assert.NoError(t, con.limitRate(context.Background(), op))
+	assert.Equal(t, awsDynamodb.ReturnConsumedCapacityTotal, aws.StringValue(in.ReturnConsumedCapacity))
+
+	out := &awsDynamodb.GetItemOutput{ConsumedCapacity: &awsDynamodb.ConsumedCapacity{
+		TableName:     aws.String("dev-users"),
+		CapacityUnits: aws.Float64(15),
+	}}
+	con.meterRate(op, &out)
+	limiter := con.limiters["dev-users"]
+	assert.InDelta(t, -5, limiter.read.tokens, 0.1)
+	assert.Nil(t, limiter.write)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, con.limitRate(ctx, op))
+}
@@ -0,0 +1,41 @@
+package dynamodb
+
+import "github.com/guregu/dynamo"
+
+// RawDynamodb is the escape hatch to github.com/guregu/dynamo for features this package does not
+// cover, sharing the session and config of the client. Calls through it still run the middlewares,
+// rate limits, logging and metrics, but skip item encoding such as codecs, encryption, TTL
+// policies and caching.
+type RawDynamodb interface {
+	// Raw returns the dynamo client, whose table names do not get DynamodbConfig.TablePrefix.
+	Raw() *dynamo.DB
+	// RawTable returns the table of name, with DynamodbConfig.TablePrefix.
+	RawTable(name string) dynamo.Table
+}
+
+// AsRaw returns the escape hatch of db, looking through NewCached and the primary region of
+// NewMultiRegion. It returns false for other implementations, such as mocks.
+func AsRaw(db Dynamodb) (RawDynamodb, bool) {
+	for {
+		switch d := db.(type) {
+		case RawDynamodb:
+			return d, true
+		case *cached:
+			db = d.Dynamodb
+		case *multiRegion:
+			db = d.Dynamodb
+		default:
+			return nil, false
+		}
+	}
+}
+
+// Raw :
+func (con *dynamodb) Raw() *dynamo.DB {
+	return con.db
+}
+
+// RawTable :
+func (con *dynamodb) RawTable(name string) dynamo.Table {
+	return con.table(name)
+}
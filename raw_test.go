@@ -0,0 +1,36 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsRaw(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dynamo := newDynamo(t)
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		raw, ok := AsRaw(dynamo)
+		if !assert.True(t, ok) {
+			t.FailNow()
+		}
+		var got HashOnly
+		assert.NoError(t, raw.RawTable(tableNameHashOnly).Get(item.HashKey(), item.Id).One(&got))
+		assert.Equal(t, item, got)
+		assert.NotNil(t, raw.Raw())
+	})
+
+	t.Run("Success: cached", func(t *testing.T) {
+		_, ok := AsRaw(NewCached(newDynamo(t), NewMemoryCache(), 0))
+		assert.True(t, ok)
+	})
+
+	t.Run("Failure: unsupported", func(t *testing.T) {
+		_, ok := AsRaw(nil)
+		assert.False(t, ok)
+	})
+}
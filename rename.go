@@ -0,0 +1,142 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+const defaultRenamePageSize = 100
+
+// RenameOptions :
+type RenameOptions struct {
+	// PageSize is the number of items scanned per page. Defaults to 100.
+	PageSize int
+	// StartFrom resumes a job from a checkpoint it reported.
+	StartFrom string
+	// Checkpoint is called after every page with a token to resume from.
+	// An error stops the job.
+	Checkpoint func(token string, result RenameResult) error
+}
+
+// RenameResult :
+type RenameResult struct {
+	// Renamed is the number of items rewritten.
+	Renamed int64
+	// Skipped is the number of items changed by someone else while the job ran.
+	Skipped int64
+}
+
+// RenameAttribute moves oldName to newName on every item of tableName.
+// Each item is updated only if oldName still holds the scanned value and newName is unset,
+// so concurrent writes win and the job can be rerun safely.
+func (con *dynamodb) RenameAttribute(ctx context.Context, tableName, oldName, newName string, opts RenameOptions) (RenameResult, error) {
+	var result RenameResult
+	if oldName == "" || newName == "" {
+		return result, errors.New("attribute name empty")
+	}
+	if oldName == newName {
+		return result, errors.New("attribute names equal")
+	}
+
+	desc, err := con.DescribeTable(tableName)
+	if err != nil {
+		return result, err
+	}
+	if oldName == desc.HashKey || oldName == desc.RangeKey {
+		return result, fmt.Errorf("%s is a key attribute", oldName)
+	}
+
+	startFrom, err := decodePagingKey(opts.StartFrom)
+	if err != nil {
+		return result, err
+	}
+
+	pageSize := opts.PageSize
+	if pageSize < 1 {
+		pageSize = defaultRenamePageSize
+	}
+
+	table := con.table(tableName)
+	for {
+		scan := table.Scan().
+			Filter("attribute_exists($)", oldName).
+			SearchLimit(int64(pageSize))
+		if startFrom != nil {
+			scan = scan.StartFrom(startFrom)
+		}
+
+		var items []map[string]*awsDynamodb.AttributeValue
+		last, err := scan.AllWithLastEvaluatedKeyContext(ctx, &items)
+		if err != nil {
+			return result, err
+		}
+
+		for _, item := range items {
+			update := table.Update(desc.HashKey, item[desc.HashKey])
+			if desc.RangeKey != "" {
+				update = update.Range(desc.RangeKey, item[desc.RangeKey])
+			}
+
+			err := update.
+				Set(newName, item[oldName]).
+				Remove(oldName).
+				If("$ = ?", oldName, item[oldName]).
+				If("attribute_not_exists($)", newName).
+				RunWithContext(ctx)
+			switch {
+			case err == nil:
+				result.Renamed++
+			case isConditionalCheckFailed(err):
+				result.Skipped++
+			default:
+				return result, err
+			}
+		}
+
+		if last == nil {
+			return result, nil
+		}
+		startFrom = last
+
+		if opts.Checkpoint != nil {
+			token, err := encodePagingKey(last)
+			if err != nil {
+				return result, err
+			}
+			if err := opts.Checkpoint(token, result); err != nil {
+				return result, err
+			}
+		}
+	}
+}
+
+func encodePagingKey(key dynamo.PagingKey) (string, error) {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodePagingKey(token string) (dynamo.PagingKey, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var key dynamo.PagingKey
+	if err := json.Unmarshal(b, &key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return key, nil
+}
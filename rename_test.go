@@ -0,0 +1,81 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type RenameBefore struct {
+	Id   string `dynamo:"ID,hash"`
+	Name string `dynamo:"Name"`
+}
+
+type RenameAfter struct {
+	Id       string `dynamo:"ID,hash"`
+	Name     string `dynamo:"Name"`
+	FullName string `dynamo:"FullName"`
+}
+
+func TestRenameAttribute(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := "rename-" + faker.UUIDDigit()
+	if err := dynamo.CreateTableWithOptions(name, RenameBefore{}, CreateTableOptions{Wait: true}); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		dynamo.DeleteTable(name)
+		dynamo.WaitUntilTableDeleted(context.Background(), name)
+	}()
+
+	for i := 0; i < 5; i++ {
+		_, err := dynamo.Put(name, RenameBefore{Id: faker.UUIDDigit(), Name: faker.Name()})
+		assert.NoError(t, err)
+	}
+	conflict := RenameAfter{Id: faker.UUIDDigit(), Name: "old", FullName: "new"}
+	_, err := dynamo.Put(name, conflict)
+	assert.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		var tokens []string
+		result, err := dynamo.RenameAttribute(context.Background(), name, "Name", "FullName", RenameOptions{
+			PageSize: 2,
+			Checkpoint: func(token string, _ RenameResult) error {
+				tokens = append(tokens, token)
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.Renamed)
+		assert.Equal(t, int64(1), result.Skipped)
+		assert.NotEmpty(t, tokens)
+
+		var items []RenameAfter
+		assert.NoError(t, dynamo.Scan(name, &items))
+		for _, item := range items {
+			assert.NotEmpty(t, item.FullName)
+			if item.Id == conflict.Id {
+				assert.Equal(t, conflict, item)
+			} else {
+				assert.Empty(t, item.Name)
+			}
+		}
+
+		result, err = dynamo.RenameAttribute(context.Background(), name, "Name", "FullName", RenameOptions{StartFrom: tokens[0]})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), result.Renamed)
+	})
+
+	t.Run("Failure: key attribute", func(t *testing.T) {
+		_, err := dynamo.RenameAttribute(context.Background(), name, "ID", "Key", RenameOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Failure: invalid token", func(t *testing.T) {
+		_, err := dynamo.RenameAttribute(context.Background(), name, "Name", "FullName", RenameOptions{StartFrom: "!"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
@@ -0,0 +1,114 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/guregu/dynamo"
+)
+
+// RequestIDs identify the last AWS request of a call, for support tickets to AWS.
+type RequestIDs struct {
+	RequestID string
+	// ExtendedRequestID is the x-amz-id-2 header, when AWS sends one.
+	ExtendedRequestID string
+}
+
+// RequestIDOf returns the AWS request ID of a failed request, or an empty string.
+func RequestIDOf(err error) string {
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		return reqErr.RequestID()
+	}
+	return ""
+}
+
+type requestIDsKey struct{}
+
+type requestIDRecorder struct {
+	mu      sync.Mutex
+	ids     RequestIDs
+	start   time.Time
+	pages   int
+	count   int64
+	scanned int64
+}
+
+// recordContext records the request IDs and pages of calls made with ctx.
+func recordContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDsKey{}, &requestIDRecorder{start: time.Now()})
+}
+
+// callContext bounds a call the way dynamo does for methods without a context
+// and records its request IDs.
+func callContext() (context.Context, context.CancelFunc) {
+	ctx := recordContext(context.Background())
+	if dynamo.RetryTimeout > 0 {
+		return context.WithTimeout(ctx, dynamo.RetryTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func requestIDs(ctx context.Context) RequestIDs {
+	rec, ok := ctx.Value(requestIDsKey{}).(*requestIDRecorder)
+	if !ok {
+		return RequestIDs{}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.ids
+}
+
+// recordPage counts a query or scan page read with a recorded ctx, and the items it returned and evaluated.
+func recordPage(ctx context.Context, count, scanned *int64) {
+	rec, ok := ctx.Value(requestIDsKey{}).(*requestIDRecorder)
+	if !ok {
+		return
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.pages++
+	rec.count += aws.Int64Value(count)
+	rec.scanned += aws.Int64Value(scanned)
+}
+
+// recordedStats returns the stats of the query or scan pages read with ctx so far.
+func recordedStats(ctx context.Context) QueryStats {
+	rec, ok := ctx.Value(requestIDsKey{}).(*requestIDRecorder)
+	if !ok {
+		return QueryStats{}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return QueryStats{
+		Count:        rec.count,
+		ScannedCount: rec.scanned,
+		Pages:        rec.pages,
+		Duration:     time.Since(rec.start),
+	}
+}
+
+// recordRequestID is a Complete handler storing the IDs of requests made with a callContext.
+func recordRequestID(r *request.Request) {
+	rec, ok := r.Context().Value(requestIDsKey{}).(*requestIDRecorder)
+	if !ok {
+		return
+	}
+
+	ids := RequestIDs{RequestID: r.RequestID}
+	if r.HTTPResponse != nil {
+		ids.ExtendedRequestID = r.HTTPResponse.Header.Get("X-Amz-Id-2")
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.ids = ids
+}
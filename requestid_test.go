@@ -0,0 +1,33 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDs(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	t.Run("Response", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit()}
+		res, err := dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.RequestID)
+
+		var result HashOnly
+		readRes, err := dynamo.GetWithResponse(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return HashOnly{}.HashKey(), item.Id },
+		}, &result)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, readRes.RequestID)
+		assert.NotEqual(t, res.RequestID, readRes.RequestID)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := dynamo.DescribeTable("not-exists")
+		assert.Error(t, err)
+		assert.NotEmpty(t, RequestIDOf(err))
+	})
+}
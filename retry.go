@@ -0,0 +1,138 @@
+package dynamodb
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Defaults used for unset RetryPolicy fields.
+const (
+	DefaultRetryMaxAttempts = 5
+	DefaultRetryBaseDelay   = 50 * time.Millisecond
+	DefaultRetryMaxDelay    = 5 * time.Second
+)
+
+// RetryPolicy retries failed requests with exponential backoff and full jitter.
+//
+// The retries of the policy replace those dynamo makes on its own, so a throttling or server error
+// is returned once MaxAttempts are spent. Such an error is no longer an awserr.RequestFailure itself;
+// use errors.As to read its status code or request ID.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Retryable classifies errors, overriding the classification of the SDK. Defaults to that
+	// of the SDK, falling back to IsRetryable.
+	Retryable func(err error) bool
+}
+
+// IsRetryable reports whether err is a throttling, server side or transient network error.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if request.IsErrorThrottle(err) {
+		return true
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		if code := reqErr.StatusCode(); code >= 500 && code != 501 {
+			return true
+		}
+	}
+	return request.IsErrorRetryable(err)
+}
+
+// Delay returns how long to wait before the given retry, counted from 0.
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	if max <= 0 {
+		max = DefaultRetryMaxDelay
+	}
+
+	delay := max
+	if retry < 32 {
+		if d := base << uint(retry); d > 0 && d < max {
+			delay = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// retryer applies a RetryPolicy to every request of the SDK client.
+type retryer struct {
+	policy RetryPolicy
+}
+
+func (r retryer) MaxRetries() int {
+	if r.policy.MaxAttempts < 1 {
+		return DefaultRetryMaxAttempts - 1
+	}
+	return r.policy.MaxAttempts - 1
+}
+
+func (r retryer) ShouldRetry(req *request.Request) bool {
+	if r.policy.Retryable != nil {
+		return r.policy.Retryable(req.Error)
+	}
+	if req.Retryable != nil {
+		return *req.Retryable
+	}
+	return IsRetryable(req.Error)
+}
+
+func (r retryer) RetryRules(req *request.Request) time.Duration {
+	return r.policy.Delay(req.RetryCount)
+}
+
+// spentRetries hides the status code of err from dynamo when the SDK retryer is the one bounding retries,
+// which is when either RetryPolicy or MaxRetries is set.
+// dynamo retries throttling and server errors again until its RetryTimeout, which would multiply the
+// attempts of the RetryPolicy.
+func (con *dynamodb) spentRetries(err error) error {
+	if con.config.RetryPolicy == nil && con.config.MaxRetries == 0 {
+		return err
+	}
+
+	reqErr, ok := err.(awserr.RequestFailure)
+	if !ok {
+		return err
+	}
+	if reqErr.StatusCode() >= 500 || request.IsErrorThrottle(err) {
+		return &retriesSpentError{reqErr}
+	}
+	return err
+}
+
+// retriesSpentError is an awserr.Error, but not an awserr.RequestFailure, which dynamo retries.
+type retriesSpentError struct {
+	err awserr.RequestFailure
+}
+
+func (e *retriesSpentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retriesSpentError) Code() string {
+	return e.err.Code()
+}
+
+func (e *retriesSpentError) Message() string {
+	return e.err.Message()
+}
+
+func (e *retriesSpentError) OrigErr() error {
+	return e.err.OrigErr()
+}
+
+func (e *retriesSpentError) Unwrap() error {
+	return e.err
+}
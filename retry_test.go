@@ -0,0 +1,125 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	throttled := awserr.NewRequestFailure(awserr.New(awsDynamodb.ErrCodeProvisionedThroughputExceededException, "", nil), 400, "")
+	unavailable := awserr.NewRequestFailure(awserr.New("ServiceUnavailable", "", nil), 503, "")
+	conditional := awserr.NewRequestFailure(awserr.New(awsDynamodb.ErrCodeConditionalCheckFailedException, "", nil), 400, "")
+
+	assert.True(t, IsRetryable(throttled))
+	assert.True(t, IsRetryable(unavailable))
+	assert.True(t, IsRetryable(awserr.New("RequestError", "", errors.New("connection reset by peer"))))
+	assert.False(t, IsRetryable(conditional))
+	assert.False(t, IsRetryable(nil))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond}
+
+	for retry := 0; retry < 64; retry++ {
+		delay := policy.Delay(retry)
+		assert.True(t, delay >= 0)
+		assert.True(t, delay <= 100*time.Millisecond)
+		if retry == 0 {
+			assert.True(t, delay <= 10*time.Millisecond)
+		}
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Second}
+
+	delays := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		delays[policy.Delay(0)] = true
+	}
+	assert.True(t, len(delays) > 1)
+}
+
+func TestRetryer(t *testing.T) {
+	throttled := awserr.NewRequestFailure(awserr.New(awsDynamodb.ErrCodeProvisionedThroughputExceededException, "", nil), 400, "")
+	conditional := awserr.NewRequestFailure(awserr.New(awsDynamodb.ErrCodeConditionalCheckFailedException, "", nil), 400, "")
+
+	t.Run("MaxRetries", func(t *testing.T) {
+		assert.Equal(t, DefaultRetryMaxAttempts-1, retryer{}.MaxRetries())
+		assert.Equal(t, 2, retryer{policy: RetryPolicy{MaxAttempts: 3}}.MaxRetries())
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		r := retryer{}
+		assert.True(t, r.ShouldRetry(&request.Request{Error: throttled}))
+		assert.False(t, r.ShouldRetry(&request.Request{Error: conditional}))
+	})
+
+	t.Run("Retryable overrides the SDK", func(t *testing.T) {
+		r := retryer{policy: RetryPolicy{Retryable: func(err error) bool { return err == conditional }}}
+		assert.True(t, r.ShouldRetry(&request.Request{Error: conditional, Retryable: aws.Bool(false)}))
+		assert.False(t, r.ShouldRetry(&request.Request{Error: throttled, Retryable: aws.Bool(true)}))
+	})
+
+	t.Run("RetryRules", func(t *testing.T) {
+		r := retryer{policy: RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}}
+		assert.True(t, r.RetryRules(&request.Request{RetryCount: 10}) <= 4*time.Millisecond)
+	})
+}
+
+func TestSpentRetries(t *testing.T) {
+	throttled := awserr.NewRequestFailure(awserr.New(awsDynamodb.ErrCodeProvisionedThroughputExceededException, "", nil), 400, "id")
+	unavailable := awserr.NewRequestFailure(awserr.New("ServiceUnavailable", "", nil), 503, "id")
+	conditional := awserr.NewRequestFailure(awserr.New(awsDynamodb.ErrCodeConditionalCheckFailedException, "", nil), 400, "id")
+
+	t.Run("RetryPolicy", func(t *testing.T) {
+		con := &dynamodb{config: &DynamodbConfig{RetryPolicy: &RetryPolicy{}}}
+		for _, err := range []error{throttled, unavailable} {
+			spent := con.spentRetries(err)
+			_, ok := spent.(awserr.RequestFailure)
+			assert.False(t, ok)
+			assert.True(t, errors.Is(spent, err))
+			assert.True(t, IsRetryable(spent))
+			assert.Equal(t, "id", RequestIDOf(spent))
+		}
+		assert.Equal(t, conditional, con.spentRetries(conditional))
+	})
+
+	t.Run("MaxRetries", func(t *testing.T) {
+		for _, maxRetries := range []int{-1, 2} {
+			con := &dynamodb{config: &DynamodbConfig{MaxRetries: maxRetries}}
+			_, ok := con.spentRetries(throttled).(awserr.RequestFailure)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("SDK default", func(t *testing.T) {
+		con := &dynamodb{config: &DynamodbConfig{}}
+		assert.Equal(t, throttled, con.spentRetries(throttled))
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var errs []error
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			Retryable: func(err error) bool {
+				errs = append(errs, err)
+				return true
+			},
+		},
+	})
+
+	_, err := dynamo.DescribeTable("not-exists")
+	assert.Error(t, err)
+	assert.Len(t, errs, 3)
+}
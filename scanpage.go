@@ -0,0 +1,51 @@
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/guregu/dynamo"
+)
+
+// ScanPageOptions :
+type ScanPageOptions struct {
+	// PageSize is the number of items evaluated, before filters. Defaults to one page of up to 1MB.
+	PageSize int
+	// StartFrom is the token returned by the previous page. Empty starts from the beginning.
+	StartFrom string
+}
+
+// ScanPage scans one page of the table into result and returns the token to pass as StartFrom
+// for the next page, empty after the last. Tokens are those of RenameOptions, so a job can be
+// resumed in slices, such as across maintenance windows.
+func (con *dynamodb) ScanPage(ctx context.Context, tableName string, result interface{}, opts ScanPageOptions, filters ...ScanFilter) (string, error) {
+	defer con.auditResult("Scan", result)()
+
+	startFrom, err := decodePagingKey(opts.StartFrom)
+	if err != nil {
+		return "", err
+	}
+
+	req := scan(con.table(tableName), filters...)
+	if opts.PageSize > 0 {
+		req = req.SearchLimit(int64(opts.PageSize))
+	}
+	if startFrom != nil {
+		req = req.StartFrom(startFrom)
+	}
+
+	ctx, cancel := con.scanContext(ctx)
+	defer cancel()
+
+	var last dynamo.PagingKey
+	err = con.readItems(tableName, result, func(out interface{}) error {
+		last, err = req.AllWithLastEvaluatedKeyContext(ctx, out)
+		return err
+	})
+	if err := scanError(ctx, err); err != nil {
+		return "", err
+	}
+	if last == nil {
+		return "", nil
+	}
+	return encodePagingKey(last)
+}
@@ -0,0 +1,50 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanPage(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "scan-page-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	items := make([]HashOnly, 5)
+	for i := range items {
+		items[i] = HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	}
+	_, err := dynamo.BulkPut(ctx, name, items, BulkPutOptions{})
+	assert.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		var all []HashOnly
+		token, pages := "", 0
+		for {
+			var page []HashOnly
+			next, err := dynamo.ScanPage(ctx, name, &page, ScanPageOptions{PageSize: 2, StartFrom: token})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.LessOrEqual(t, len(page), 2)
+			all, token, pages = append(all, page...), next, pages+1
+			if len(token) < 1 {
+				break
+			}
+		}
+		assert.ElementsMatch(t, items, all)
+		assert.GreaterOrEqual(t, pages, 3)
+	})
+
+	t.Run("Failure: invalid token", func(t *testing.T) {
+		var page []HashOnly
+		_, err := dynamo.ScanPage(ctx, name, &page, ScanPageOptions{StartFrom: "!"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
@@ -0,0 +1,238 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaDrift is one difference between a table and its expected schema.
+type SchemaDrift struct {
+	// Path names the part of the schema, such as "RangeKey" or "GSI Status-index Projection".
+	Path     string
+	Expected string
+	Actual   string
+}
+
+// SchemaDriftError lists the differences found by ValidateSchema and ValidateTableSpec.
+type SchemaDriftError struct {
+	Table  string
+	Drifts []SchemaDrift
+}
+
+func (e *SchemaDriftError) Error() string {
+	drifts := make([]string, len(e.Drifts))
+	for i, d := range e.Drifts {
+		drifts[i] = fmt.Sprintf("%s is %q, expected %q", d.Path, d.Actual, d.Expected)
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrSpecMismatch, e.Table, strings.Join(drifts, "; "))
+}
+
+func (e *SchemaDriftError) Unwrap() error {
+	return ErrSpecMismatch
+}
+
+// ValidateSchema compares the keys and indexes of the table with those declared by the struct tags
+// of entity, like CreateTableWithContext reads them, and returns a *SchemaDriftError listing the
+// differences, so a deploy can fail before running against a drifted table.
+func (con *dynamodb) ValidateSchema(ctx context.Context, tableName string, entity interface{}) error {
+	spec, err := specFromEntity(entity)
+	if err != nil {
+		return err
+	}
+	spec.Name = tableName
+	return con.ValidateTableSpec(ctx, spec)
+}
+
+// ValidateTableSpec is ValidateSchema against spec. Key types default to string; projections are
+// only compared when set. Billing, streams and TTL are left to ApplyTableSpec.
+func (con *dynamodb) ValidateTableSpec(ctx context.Context, spec TableSpec) error {
+	desc, err := con.table(spec.Name).Describe().RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	actual := TableSpec{
+		HashKey:      desc.HashKey,
+		HashKeyType:  DynamodbKeyType(desc.HashKeyType),
+		RangeKey:     desc.RangeKey,
+		RangeKeyType: DynamodbKeyType(desc.RangeKeyType),
+	}
+	for _, index := range desc.GSI {
+		actual.GSIs = append(actual.GSIs, indexDefinition(index))
+	}
+	for _, index := range desc.LSI {
+		actual.LSIs = append(actual.LSIs, indexDefinition(index))
+	}
+
+	if drifts := compareSchemas(spec, actual); len(drifts) > 0 {
+		return &SchemaDriftError{Table: spec.Name, Drifts: drifts}
+	}
+	return nil
+}
+
+func compareSchemas(expected, actual TableSpec) []SchemaDrift {
+	var drifts []SchemaDrift
+	compare := func(path, expected, actual string) {
+		if expected != actual {
+			drifts = append(drifts, SchemaDrift{Path: path, Expected: expected, Actual: actual})
+		}
+	}
+	compareKey := func(path, expectedKey string, expectedType DynamodbKeyType, actualKey string, actualType DynamodbKeyType) {
+		compare(path, expectedKey, actualKey)
+		if len(expectedKey) > 0 && expectedKey == actualKey {
+			compare(path+"Type", string(expectedType.value()), string(actualType.value()))
+		}
+	}
+	compareIndexes := func(kind string, expected, actual []IndexDefinition) {
+		actualByName := map[string]IndexDefinition{}
+		for _, index := range actual {
+			actualByName[index.Name] = index
+		}
+		for _, index := range expected {
+			path := kind + " " + index.Name
+			got, ok := actualByName[index.Name]
+			if !ok {
+				compare(path, "present", "missing")
+				continue
+			}
+			delete(actualByName, index.Name)
+
+			if kind == "GSI" {
+				compareKey(path+" HashKey", index.HashKey, index.HashKeyType, got.HashKey, got.HashKeyType)
+			}
+			compareKey(path+" RangeKey", index.RangeKey, index.RangeKeyType, got.RangeKey, got.RangeKeyType)
+			if len(index.Projection) > 0 {
+				compare(path+" Projection", string(index.Projection), string(got.Projection))
+				if index.Projection == DynamodbProjectionInclude && got.Projection == DynamodbProjectionInclude {
+					compare(path+" NonKeyAttributes", sortedList(index.NonKeyAttributes), sortedList(got.NonKeyAttributes))
+				}
+			}
+		}
+		for name := range actualByName {
+			compare(kind+" "+name, "missing", "present")
+		}
+	}
+
+	compareKey("HashKey", expected.HashKey, expected.HashKeyType, actual.HashKey, actual.HashKeyType)
+	compareKey("RangeKey", expected.RangeKey, expected.RangeKeyType, actual.RangeKey, actual.RangeKeyType)
+	compareIndexes("GSI", expected.GSIs, actual.GSIs)
+	compareIndexes("LSI", expected.LSIs, actual.LSIs)
+
+	sort.SliceStable(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts
+}
+
+func sortedList(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// specFromEntity reads the keys and indexes declared by the dynamo, index and localIndex tags of entity.
+func specFromEntity(entity interface{}) (TableSpec, error) {
+	rv := reflect.Indirect(reflect.ValueOf(entity))
+	if rv.Kind() != reflect.Struct {
+		return TableSpec{}, fmt.Errorf("entity must be a struct, got %T", entity)
+	}
+
+	var spec TableSpec
+	gsis := map[string]*IndexDefinition{}
+	lsis := map[string]*IndexDefinition{}
+	var addFields func(rv reflect.Value)
+	addFields = func(rv reflect.Value) {
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			fv := rv.Field(i)
+			tag := field.Tag.Get("dynamo")
+			options := strings.Split(tag, ",")
+			name := options[0]
+			if name == "-" {
+				continue
+			}
+			if field.Anonymous && reflect.Indirect(fv).Kind() == reflect.Struct {
+				addFields(reflect.Indirect(fv))
+				continue
+			}
+			if len(name) < 1 {
+				name = field.Name
+			}
+			typ := keyTypeOf(field.Type, options[1:])
+
+			for _, option := range options[1:] {
+				switch option {
+				case "hash":
+					spec.HashKey, spec.HashKeyType = name, typ
+				case "range":
+					spec.RangeKey, spec.RangeKeyType = name, typ
+				}
+			}
+			addIndexKey(gsis, field.Tag.Get("index"), name, typ)
+			addIndexKey(lsis, field.Tag.Get("localIndex"), name, typ)
+		}
+	}
+	addFields(rv)
+
+	for _, index := range gsis {
+		spec.GSIs = append(spec.GSIs, *index)
+	}
+	for _, index := range lsis {
+		spec.LSIs = append(spec.LSIs, *index)
+	}
+	sort.Slice(spec.GSIs, func(i, j int) bool { return spec.GSIs[i].Name < spec.GSIs[j].Name })
+	sort.Slice(spec.LSIs, func(i, j int) bool { return spec.LSIs[i].Name < spec.LSIs[j].Name })
+	return spec, nil
+}
+
+// addIndexKey adds the key of an index tag, such as `index:"Status-index,hash"`.
+func addIndexKey(indexes map[string]*IndexDefinition, tag, name string, typ DynamodbKeyType) {
+	i := strings.LastIndex(tag, ",")
+	if i < 0 {
+		return
+	}
+	indexName, keyType := tag[:i], tag[i+1:]
+
+	index := indexes[indexName]
+	if index == nil {
+		index = &IndexDefinition{Name: indexName}
+		indexes[indexName] = index
+	}
+	switch keyType {
+	case "hash":
+		index.HashKey, index.HashKeyType = name, typ
+	case "range":
+		index.RangeKey, index.RangeKeyType = name, typ
+	}
+}
+
+var textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// keyTypeOf is the attribute type dynamo stores values of t as.
+func keyTypeOf(t reflect.Type, options []string) DynamodbKeyType {
+	for _, option := range options {
+		if option == "unixtime" {
+			return DynamodbKeyTypeNumber
+		}
+	}
+	if t.Implements(textMarshaler) || reflect.PtrTo(t).Implements(textMarshaler) {
+		return DynamodbKeyTypeString
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return DynamodbKeyTypeNumber
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return DynamodbKeyTypeBinary
+		}
+	}
+	return DynamodbKeyTypeString
+}
@@ -0,0 +1,103 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type WithSchemaIndexes struct {
+	Id        string `dynamo:"ID,hash"`
+	CreatedAt string `dynamo:"CreatedAt,range" index:"Status-index,range"`
+	Name      string `dynamo:"Name" localIndex:"ID-Name-index,range"`
+	Status    int    `dynamo:"Status" index:"Status-index,hash"`
+}
+
+func TestValidateSchema(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "validate-" + faker.UUIDDigit()
+	defer dynamo.DeleteTableWithContext(ctx, name)
+	if !assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, WithSchemaIndexes{}, CreateTableOptions{OnDemand: true, Wait: true})) {
+		t.FailNow()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, dynamo.ValidateSchema(ctx, name, WithSchemaIndexes{}))
+	})
+
+	t.Run("Failure: drift", func(t *testing.T) {
+		err := dynamo.ValidateSchema(ctx, name, HashOnly{})
+		assert.True(t, errors.Is(err, ErrSpecMismatch))
+
+		var drift *SchemaDriftError
+		if assert.True(t, errors.As(err, &drift)) {
+			assert.Equal(t, []SchemaDrift{
+				{Path: "GSI Status-index", Expected: "missing", Actual: "present"},
+				{Path: "LSI ID-Name-index", Expected: "missing", Actual: "present"},
+				{Path: "RangeKey", Expected: "", Actual: "CreatedAt"},
+			}, drift.Drifts)
+		}
+	})
+
+	t.Run("Failure: spec drift", func(t *testing.T) {
+		err := dynamo.ValidateTableSpec(ctx, TableSpec{
+			Name:     name,
+			HashKey:  "ID",
+			RangeKey: "CreatedAt",
+			GSIs: []IndexDefinition{
+				{Name: "Status-index", HashKey: "Status", Projection: DynamodbProjectionKeysOnly},
+			},
+			LSIs: []IndexDefinition{
+				{Name: "ID-Name-index", RangeKey: "Name"},
+			},
+		})
+
+		var drift *SchemaDriftError
+		if assert.True(t, errors.As(err, &drift)) {
+			assert.Equal(t, []SchemaDrift{
+				{Path: "GSI Status-index HashKeyType", Expected: "S", Actual: "N"},
+				{Path: "GSI Status-index Projection", Expected: "KEYS_ONLY", Actual: "ALL"},
+				{Path: "GSI Status-index RangeKey", Expected: "", Actual: "CreatedAt"},
+			}, drift.Drifts)
+		}
+	})
+
+	t.Run("Failure: table not found", func(t *testing.T) {
+		assert.Error(t, dynamo.ValidateSchema(ctx, "validate-"+faker.UUIDDigit(), HashOnly{}))
+	})
+}
+
+func TestSpecFromEntity(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		spec, err := specFromEntity(&WithSchemaIndexes{})
+		assert.NoError(t, err)
+		assert.Equal(t, TableSpec{
+			HashKey:      "ID",
+			HashKeyType:  DynamodbKeyTypeString,
+			RangeKey:     "CreatedAt",
+			RangeKeyType: DynamodbKeyTypeString,
+			GSIs: []IndexDefinition{{
+				Name:         "Status-index",
+				HashKey:      "Status",
+				HashKeyType:  DynamodbKeyTypeNumber,
+				RangeKey:     "CreatedAt",
+				RangeKeyType: DynamodbKeyTypeString,
+			}},
+			LSIs: []IndexDefinition{{
+				Name:         "ID-Name-index",
+				RangeKey:     "Name",
+				RangeKeyType: DynamodbKeyTypeString,
+			}},
+		}, spec)
+	})
+
+	t.Run("Failure: not a struct", func(t *testing.T) {
+		_, err := specFromEntity("users")
+		assert.Error(t, err)
+	})
+}
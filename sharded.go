@@ -0,0 +1,220 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// DefaultShardSeparator joins a hash key and its shard when ShardOptions.Separator is unset.
+const DefaultShardSeparator = "#"
+
+// ShardOptions :
+type ShardOptions struct {
+	// Shards is the number of shards of every hash key.
+	Shards int
+	// HashKey is stored as the logical hash key, a string, followed by the separator and the shard.
+	HashKey  string
+	RangeKey string
+	// Separator defaults to DefaultShardSeparator.
+	Separator string
+}
+
+// ShardedTable spreads the items of hot hash keys over shards, such as a leaderboard written by every player.
+// Items land on a shard chosen by a hash of their range key, so every item is always on the same shard.
+type ShardedTable struct {
+	db        Dynamodb
+	tableName string
+	opts      ShardOptions
+}
+
+// NewShardedTable :
+func NewShardedTable(db Dynamodb, tableName string, opts ShardOptions) *ShardedTable {
+	if opts.Shards < 1 {
+		opts.Shards = 1
+	}
+	if len(opts.Separator) < 1 {
+		opts.Separator = DefaultShardSeparator
+	}
+	return &ShardedTable{db: db, tableName: tableName, opts: opts}
+}
+
+// ShardKey returns the stored hash key of a shard.
+func (t *ShardedTable) ShardKey(hashValue string, shard int) string {
+	return hashValue + t.opts.Separator + strconv.Itoa(shard)
+}
+
+func (t *ShardedTable) shardOf(rangeValue *awsDynamodb.AttributeValue) int {
+	h := fnv.New32a()
+	h.Write([]byte(cacheKey("", map[string]*awsDynamodb.AttributeValue{t.opts.RangeKey: rangeValue})))
+	return int(h.Sum32() % uint32(t.opts.Shards))
+}
+
+func (t *ShardedTable) key(hashValue string, rangeValue interface{}) (DynamodbKey, error) {
+	rng, err := dynamo.Marshal(rangeValue)
+	if err != nil {
+		return DynamodbKey{}, err
+	}
+	hash := t.ShardKey(hashValue, t.shardOf(rng))
+	return DynamodbKey{
+		Hash:  func() (string, interface{}) { return t.opts.HashKey, hash },
+		Range: func() (string, interface{}, *DynamodbOptions) { return t.opts.RangeKey, rangeValue, nil },
+	}, nil
+}
+
+// Put writes item to the shard of its range key.
+func (t *ShardedTable) Put(item interface{}) (*DynamodbResponse, error) {
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	hash, rng := av[t.opts.HashKey], av[t.opts.RangeKey]
+	if hash == nil || hash.S == nil {
+		return &DynamodbResponse{}, fmt.Errorf("%s must be a string", t.opts.HashKey)
+	}
+	if rng == nil {
+		return &DynamodbResponse{}, fmt.Errorf("%s missing", t.opts.RangeKey)
+	}
+
+	av[t.opts.HashKey] = &awsDynamodb.AttributeValue{S: aws.String(t.ShardKey(*hash.S, t.shardOf(rng)))}
+	return t.db.Put(t.tableName, av)
+}
+
+// Get reads an item of the logical hash key.
+func (t *ShardedTable) Get(hashValue string, rangeValue interface{}, result interface{}) error {
+	key, err := t.key(hashValue, rangeValue)
+	if err != nil {
+		return err
+	}
+
+	var av map[string]*awsDynamodb.AttributeValue
+	if err := t.db.Get(t.tableName, key, &av); err != nil {
+		return err
+	}
+	return dynamo.UnmarshalItem(t.unshard(av), result)
+}
+
+// Delete :
+func (t *ShardedTable) Delete(hashValue string, rangeValue interface{}) (*DynamodbResponse, error) {
+	key, err := t.key(hashValue, rangeValue)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	return t.db.Delete(t.tableName, key)
+}
+
+// ShardQueryOptions :
+type ShardQueryOptions struct {
+	// Index queries a secondary index whose hash key is the sharded hash key.
+	Index string
+	// SortKey orders the merged results. Defaults to the range key.
+	SortKey    string
+	Descending bool
+	// Limit is the number of items returned, the first ones in the order of SortKey. Each shard is
+	// queried for Limit items only when sorted by the range key of the table; with an Index or another
+	// SortKey, every shard is read in full before the merged items are sorted and truncated.
+	Limit int
+}
+
+// QuerySharded queries every shard of hashValue in parallel and appends the merged items, sorted by
+// SortKey, to result, a pointer to a slice. The items carry the logical hash key.
+func (t *ShardedTable) QuerySharded(ctx context.Context, hashValue string, result interface{}, opts ShardQueryOptions) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("result must be a pointer to a slice, got %T", result)
+	}
+	sortKey := opts.SortKey
+	if len(sortKey) < 1 {
+		sortKey = t.opts.RangeKey
+	}
+	// The items of a shard come in the order of the range key of the table or index queried, so
+	// only then are the first Limit items of every shard enough to merge.
+	shardLimit := len(opts.Index) < 1 && sortKey == t.opts.RangeKey
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		items    []map[string]*awsDynamodb.AttributeValue
+		firstErr error
+	)
+	for shard := 0; shard < t.opts.Shards; shard++ {
+		wg.Add(1)
+		go func(shard int) {
+			defer wg.Done()
+
+			q := t.db.Query(t.tableName).Hash(t.opts.HashKey, t.ShardKey(hashValue, shard))
+			if len(opts.Index) > 0 {
+				q.Index(opts.Index)
+			}
+			if opts.Descending {
+				q.Order(DynamodbOrderDesc)
+			}
+			if shardLimit && opts.Limit > 0 {
+				q.Limit(opts.Limit)
+			}
+
+			var shardItems []map[string]*awsDynamodb.AttributeValue
+			iter := q.Iter()
+			var item map[string]*awsDynamodb.AttributeValue
+			for iter.Next(ctx, &item) {
+				shardItems = append(shardItems, item)
+				item = nil
+			}
+			err := iter.Err()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			items = append(items, shardItems...)
+		}(shard)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compareKeys(items[i][sortKey], items[j][sortKey])
+		if opts.Descending {
+			return c == 1
+		}
+		return c == -1
+	})
+	if opts.Limit > 0 && len(items) > opts.Limit {
+		items = items[:opts.Limit]
+	}
+
+	slice := rv.Elem()
+	elem := slice.Type().Elem()
+	for _, item := range items {
+		v := reflect.New(elem)
+		if err := dynamo.UnmarshalItem(t.unshard(item), v.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, v.Elem()))
+	}
+	return nil
+}
+
+// unshard replaces the stored hash key of item by the logical one.
+func (t *ShardedTable) unshard(item map[string]*awsDynamodb.AttributeValue) map[string]*awsDynamodb.AttributeValue {
+	hash := item[t.opts.HashKey]
+	if hash == nil || hash.S == nil {
+		return item
+	}
+	if i := strings.LastIndex(*hash.S, t.opts.Separator); i >= 0 {
+		item[t.opts.HashKey] = &awsDynamodb.AttributeValue{S: aws.String((*hash.S)[:i])}
+	}
+	return item
+}
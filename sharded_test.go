@@ -0,0 +1,63 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedTable(t *testing.T) {
+	table := NewShardedTable(newDynamo(t), tableNameHashAndRange, ShardOptions{
+		Shards:   4,
+		HashKey:  HashAndRange{}.HashKey(),
+		RangeKey: HashAndRange{}.RangeKey(),
+	})
+
+	id := faker.UUIDDigit()
+	items := make([]HashAndRange, 8)
+	for i := range items {
+		items[i] = HashAndRange{Id: id, CreatedAt: fmt.Sprintf("2021-01-0%d", i+1), Name: faker.Name()}
+		_, err := table.Put(items[i])
+		assert.NoError(t, err)
+	}
+
+	t.Run("Success: get", func(t *testing.T) {
+		var got HashAndRange
+		assert.NoError(t, table.Get(id, items[3].CreatedAt, &got))
+		assert.Equal(t, items[3], got)
+	})
+
+	t.Run("Success: query", func(t *testing.T) {
+		var got []HashAndRange
+		assert.NoError(t, table.QuerySharded(context.Background(), id, &got, ShardQueryOptions{}))
+		assert.Equal(t, items, got)
+	})
+
+	t.Run("Success: query descending with limit", func(t *testing.T) {
+		var got []HashAndRange
+		assert.NoError(t, table.QuerySharded(context.Background(), id, &got, ShardQueryOptions{Descending: true, Limit: 3}))
+		assert.Equal(t, []HashAndRange{items[7], items[6], items[5]}, got)
+	})
+
+	t.Run("Success: query by another sort key with limit", func(t *testing.T) {
+		var got []HashAndRange
+		assert.NoError(t, table.QuerySharded(context.Background(), id, &got, ShardQueryOptions{SortKey: "Name", Limit: 3}))
+
+		sorted := append([]HashAndRange{}, items...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		assert.Equal(t, sorted[:3], got)
+	})
+
+	t.Run("Success: delete", func(t *testing.T) {
+		_, err := table.Delete(id, items[0].CreatedAt)
+		assert.NoError(t, err)
+
+		var got []HashAndRange
+		assert.NoError(t, table.QuerySharded(context.Background(), id, &got, ShardQueryOptions{}))
+		assert.Equal(t, items[1:], got)
+	})
+}
@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// flight is a Get in progress, shared by the identical Gets arriving before it completes.
+type flight struct {
+	done chan struct{}
+	item map[string]*awsDynamodb.AttributeValue
+	res  *DynamodbReadResponse
+	err  error
+}
+
+// coalescedGet runs one query for all concurrent Gets of the same item and decodes the item into each result.
+// Gets are keyed like the cache, so only equality of keys is coalesced.
+func (con *dynamodb) coalescedGet(cacheKey, tableName string, key DynamodbKey, result interface{}) (*DynamodbReadResponse, error) {
+	f := &flight{done: make(chan struct{})}
+	if other, shared := con.flights.LoadOrStore(cacheKey, f); shared {
+		f = other.(*flight)
+		<-f.done
+
+		if f.err != nil {
+			return &DynamodbReadResponse{Coalesced: true}, f.err
+		}
+		return &DynamodbReadResponse{Coalesced: true}, con.decodeItem(tableName, f.item, result)
+	}
+
+	func() {
+		defer func() {
+			con.flights.Delete(cacheKey)
+			close(f.done)
+		}()
+
+		ctx, cancel := callContext()
+		defer cancel()
+
+		cc := con.capacity()
+		table := con.table(tableName)
+		f.err = query(&table, key).ConsumedCapacity(cc).OneWithContext(ctx, &f.item)
+		f.res = readResponse(ctx, cc)
+	}()
+
+	if f.err != nil {
+		return f.res, f.err
+	}
+	return f.res, con.decodeItem(tableName, f.item, result)
+}
@@ -0,0 +1,73 @@
+package dynamodb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceGets(t *testing.T) {
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{CoalesceGets: true})
+
+	var queries int32
+	dynamo.Use(func(ctx context.Context, op OperationInfo, next Handler) error {
+		if op.Name == "Query" && op.Table == tableNameHashOnly {
+			atomic.AddInt32(&queries, 1)
+			time.Sleep(200 * time.Millisecond)
+		}
+		return next(ctx)
+	})
+
+	item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	_, err := dynamo.Put(tableNameHashOnly, item)
+	assert.NoError(t, err)
+	key := DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", item.Id },
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		atomic.StoreInt32(&queries, 0)
+
+		var wg sync.WaitGroup
+		results := make([]HashOnly, 10)
+		coalesced := make([]bool, len(results))
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				res, err := dynamo.GetWithResponse(tableNameHashOnly, key, &results[i])
+				assert.NoError(t, err)
+				coalesced[i] = res.Coalesced
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Less(t, int(atomic.LoadInt32(&queries)), len(results))
+		assert.Contains(t, coalesced, true)
+		for _, result := range results {
+			assert.Equal(t, item, result)
+		}
+	})
+
+	t.Run("Failure: not found is shared", func(t *testing.T) {
+		missing := DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", faker.UUIDDigit() },
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var result HashOnly
+				assert.Error(t, dynamo.Get(tableNameHashOnly, missing, &result))
+			}()
+		}
+		wg.Wait()
+	})
+}
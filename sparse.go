@@ -0,0 +1,46 @@
+package dynamodb
+
+import (
+	"context"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Attributes tagged `dynamo:"Name,sparse"` control the membership of an item in a sparse index,
+// so Put leaves them out while they hold their zero value instead of indexing the item under 0 or false.
+func removeSparse(av map[string]*awsDynamodb.AttributeValue, fields []taggedField) {
+	for _, field := range fields {
+		if field.value.IsZero() {
+			delete(av, field.name)
+		}
+	}
+}
+
+// SetSparse sets attribute on the existing item of key, adding it to sparse indexes keyed by attribute.
+func (con *dynamodb) SetSparse(tableName string, key DynamodbKey, attribute string, value interface{}) error {
+	hKey, _ := key.Hash()
+	return updateItem(con.table(tableName), key).
+		Set(attribute, value).
+		If("attribute_exists($)", hKey).
+		Run()
+}
+
+// ClearSparse removes attribute from the item of key, taking it out of sparse indexes keyed by attribute.
+func (con *dynamodb) ClearSparse(tableName string, key DynamodbKey, attribute string) error {
+	hKey, _ := key.Hash()
+	return updateItem(con.table(tableName), key).
+		Remove(attribute).
+		If("attribute_exists($)", hKey).
+		Run()
+}
+
+// ScanSparseIndex reads every item of a sparse index, which holds only the flagged items.
+func (con *dynamodb) ScanSparseIndex(tableName, indexName string, result interface{}) error {
+	ctx, cancel := con.scanContext(context.Background())
+	defer cancel()
+
+	err := con.readItems(tableName, result, func(out interface{}) error {
+		return con.table(tableName).Scan().Index(indexName).AllWithContext(ctx, out)
+	})
+	return scanError(ctx, err)
+}
@@ -0,0 +1,74 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type SparseTask struct {
+	Id      string `dynamo:"ID,hash"`
+	Name    string `dynamo:"Name"`
+	Pending int    `dynamo:"Pending,sparse"`
+}
+
+func TestSparse(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := "sparse-" + faker.UUIDDigit()
+	err := dynamo.CreateTableWithOptions(name, SparseTask{}, CreateTableOptions{
+		OnDemand: true,
+		GSIs: []IndexDefinition{
+			{Name: "Pending-index", HashKey: "Pending", HashKeyType: DynamodbKeyTypeNumber, Projection: DynamodbProjectionKeysOnly},
+		},
+		Wait: true,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		dynamo.DeleteTable(name)
+		dynamo.WaitUntilTableDeleted(context.Background(), name)
+	}()
+
+	done := SparseTask{Id: faker.UUIDDigit(), Name: "done"}
+	pending := SparseTask{Id: faker.UUIDDigit(), Name: "pending", Pending: 1}
+	_, err = dynamo.Put(name, done)
+	assert.NoError(t, err)
+	_, err = dynamo.Put(name, &pending)
+	assert.NoError(t, err)
+
+	key := func(id string) DynamodbKey {
+		return DynamodbKey{Hash: func() (string, interface{}) { return "ID", id }}
+	}
+
+	t.Run("Put omits zero", func(t *testing.T) {
+		var items []SparseTask
+		assert.NoError(t, dynamo.ScanSparseIndex(name, "Pending-index", &items))
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, pending.Id, items[0].Id)
+		}
+	})
+
+	t.Run("SetSparse and ClearSparse", func(t *testing.T) {
+		assert.NoError(t, dynamo.SetSparse(name, key(done.Id), "Pending", 1))
+		assert.NoError(t, dynamo.ClearSparse(name, key(pending.Id), "Pending"))
+
+		var items []SparseTask
+		assert.NoError(t, dynamo.ScanSparseIndex(name, "Pending-index", &items))
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, done.Id, items[0].Id)
+		}
+
+		var item SparseTask
+		assert.NoError(t, dynamo.Get(name, key(pending.Id), &item))
+		assert.Equal(t, "pending", item.Name)
+	})
+
+	t.Run("Failure: item not exists", func(t *testing.T) {
+		assert.Error(t, dynamo.SetSparse(name, key(faker.UUIDDigit()), "Pending", 1))
+		assert.Error(t, dynamo.ClearSparse(name, key(faker.UUIDDigit()), "Pending"))
+	})
+}
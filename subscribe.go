@@ -0,0 +1,371 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/guregu/dynamo"
+)
+
+// ErrStreamDisabled is returned by Subscribe for a table without a stream.
+var ErrStreamDisabled = errors.New("stream disabled")
+
+// DefaultSubscribePollInterval is the wait between reads of a shard without new records
+// and between listings of the shards of a stream.
+const DefaultSubscribePollInterval = time.Second
+
+// ChangeType :
+type ChangeType string
+
+// Change types
+const (
+	ChangeInsert ChangeType = "INSERT"
+	ChangeModify ChangeType = "MODIFY"
+	ChangeRemove ChangeType = "REMOVE"
+)
+
+// ChangeEvent is one item change read from a table stream.
+// The images present depend on the stream view of the table.
+type ChangeEvent struct {
+	Type           ChangeType
+	Table          string
+	Keys           map[string]*awsDynamodb.AttributeValue
+	NewImage       map[string]*awsDynamodb.AttributeValue
+	OldImage       map[string]*awsDynamodb.AttributeValue
+	SequenceNumber string
+	Time           time.Time
+}
+
+// UnmarshalNew unmarshals the item after the change into out.
+func (e ChangeEvent) UnmarshalNew(out interface{}) error {
+	return dynamo.UnmarshalItem(e.NewImage, out)
+}
+
+// UnmarshalOld unmarshals the item before the change into out.
+func (e ChangeEvent) UnmarshalOld(out interface{}) error {
+	return dynamo.UnmarshalItem(e.OldImage, out)
+}
+
+// ChangeHandler is called once per change. An error retries the same change,
+// so later changes of the shard wait until it succeeds.
+type ChangeHandler func(ctx context.Context, event ChangeEvent) error
+
+// SubscribeOptions :
+type SubscribeOptions struct {
+	// FromStart also delivers the changes still held by the stream, up to 24 hours old.
+	// By default only changes made after Subscribe are delivered.
+	FromStart    bool
+	PollInterval time.Duration
+	// MaxAttempts of the handler per change, including the first. After that the change is
+	// captured by DynamodbConfig.DeadLetters, or the subscription stops with the handler error
+	// without a sink. Zero retries until stopped.
+	MaxAttempts int
+	// Retry sets the delays between handler attempts.
+	Retry RetryPolicy
+}
+
+// Subscription delivers the changes of a table until stopped or failed.
+type Subscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	once sync.Once
+	err  error
+}
+
+// Stop stops the subscription, waits for running handlers and returns the error it failed with, if any.
+func (s *Subscription) Stop() error {
+	s.cancel()
+	<-s.done
+	return s.err
+}
+
+// Done is closed once the subscription is stopped or failed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error the subscription failed with, after Done is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+func (s *Subscription) fail(err error) {
+	s.once.Do(func() {
+		s.err = err
+		s.cancel()
+	})
+}
+
+// Subscribe calls handler for every change of the table, read from its stream.
+// Changes of one hash key are delivered in order, one at a time: records of a shard
+// are handled sequentially and a shard starts once its parent shard is done.
+// Changes are delivered at least once, so handler should be idempotent.
+func (con *dynamodb) Subscribe(tableName string, handler ChangeHandler) (*Subscription, error) {
+	return con.SubscribeWithOptions(tableName, handler, SubscribeOptions{})
+}
+
+func (con *dynamodb) SubscribeWithOptions(tableName string, handler ChangeHandler, opts SubscribeOptions) (*Subscription, error) {
+	desc, err := con.table(tableName).Describe().Run()
+	if err != nil {
+		return nil, err
+	}
+	if !desc.StreamEnabled || len(desc.LatestStreamARN) < 1 {
+		return nil, fmt.Errorf("%w: %s", ErrStreamDisabled, tableName)
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultSubscribePollInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &Subscription{cancel: cancel, done: make(chan struct{})}
+	s := &subscriber{
+		con:      con,
+		table:    tableName,
+		arn:      desc.LatestStreamARN,
+		handler:  handler,
+		opts:     opts,
+		sub:      sub,
+		started:  map[string]bool{},
+		finished: map[string]bool{},
+	}
+
+	// The first listing decides where each shard starts, so it is done before returning
+	// and changes made after Subscribe returns are never missed.
+	shards, err := s.shards(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s.start(ctx, shards, true)
+
+	go func() {
+		defer close(sub.done)
+		s.run(ctx)
+		s.wg.Wait()
+	}()
+	return sub, nil
+}
+
+type subscriber struct {
+	con     *dynamodb
+	table   string
+	arn     string
+	handler ChangeHandler
+	opts    SubscribeOptions
+	sub     *Subscription
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	started  map[string]bool
+	finished map[string]bool
+}
+
+// run lists the shards of the stream, which split and roll over about every four hours.
+func (s *subscriber) run(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(s.opts.PollInterval):
+		case <-ctx.Done():
+			return
+		}
+
+		shards, err := s.shards(ctx)
+		if err != nil {
+			if ctx.Err() == nil && !IsRetryable(err) {
+				s.sub.fail(err)
+			}
+			continue
+		}
+		s.start(ctx, shards, false)
+	}
+}
+
+func (s *subscriber) shards(ctx context.Context) ([]*dynamodbstreams.Shard, error) {
+	var shards []*dynamodbstreams.Shard
+	input := &dynamodbstreams.DescribeStreamInput{StreamArn: aws.String(s.arn)}
+	for {
+		out, err := s.con.streams.DescribeStreamWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		shards = append(shards, out.StreamDescription.Shards...)
+		if out.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+		input.ExclusiveStartShardId = out.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+// start reads the shards not started yet whose parent is done. On the first listing,
+// unless FromStart is set, closed shards are skipped and open shards start at their end.
+func (s *subscriber) start(ctx context.Context, shards []*dynamodbstreams.Shard, first bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	listed := map[string]bool{}
+	for _, shard := range shards {
+		listed[aws.StringValue(shard.ShardId)] = true
+	}
+
+	for _, shard := range shards {
+		id := aws.StringValue(shard.ShardId)
+		if s.started[id] {
+			continue
+		}
+
+		if first && !s.opts.FromStart {
+			s.started[id] = true
+			if shard.SequenceNumberRange.EndingSequenceNumber != nil {
+				s.finished[id] = true
+				continue
+			}
+			s.read(ctx, id, dynamodbstreams.ShardIteratorTypeLatest)
+			continue
+		}
+
+		// A parent trimmed from the stream is done.
+		if parent := aws.StringValue(shard.ParentShardId); listed[parent] && !s.finished[parent] {
+			continue
+		}
+		s.started[id] = true
+		s.read(ctx, id, dynamodbstreams.ShardIteratorTypeTrimHorizon)
+	}
+}
+
+func (s *subscriber) read(ctx context.Context, shard, iteratorType string) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := s.readShard(ctx, shard, iteratorType)
+		if err == nil {
+			s.mu.Lock()
+			s.finished[shard] = true
+			s.mu.Unlock()
+			return
+		}
+		if ctx.Err() == nil {
+			s.sub.fail(err)
+		}
+	}()
+}
+
+// readShard delivers the records of shard until it is closed and read to the end.
+func (s *subscriber) readShard(ctx context.Context, shard, iteratorType string) error {
+	var last string
+	iterator, err := s.iterator(ctx, shard, iteratorType, last)
+	if err != nil {
+		return err
+	}
+
+	for retry := 0; iterator != nil; {
+		out, err := s.con.streams.GetRecordsWithContext(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: iterator})
+		if isExpiredIterator(err) {
+			if iterator, err = s.iterator(ctx, shard, iteratorType, last); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			if !IsRetryable(err) {
+				return err
+			}
+			if err := sleep(ctx, s.opts.Retry.Delay(retry)); err != nil {
+				return err
+			}
+			retry++
+			continue
+		}
+		retry = 0
+
+		for _, record := range out.Records {
+			if err := s.deliver(ctx, s.event(record)); err != nil {
+				return err
+			}
+			last = aws.StringValue(record.Dynamodb.SequenceNumber)
+		}
+
+		iterator = out.NextShardIterator
+		if iterator != nil && len(out.Records) < 1 {
+			if err := sleep(ctx, s.opts.PollInterval); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// iterator starts after the last delivered record, if any.
+func (s *subscriber) iterator(ctx context.Context, shard, iteratorType, last string) (*string, error) {
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(s.arn),
+		ShardId:           aws.String(shard),
+		ShardIteratorType: aws.String(iteratorType),
+	}
+	if len(last) > 0 {
+		input.ShardIteratorType = aws.String(dynamodbstreams.ShardIteratorTypeAfterSequenceNumber)
+		input.SequenceNumber = aws.String(last)
+	}
+
+	out, err := s.con.streams.GetShardIteratorWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return out.ShardIterator, nil
+}
+
+func (s *subscriber) deliver(ctx context.Context, event ChangeEvent) error {
+	for attempt := 1; ; attempt++ {
+		err := s.handler(ctx, event)
+		if err == nil {
+			return nil
+		}
+		if s.opts.MaxAttempts > 0 && attempt >= s.opts.MaxAttempts {
+			item := event.NewImage
+			if item == nil {
+				item = event.Keys
+			}
+			return s.con.deadLetter(ctx, "Subscribe", s.table, item, fmt.Errorf("change %s: %w", event.SequenceNumber, err))
+		}
+		if err := sleep(ctx, s.opts.Retry.Delay(attempt-1)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *subscriber) event(record *dynamodbstreams.Record) ChangeEvent {
+	change := record.Dynamodb
+	return ChangeEvent{
+		Type:           ChangeType(aws.StringValue(record.EventName)),
+		Table:          s.table,
+		Keys:           change.Keys,
+		NewImage:       change.NewImage,
+		OldImage:       change.OldImage,
+		SequenceNumber: aws.StringValue(change.SequenceNumber),
+		Time:           aws.TimeValue(change.ApproximateCreationDateTime),
+	}
+}
+
+func isExpiredIterator(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == dynamodbstreams.ErrCodeExpiredIteratorException
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
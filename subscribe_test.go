@@ -0,0 +1,108 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "subscribe-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.ApplyTableSpec(ctx, TableSpec{
+		Name:     name,
+		HashKey:  "ID",
+		OnDemand: true,
+		Stream:   DynamodbStreamNewAndOldImages,
+	}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	opts := SubscribeOptions{PollInterval: 100 * time.Millisecond}
+
+	t.Run("Success", func(t *testing.T) {
+		events := make(chan ChangeEvent, 10)
+		sub, err := dynamo.SubscribeWithOptions(name, func(ctx context.Context, event ChangeEvent) error {
+			events <- event
+			return nil
+		}, opts)
+		assert.NoError(t, err)
+
+		item := HashOnly{Id: faker.UUIDDigit(), Name: "first"}
+		_, err = dynamo.Put(name, item)
+		assert.NoError(t, err)
+		item.Name = "second"
+		_, err = dynamo.Put(name, item)
+		assert.NoError(t, err)
+
+		for _, want := range []ChangeType{ChangeInsert, ChangeModify} {
+			select {
+			case event := <-events:
+				assert.Equal(t, want, event.Type)
+				assert.Equal(t, name, event.Table)
+			case <-time.After(10 * time.Second):
+				t.Fatal("no change received")
+			}
+		}
+
+		assert.NoError(t, sub.Stop())
+	})
+
+	t.Run("Success: retries the handler", func(t *testing.T) {
+		var calls int32
+		received := make(chan HashOnly, 1)
+		sub, err := dynamo.SubscribeWithOptions(name, func(ctx context.Context, event ChangeEvent) error {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return errors.New("not yet")
+			}
+			var item HashOnly
+			assert.NoError(t, event.UnmarshalNew(&item))
+			received <- item
+			return nil
+		}, opts)
+		assert.NoError(t, err)
+
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err = dynamo.Put(name, item)
+		assert.NoError(t, err)
+
+		select {
+		case got := <-received:
+			assert.Equal(t, item.Name, got.Name)
+		case <-time.After(10 * time.Second):
+			t.Fatal("no change received")
+		}
+
+		assert.NoError(t, sub.Stop())
+	})
+
+	t.Run("Failure: handler gives up", func(t *testing.T) {
+		sub, err := dynamo.SubscribeWithOptions(name, func(ctx context.Context, event ChangeEvent) error {
+			return errors.New("broken")
+		}, SubscribeOptions{PollInterval: opts.PollInterval, MaxAttempts: 2})
+		assert.NoError(t, err)
+
+		_, err = dynamo.Put(name, HashOnly{Id: faker.UUIDDigit()})
+		assert.NoError(t, err)
+
+		select {
+		case <-sub.Done():
+			assert.Error(t, sub.Err())
+		case <-time.After(10 * time.Second):
+			t.Fatal("subscription did not fail")
+		}
+	})
+
+	t.Run("Failure: stream disabled", func(t *testing.T) {
+		_, err := dynamo.Subscribe(tableNameHashOnly, func(ctx context.Context, event ChangeEvent) error {
+			return nil
+		})
+		assert.True(t, errors.Is(err, ErrStreamDisabled))
+	})
+}
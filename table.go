@@ -0,0 +1,342 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// ErrTableNotFound is returned by DeleteTableWithContext when the table does not exist.
+var ErrTableNotFound = errors.New("table not found")
+
+// DynamodbKeyType is the attribute type of a table or index key.
+type DynamodbKeyType string
+
+// Key types
+const (
+	DynamodbKeyTypeString DynamodbKeyType = "S"
+	DynamodbKeyTypeNumber DynamodbKeyType = "N"
+	DynamodbKeyTypeBinary DynamodbKeyType = "B"
+)
+
+// DynamodbProjection determines which attributes are copied into an index.
+type DynamodbProjection string
+
+// Projection
+const (
+	DynamodbProjectionAll      DynamodbProjection = "ALL"
+	DynamodbProjectionKeysOnly DynamodbProjection = "KEYS_ONLY"
+	DynamodbProjectionInclude  DynamodbProjection = "INCLUDE"
+)
+
+// IndexDefinition describes a secondary index.
+// Key types default to string and the projection defaults to all attributes.
+// A definition without HashKey only overrides the projection of an index
+// already declared by struct tags on the entity.
+type IndexDefinition struct {
+	Name             string             `yaml:"name"`
+	HashKey          string             `yaml:"hashKey"`
+	HashKeyType      DynamodbKeyType    `yaml:"hashKeyType"`
+	RangeKey         string             `yaml:"rangeKey"`
+	RangeKeyType     DynamodbKeyType    `yaml:"rangeKeyType"`
+	Projection       DynamodbProjection `yaml:"projection"`
+	NonKeyAttributes []string           `yaml:"nonKeyAttributes"`
+
+	// Provisioned throughput, global secondary indexes only.
+	ReadUnits  int64 `yaml:"readUnits"`
+	WriteUnits int64 `yaml:"writeUnits"`
+}
+
+// CreateTableOptions :
+type CreateTableOptions struct {
+	OnDemand   bool
+	ReadUnits  int64
+	WriteUnits int64
+	GSIs       []IndexDefinition
+	LSIs       []IndexDefinition
+	// Wait blocks until the table is active.
+	Wait bool
+}
+
+// TableExists reports whether the table exists, in any status.
+func (con *dynamodb) TableExists(ctx context.Context, name string) (bool, error) {
+	_, err := con.table(name).Describe().RunWithContext(ctx)
+	if isTableNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListTables returns the names of all tables, without DynamodbConfig.TablePrefix. Tables of other
+// prefixes are left out.
+func (con *dynamodb) ListTables(ctx context.Context) ([]string, error) {
+	return con.ListTablesWithPrefix(ctx, "")
+}
+
+// ListTablesWithPrefix returns the names of the tables starting with prefix, in ascending order.
+func (con *dynamodb) ListTablesWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	full := con.tableName(prefix)
+	names := []string{}
+	err := con.db.Client().ListTablesPagesWithContext(ctx, &awsDynamodb.ListTablesInput{}, func(page *awsDynamodb.ListTablesOutput, last bool) bool {
+		for _, name := range aws.StringValueSlice(page.TableNames) {
+			if strings.HasPrefix(name, full) {
+				names = append(names, strings.TrimPrefix(name, con.config.TablePrefix))
+			} else if name > full {
+				// Names are listed in ascending order, so no later name has the prefix.
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Ping checks that DynamoDB is reachable with the credentials of the client, such as for readiness
+// probes, by listing at most one table.
+func (con *dynamodb) Ping(ctx context.Context) error {
+	_, err := con.db.Client().ListTablesWithContext(ctx, &awsDynamodb.ListTablesInput{Limit: aws.Int64(1)})
+	return err
+}
+
+func isTableNotFound(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == awsDynamodb.ErrCodeResourceNotFoundException
+}
+
+// Deprecated: use CreateTableWithContext.
+func (con *dynamodb) CreateTableWithOptions(name string, entity interface{}, options CreateTableOptions) error {
+	return con.CreateTableWithContext(context.Background(), name, entity, options)
+}
+
+func (con *dynamodb) CreateTableWithContext(ctx context.Context, name string, entity interface{}, options CreateTableOptions) error {
+	req := con.db.CreateTable(con.tableName(name), entity).OnDemand(options.OnDemand)
+	if options.ReadUnits > 0 || options.WriteUnits > 0 {
+		req.Provision(options.ReadUnits, options.WriteUnits)
+	}
+
+	for _, index := range options.LSIs {
+		addIndex(req, index, true)
+	}
+	for _, index := range options.GSIs {
+		addIndex(req, index, false)
+	}
+
+	release, err := con.guardTableCreate()
+	if err != nil {
+		return err
+	}
+
+	if err := req.RunWithContext(ctx); err != nil {
+		release()
+		return err
+	}
+
+	con.emit(TableEvent{Type: TableCreated, Table: name})
+
+	if options.Wait {
+		return con.WaitUntilTableActive(ctx, name)
+	}
+
+	return nil
+}
+
+func addIndex(req *dynamo.CreateTable, index IndexDefinition, local bool) {
+	if len(index.HashKey) < 1 {
+		projection := index.Projection
+		if len(projection) < 1 {
+			projection = DynamodbProjectionAll
+		}
+		req.Project(index.Name, dynamo.IndexProjection(projection), index.NonKeyAttributes...)
+		return
+	}
+
+	req.Index(dynamo.Index{
+		Name:              index.Name,
+		Local:             local,
+		HashKey:           index.HashKey,
+		HashKeyType:       index.HashKeyType.value(),
+		RangeKey:          index.RangeKey,
+		RangeKeyType:      index.RangeKeyType.value(),
+		ProjectionType:    dynamo.IndexProjection(index.Projection),
+		ProjectionAttribs: index.NonKeyAttributes,
+		Throughput: dynamo.Throughput{
+			Read:  index.ReadUnits,
+			Write: index.WriteUnits,
+		},
+	})
+}
+
+func (t DynamodbKeyType) value() dynamo.KeyType {
+	if len(t) < 1 {
+		return dynamo.StringType
+	}
+	return dynamo.KeyType(t)
+}
+
+// TableStatus :
+type TableStatus string
+
+// Table status
+const (
+	TableStatusActive   TableStatus = "ACTIVE"
+	TableStatusCreating TableStatus = "CREATING"
+	TableStatusUpdating TableStatus = "UPDATING"
+	TableStatusDeleting TableStatus = "DELETING"
+)
+
+// TableDescription is the metadata of a table.
+// Items and SizeBytes are updated by DynamoDB about every six hours.
+type TableDescription struct {
+	Name         string
+	Status       TableStatus
+	CreatedAt    time.Time
+	Items        int64
+	SizeBytes    int64
+	HashKey      string
+	HashKeyType  DynamodbKeyType
+	RangeKey     string
+	RangeKeyType DynamodbKeyType
+	OnDemand     bool
+	ReadUnits    int64
+	WriteUnits   int64
+	GSIs         []IndexDefinition
+	LSIs         []IndexDefinition
+	StreamARN    string
+}
+
+func (con *dynamodb) DescribeTable(name string) (*TableDescription, error) {
+	desc, err := con.table(name).Describe().Run()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &TableDescription{
+		Name:         name,
+		Status:       TableStatus(desc.Status),
+		CreatedAt:    desc.Created,
+		Items:        desc.Items,
+		SizeBytes:    desc.Size,
+		HashKey:      desc.HashKey,
+		HashKeyType:  DynamodbKeyType(desc.HashKeyType),
+		RangeKey:     desc.RangeKey,
+		RangeKeyType: DynamodbKeyType(desc.RangeKeyType),
+		OnDemand:     desc.OnDemand,
+		ReadUnits:    desc.Throughput.Read,
+		WriteUnits:   desc.Throughput.Write,
+	}
+	if desc.StreamEnabled {
+		table.StreamARN = desc.LatestStreamARN
+	}
+
+	for _, index := range desc.GSI {
+		table.GSIs = append(table.GSIs, indexDefinition(index))
+	}
+	for _, index := range desc.LSI {
+		definition := indexDefinition(index)
+		definition.ReadUnits, definition.WriteUnits = 0, 0
+		table.LSIs = append(table.LSIs, definition)
+	}
+
+	return table, nil
+}
+
+func indexDefinition(index dynamo.Index) IndexDefinition {
+	return IndexDefinition{
+		Name:             index.Name,
+		HashKey:          index.HashKey,
+		HashKeyType:      DynamodbKeyType(index.HashKeyType),
+		RangeKey:         index.RangeKey,
+		RangeKeyType:     DynamodbKeyType(index.RangeKeyType),
+		Projection:       DynamodbProjection(index.ProjectionType),
+		NonKeyAttributes: index.ProjectionAttribs,
+		ReadUnits:        index.Throughput.Read,
+		WriteUnits:       index.Throughput.Write,
+	}
+}
+
+func (con *dynamodb) DeleteTableWithContext(ctx context.Context, name string) error {
+	if err := con.table(name).DeleteTable().RunWithContext(ctx); err != nil {
+		if isTableNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrTableNotFound, name)
+		}
+		return err
+	}
+
+	con.emit(TableEvent{Type: TableDeleted, Table: name})
+	return nil
+}
+
+// DeleteTableOptions :
+type DeleteTableOptions struct {
+	// Wait blocks until the table is gone, including when another caller was deleting it.
+	Wait bool
+}
+
+// DeleteTableIfExists deletes the table, succeeding when it does not exist or is already being deleted,
+// for idempotent teardowns. A table being created or updated is waited for until ACTIVE, then deleted.
+func (con *dynamodb) DeleteTableIfExists(ctx context.Context, name string, options DeleteTableOptions) error {
+	for {
+		err := con.DeleteTableWithContext(ctx, name)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrTableNotFound) {
+			return nil
+		}
+		if !isResourceInUse(err) {
+			return err
+		}
+
+		desc, err := con.table(name).Describe().RunWithContext(ctx)
+		if isTableNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if TableStatus(desc.Status) == TableStatusDeleting {
+			break
+		}
+		if err := con.waitTableActive(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	if options.Wait {
+		return con.WaitUntilTableDeleted(ctx, name)
+	}
+	return nil
+}
+
+// isResourceInUse reports a table being created, updated or already being deleted.
+func isResourceInUse(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == awsDynamodb.ErrCodeResourceInUseException
+}
+
+func (con *dynamodb) WaitUntilTableActive(ctx context.Context, name string) error {
+	return con.waitTableActive(ctx, name)
+}
+
+func (con *dynamodb) waitTableActive(ctx context.Context, name string, opts ...request.WaiterOption) error {
+	return con.db.Client().WaitUntilTableExistsWithContext(ctx, &awsDynamodb.DescribeTableInput{
+		TableName: aws.String(con.tableName(name)),
+	}, opts...)
+}
+
+func (con *dynamodb) WaitUntilTableDeleted(ctx context.Context, name string) error {
+	return con.db.Client().WaitUntilTableNotExistsWithContext(ctx, &awsDynamodb.DescribeTableInput{
+		TableName: aws.String(con.tableName(name)),
+	})
+}
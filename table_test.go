@@ -0,0 +1,245 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type WithIndex struct {
+	Id        string `dynamo:"ID,hash"`
+	CreatedAt string `dynamo:"CreatedAt,range"`
+	Name      string `dynamo:"Name"`
+	Status    int    `dynamo:"Status"`
+}
+
+func TestCreateTableWithOptions(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	t.Run("Success", func(t *testing.T) {
+		name := "with-options-" + faker.UUIDDigit()
+
+		err := dynamo.CreateTableWithOptions(name, WithIndex{}, CreateTableOptions{
+			ReadUnits:  2,
+			WriteUnits: 2,
+			GSIs: []IndexDefinition{
+				{
+					Name:        "Status-index",
+					HashKey:     "Status",
+					HashKeyType: DynamodbKeyTypeNumber,
+					RangeKey:    "CreatedAt",
+					Projection:  DynamodbProjectionKeysOnly,
+					ReadUnits:   1,
+					WriteUnits:  1,
+				},
+			},
+			LSIs: []IndexDefinition{
+				{
+					Name:             "ID-Name-index",
+					HashKey:          "ID",
+					RangeKey:         "Name",
+					Projection:       DynamodbProjectionInclude,
+					NonKeyAttributes: []string{"Status"},
+				},
+			},
+			Wait: true,
+		})
+		assert.NoError(t, err)
+		assert.True(t, dynamo.ExistsTable(name))
+
+		desc, err := dynamo.DescribeTable(name)
+		assert.NoError(t, err)
+		assert.Equal(t, TableStatusActive, desc.Status)
+		assert.Equal(t, "ID", desc.HashKey)
+		assert.Equal(t, "CreatedAt", desc.RangeKey)
+		assert.Equal(t, int64(2), desc.ReadUnits)
+		assert.Len(t, desc.GSIs, 1)
+		assert.Equal(t, DynamodbKeyTypeNumber, desc.GSIs[0].HashKeyType)
+		assert.Equal(t, DynamodbProjectionKeysOnly, desc.GSIs[0].Projection)
+		assert.Len(t, desc.LSIs, 1)
+		assert.Equal(t, []string{"Status"}, desc.LSIs[0].NonKeyAttributes)
+
+		assert.NoError(t, dynamo.DeleteTable(name))
+		assert.NoError(t, dynamo.WaitUntilTableDeleted(context.Background(), name))
+		assert.False(t, dynamo.ExistsTable(name))
+	})
+
+	t.Run("Failure: unknown index projection", func(t *testing.T) {
+		name := "with-options-" + faker.UUIDDigit()
+
+		err := dynamo.CreateTableWithOptions(name, WithIndex{}, CreateTableOptions{
+			LSIs: []IndexDefinition{
+				{Name: "not-declared", Projection: DynamodbProjectionKeysOnly},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestTableExists(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		name := "exists-" + faker.UUIDDigit()
+
+		exists, err := dynamo.TableExists(ctx, name)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+
+		assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}))
+		exists, err = dynamo.TableExists(ctx, name)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		assert.NoError(t, dynamo.DeleteTableWithContext(ctx, name))
+		assert.NoError(t, dynamo.WaitUntilTableDeleted(ctx, name))
+	})
+
+	t.Run("Failure: canceled", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		exists, err := dynamo.TableExists(canceled, tableNameHashOnly)
+		assert.Error(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestListTables(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	prefix := "list-" + faker.UUIDDigit() + "-"
+	names := []string{prefix + "a", prefix + "b"}
+	for _, name := range names {
+		assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true}))
+		defer dynamo.DeleteTableWithContext(ctx, name)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		all, err := dynamo.ListTables(ctx)
+		assert.NoError(t, err)
+		assert.Subset(t, all, append(names, tableNameHashOnly))
+	})
+
+	t.Run("Success: prefix", func(t *testing.T) {
+		listed, err := dynamo.ListTablesWithPrefix(ctx, prefix)
+		assert.NoError(t, err)
+		assert.Equal(t, names, listed)
+	})
+
+	t.Run("Success: table prefix", func(t *testing.T) {
+		prefixed := newDynamoWithConfig(t, &DynamodbConfig{TablePrefix: prefix})
+		listed, err := prefixed.ListTables(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, listed)
+	})
+}
+
+func TestPing(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, dynamo.Ping(ctx))
+	})
+
+	t.Run("Failure: unreachable", func(t *testing.T) {
+		unreachable, err := New(session.New(), &DynamodbConfig{Endpoint: "http://localhost:1", Region: "us-east-1", RetryPolicy: &RetryPolicy{MaxAttempts: 1}})
+		assert.NoError(t, err)
+		assert.Error(t, unreachable.Ping(ctx))
+	})
+}
+
+func TestDeleteTableIfExists(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		name := "delete-" + faker.UUIDDigit()
+		assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}))
+
+		assert.NoError(t, dynamo.DeleteTableIfExists(ctx, name, DeleteTableOptions{Wait: true}))
+		exists, err := dynamo.TableExists(ctx, name)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Success: not found", func(t *testing.T) {
+		name := "delete-" + faker.UUIDDigit()
+		assert.NoError(t, dynamo.DeleteTableIfExists(ctx, name, DeleteTableOptions{Wait: true}))
+	})
+
+	t.Run("Success: creating", func(t *testing.T) {
+		name := "delete-" + faker.UUIDDigit()
+		assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true}))
+
+		assert.NoError(t, dynamo.DeleteTableIfExists(ctx, name, DeleteTableOptions{Wait: true}))
+		exists, err := dynamo.TableExists(ctx, name)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Failure: DeleteTableWithContext not found", func(t *testing.T) {
+		name := "delete-" + faker.UUIDDigit()
+		err := dynamo.DeleteTableWithContext(ctx, name)
+		assert.True(t, errors.Is(err, ErrTableNotFound))
+	})
+}
+
+func TestDescribeTable(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	t.Run("Success", func(t *testing.T) {
+		desc, err := dynamo.DescribeTable(tableNameHashOnly)
+		assert.NoError(t, err)
+		assert.Equal(t, tableNameHashOnly, desc.Name)
+		assert.Equal(t, HashOnly{}.HashKey(), desc.HashKey)
+		assert.Equal(t, DynamodbKeyTypeString, desc.HashKeyType)
+		assert.Empty(t, desc.RangeKey)
+	})
+
+	t.Run("Failure: not exists", func(t *testing.T) {
+		_, err := dynamo.DescribeTable("not-exists")
+		assert.Error(t, err)
+	})
+}
+
+func TestTablePrefix(t *testing.T) {
+	ctx := context.Background()
+	raw := newDynamo(t)
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{TablePrefix: "test-"})
+
+	name := "prefixed-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashOnly{}, CreateTableOptions{OnDemand: true, Wait: true}))
+	defer func() {
+		dynamo.DeleteTableWithContext(ctx, name)
+		dynamo.WaitUntilTableDeleted(ctx, name)
+	}()
+
+	exists, err := raw.TableExists(ctx, "test-"+name)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = dynamo.TableExists(ctx, name)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	desc, err := dynamo.DescribeTable(name)
+	assert.NoError(t, err)
+	assert.Equal(t, name, desc.Name)
+
+	item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+	_, err = dynamo.Put(name, item)
+	assert.NoError(t, err)
+
+	var datum HashOnly
+	assert.NoError(t, raw.Get("test-"+name, DynamodbKey{
+		Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+	}, &datum))
+	assert.Equal(t, item, datum)
+}
@@ -0,0 +1,363 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSpecMismatch is returned when a table differs from its spec in a way UpdateTable cannot converge,
+// such as its keys or local secondary indexes.
+var ErrSpecMismatch = errors.New("table does not match spec")
+
+// DynamodbStreamView selects what a table stream records. The empty view disables the stream.
+type DynamodbStreamView string
+
+// Stream views
+const (
+	DynamodbStreamKeysOnly        DynamodbStreamView = "KEYS_ONLY"
+	DynamodbStreamNewImage        DynamodbStreamView = "NEW_IMAGE"
+	DynamodbStreamOldImage        DynamodbStreamView = "OLD_IMAGE"
+	DynamodbStreamNewAndOldImages DynamodbStreamView = "NEW_AND_OLD_IMAGES"
+)
+
+// TableSpec declares a table for ApplyTableSpec. Key types default to string and
+// a provisioned table without units gets one read and one write unit.
+type TableSpec struct {
+	Name         string            `yaml:"name"`
+	HashKey      string            `yaml:"hashKey"`
+	HashKeyType  DynamodbKeyType   `yaml:"hashKeyType"`
+	RangeKey     string            `yaml:"rangeKey"`
+	RangeKeyType DynamodbKeyType   `yaml:"rangeKeyType"`
+	OnDemand     bool              `yaml:"onDemand"`
+	ReadUnits    int64             `yaml:"readUnits"`
+	WriteUnits   int64             `yaml:"writeUnits"`
+	GSIs         []IndexDefinition `yaml:"gsis"`
+	LSIs         []IndexDefinition `yaml:"lsis"`
+	// TTLAttribute enables TTL on this attribute.
+	TTLAttribute string             `yaml:"ttlAttribute"`
+	Stream       DynamodbStreamView `yaml:"stream"`
+}
+
+// ParseTableSpecs reads a YAML or JSON list of table specs:
+//
+//	# tables.yaml
+//	- name: users
+//	  hashKey: ID
+//	  onDemand: true
+//	  gsis:
+//	    - name: Email-index
+//	      hashKey: Email
+//	  ttlAttribute: ExpiresAt
+func ParseTableSpecs(data []byte) ([]TableSpec, error) {
+	var specs []TableSpec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		return nil, err
+	}
+	for i, spec := range specs {
+		if len(spec.Name) < 1 || len(spec.HashKey) < 1 {
+			return nil, fmt.Errorf("table spec %d: name and hashKey are required", i)
+		}
+	}
+	return specs, nil
+}
+
+// ApplyTableSpec creates the table of spec, or converges an existing one through UpdateTable.
+// It is idempotent, so it can run on every startup. Billing, throughput, streams, TTL and
+// missing global secondary indexes are converged; indexes not in spec are kept.
+// Each change waits until the table is active again.
+func (con *dynamodb) ApplyTableSpec(ctx context.Context, spec TableSpec) error {
+	desc, err := con.table(spec.Name).Describe().RunWithContext(ctx)
+	switch {
+	case isTableNotFound(err):
+		err = con.createFromSpec(ctx, spec)
+	case err == nil:
+		err = con.convergeSpec(ctx, spec, desc)
+	}
+	if err != nil {
+		return err
+	}
+
+	return con.applyTTLSpec(spec)
+}
+
+// CreateTableFromSchema creates the table name exactly as declared by spec, whose Name is ignored,
+// for schemas that struct tags cannot express such as projections of non-key attributes.
+// Unlike ApplyTableSpec, it fails when the table exists. It waits until the table is active.
+func (con *dynamodb) CreateTableFromSchema(ctx context.Context, name string, spec TableSpec) error {
+	if len(spec.HashKey) < 1 {
+		return fmt.Errorf("schema of %s has no hash key", name)
+	}
+
+	spec.Name = name
+	if err := con.createFromSpec(ctx, spec); err != nil {
+		return err
+	}
+	return con.applyTTLSpec(spec)
+}
+
+// DescribeTableSpec describes the table as a spec that ApplyTableSpec converges to, which makes
+// changing one part of a table, such as adding an index, a matter of editing the spec.
+func (con *dynamodb) DescribeTableSpec(ctx context.Context, name string) (TableSpec, error) {
+	desc, err := con.table(name).Describe().RunWithContext(ctx)
+	if err != nil {
+		return TableSpec{}, err
+	}
+
+	spec := TableSpec{
+		Name:         name,
+		HashKey:      desc.HashKey,
+		HashKeyType:  DynamodbKeyType(desc.HashKeyType),
+		RangeKey:     desc.RangeKey,
+		RangeKeyType: DynamodbKeyType(desc.RangeKeyType),
+		OnDemand:     desc.OnDemand,
+	}
+	if !desc.OnDemand {
+		spec.ReadUnits, spec.WriteUnits = desc.Throughput.Read, desc.Throughput.Write
+	}
+	if desc.StreamEnabled {
+		spec.Stream = DynamodbStreamView(desc.StreamView)
+	}
+	for _, index := range desc.GSI {
+		spec.GSIs = append(spec.GSIs, indexDefinition(index))
+	}
+	for _, index := range desc.LSI {
+		definition := indexDefinition(index)
+		definition.ReadUnits, definition.WriteUnits = 0, 0
+		spec.LSIs = append(spec.LSIs, definition)
+	}
+
+	ttl, err := con.DescribeTTL(name)
+	if err != nil {
+		return TableSpec{}, err
+	}
+	if ttl.Enabled() || ttl.Status == TTLStatusEnabling {
+		spec.TTLAttribute = ttl.Attribute
+	}
+	return spec, nil
+}
+
+func (con *dynamodb) createFromSpec(ctx context.Context, spec TableSpec) error {
+	input := &awsDynamodb.CreateTableInput{
+		TableName: aws.String(con.tableName(spec.Name)),
+		KeySchema: keySchema(spec.HashKey, spec.RangeKey),
+	}
+
+	attributes := map[string]DynamodbKeyType{}
+	addAttribute := func(name string, typ DynamodbKeyType) {
+		if len(name) > 0 {
+			attributes[name] = DynamodbKeyType(typ.value())
+		}
+	}
+	addAttribute(spec.HashKey, spec.HashKeyType)
+	addAttribute(spec.RangeKey, spec.RangeKeyType)
+
+	if spec.OnDemand {
+		input.BillingMode = aws.String(awsDynamodb.BillingModePayPerRequest)
+	} else {
+		input.ProvisionedThroughput = spec.throughput(spec.ReadUnits, spec.WriteUnits)
+	}
+
+	for _, index := range spec.GSIs {
+		addAttribute(index.HashKey, index.HashKeyType)
+		addAttribute(index.RangeKey, index.RangeKeyType)
+
+		gsi := &awsDynamodb.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(index.HashKey, index.RangeKey),
+			Projection: projection(index),
+		}
+		if !spec.OnDemand {
+			gsi.ProvisionedThroughput = spec.throughput(index.ReadUnits, index.WriteUnits)
+		}
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, gsi)
+	}
+	for _, index := range spec.LSIs {
+		addAttribute(index.RangeKey, index.RangeKeyType)
+
+		input.LocalSecondaryIndexes = append(input.LocalSecondaryIndexes, &awsDynamodb.LocalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(spec.HashKey, index.RangeKey),
+			Projection: projection(index),
+		})
+	}
+
+	for name, typ := range attributes {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, &awsDynamodb.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: aws.String(string(typ)),
+		})
+	}
+
+	if len(spec.Stream) > 0 {
+		input.StreamSpecification = &awsDynamodb.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String(string(spec.Stream)),
+		}
+	}
+
+	release, err := con.guardTableCreate()
+	if err != nil {
+		return err
+	}
+
+	if _, err := con.db.Client().CreateTableWithContext(ctx, input); err != nil {
+		release()
+		return err
+	}
+
+	con.emit(TableEvent{Type: TableCreated, Table: spec.Name})
+	return con.WaitUntilTableActive(ctx, spec.Name)
+}
+
+func (con *dynamodb) convergeSpec(ctx context.Context, spec TableSpec, desc dynamo.Description) error {
+	if desc.HashKey != spec.HashKey || desc.RangeKey != spec.RangeKey {
+		return fmt.Errorf("%w: %s has keys %s, %s", ErrSpecMismatch, spec.Name, desc.HashKey, desc.RangeKey)
+	}
+
+	lsis := map[string]bool{}
+	for _, index := range desc.LSI {
+		lsis[index.Name] = true
+	}
+	for _, index := range spec.LSIs {
+		if !lsis[index.Name] {
+			return fmt.Errorf("%w: local secondary index %s can only be added on creation", ErrSpecMismatch, index.Name)
+		}
+	}
+
+	// DynamoDB takes one stream change per update, so a different view disables the stream first.
+	stream := desc.StreamEnabled && desc.StreamView != dynamo.StreamView(spec.Stream)
+	if stream {
+		if err := con.updateFromSpec(ctx, spec, TableUpdated, "", func(req *dynamo.UpdateTable) {
+			req.DisableStream()
+		}); err != nil {
+			return err
+		}
+	}
+	if (stream || !desc.StreamEnabled) && len(spec.Stream) > 0 {
+		if err := con.updateFromSpec(ctx, spec, TableUpdated, "", func(req *dynamo.UpdateTable) {
+			req.Stream(dynamo.StreamView(spec.Stream))
+		}); err != nil {
+			return err
+		}
+	}
+
+	read, write := spec.units(spec.ReadUnits, spec.WriteUnits)
+	if desc.OnDemand != spec.OnDemand || !spec.OnDemand && (desc.Throughput.Read != read || desc.Throughput.Write != write) {
+		if err := con.updateFromSpec(ctx, spec, TableUpdated, "", func(req *dynamo.UpdateTable) {
+			req.OnDemand(spec.OnDemand)
+			if !spec.OnDemand {
+				req.Provision(read, write)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+
+	gsis := map[string]bool{}
+	for _, index := range desc.GSI {
+		gsis[index.Name] = true
+	}
+	for _, index := range spec.GSIs {
+		if gsis[index.Name] {
+			continue
+		}
+
+		if err := con.updateFromSpec(ctx, spec, IndexAdded, index.Name, func(req *dynamo.UpdateTable) {
+			req.CreateIndex(gsi(index, spec.OnDemand))
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateFromSpec runs one UpdateTable, emits event and waits until the table is active.
+func (con *dynamodb) updateFromSpec(ctx context.Context, spec TableSpec, event TableEventType, index string, build func(req *dynamo.UpdateTable)) error {
+	req := con.table(spec.Name).UpdateTable()
+	build(req)
+	if _, err := req.RunWithContext(ctx); err != nil {
+		return err
+	}
+
+	con.emit(TableEvent{Type: event, Table: spec.Name, Index: index})
+	if err := con.WaitUntilTableActive(ctx, spec.Name); err != nil {
+		return err
+	}
+	if len(index) > 0 {
+		return con.WaitUntilIndexActive(ctx, spec.Name, index)
+	}
+	return nil
+}
+
+func (con *dynamodb) applyTTLSpec(spec TableSpec) error {
+	if len(spec.TTLAttribute) < 1 {
+		return nil
+	}
+
+	ttl, err := con.DescribeTTL(spec.Name)
+	if err != nil {
+		return err
+	}
+	if ttl.Attribute == spec.TTLAttribute && (ttl.Enabled() || ttl.Status == TTLStatusEnabling) {
+		return nil
+	}
+	if len(ttl.Attribute) > 0 && ttl.Attribute != spec.TTLAttribute {
+		return fmt.Errorf("%w: %s has TTL on %s", ErrSpecMismatch, spec.Name, ttl.Attribute)
+	}
+	return con.EnableTTL(spec.Name, spec.TTLAttribute)
+}
+
+func (spec TableSpec) units(read, write int64) (int64, int64) {
+	if spec.OnDemand {
+		return 0, 0
+	}
+	if read < 1 {
+		read = 1
+	}
+	if write < 1 {
+		write = 1
+	}
+	return read, write
+}
+
+func (spec TableSpec) throughput(read, write int64) *awsDynamodb.ProvisionedThroughput {
+	read, write = spec.units(read, write)
+	return &awsDynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(read),
+		WriteCapacityUnits: aws.Int64(write),
+	}
+}
+
+func keySchema(hashKey, rangeKey string) []*awsDynamodb.KeySchemaElement {
+	schema := []*awsDynamodb.KeySchemaElement{{
+		AttributeName: aws.String(hashKey),
+		KeyType:       aws.String(awsDynamodb.KeyTypeHash),
+	}}
+	if len(rangeKey) > 0 {
+		schema = append(schema, &awsDynamodb.KeySchemaElement{
+			AttributeName: aws.String(rangeKey),
+			KeyType:       aws.String(awsDynamodb.KeyTypeRange),
+		})
+	}
+	return schema
+}
+
+func projection(index IndexDefinition) *awsDynamodb.Projection {
+	typ := index.Projection
+	if len(typ) < 1 {
+		typ = DynamodbProjectionAll
+	}
+
+	projection := &awsDynamodb.Projection{ProjectionType: aws.String(string(typ))}
+	if typ == DynamodbProjectionInclude {
+		projection.NonKeyAttributes = aws.StringSlice(index.NonKeyAttributes)
+	}
+	return projection
+}
@@ -0,0 +1,149 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTableSpecs(t *testing.T) {
+	t.Run("Success: yaml", func(t *testing.T) {
+		specs, err := ParseTableSpecs([]byte(`
+- name: users
+  hashKey: ID
+  rangeKey: CreatedAt
+  onDemand: true
+  gsis:
+    - name: Status-index
+      hashKey: Status
+      hashKeyType: N
+      projection: KEYS_ONLY
+  ttlAttribute: ExpiresAt
+  stream: NEW_IMAGE
+`))
+		assert.NoError(t, err)
+		assert.Len(t, specs, 1)
+		assert.Equal(t, "users", specs[0].Name)
+		assert.Equal(t, "CreatedAt", specs[0].RangeKey)
+		assert.True(t, specs[0].OnDemand)
+		assert.Equal(t, DynamodbKeyTypeNumber, specs[0].GSIs[0].HashKeyType)
+		assert.Equal(t, DynamodbProjectionKeysOnly, specs[0].GSIs[0].Projection)
+		assert.Equal(t, "ExpiresAt", specs[0].TTLAttribute)
+		assert.Equal(t, DynamodbStreamNewImage, specs[0].Stream)
+	})
+
+	t.Run("Success: json", func(t *testing.T) {
+		specs, err := ParseTableSpecs([]byte(`[{"name": "users", "hashKey": "ID", "readUnits": 2}]`))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), specs[0].ReadUnits)
+	})
+
+	t.Run("Failure: no hash key", func(t *testing.T) {
+		_, err := ParseTableSpecs([]byte(`[{"name": "users"}]`))
+		assert.Error(t, err)
+	})
+}
+
+func TestApplyTableSpec(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	spec := TableSpec{
+		Name:     "spec-" + faker.UUIDDigit(),
+		HashKey:  "ID",
+		RangeKey: "CreatedAt",
+		OnDemand: true,
+		LSIs: []IndexDefinition{
+			{Name: "ID-Name-index", RangeKey: "Name"},
+		},
+		TTLAttribute: "ExpiresAt",
+	}
+	defer dynamo.DeleteTableWithContext(ctx, spec.Name)
+
+	t.Run("Success: create", func(t *testing.T) {
+		assert.NoError(t, dynamo.ApplyTableSpec(ctx, spec))
+
+		desc, err := dynamo.DescribeTable(spec.Name)
+		assert.NoError(t, err)
+		assert.True(t, desc.OnDemand)
+		assert.Len(t, desc.LSIs, 1)
+
+		ttl, err := dynamo.DescribeTTL(spec.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, "ExpiresAt", ttl.Attribute)
+	})
+
+	t.Run("Success: unchanged", func(t *testing.T) {
+		var events []TableEvent
+		dynamo.OnTableEvent(func(event TableEvent) { events = append(events, event) })
+
+		assert.NoError(t, dynamo.ApplyTableSpec(ctx, spec))
+		assert.Empty(t, events)
+	})
+
+	t.Run("Success: converge", func(t *testing.T) {
+		spec := spec
+		spec.GSIs = []IndexDefinition{
+			{Name: "Status-index", HashKey: "Status", HashKeyType: DynamodbKeyTypeNumber},
+		}
+		spec.Stream = DynamodbStreamKeysOnly
+
+		assert.NoError(t, dynamo.ApplyTableSpec(ctx, spec))
+
+		desc, err := dynamo.DescribeTable(spec.Name)
+		assert.NoError(t, err)
+		assert.Len(t, desc.GSIs, 1)
+		assert.NotEmpty(t, desc.StreamARN)
+	})
+
+	t.Run("Failure: keys changed", func(t *testing.T) {
+		spec := spec
+		spec.RangeKey = "Name"
+
+		err := dynamo.ApplyTableSpec(ctx, spec)
+		assert.True(t, errors.Is(err, ErrSpecMismatch))
+	})
+}
+
+func TestCreateTableFromSchema(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "schema-" + faker.UUIDDigit()
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	spec := TableSpec{
+		HashKey:  "ID",
+		OnDemand: true,
+		GSIs: []IndexDefinition{{
+			Name:             "Status-index",
+			HashKey:          "Status",
+			HashKeyType:      DynamodbKeyTypeNumber,
+			Projection:       DynamodbProjectionInclude,
+			NonKeyAttributes: []string{"Name"},
+		}},
+		TTLAttribute: "ExpiresAt",
+		Stream:       DynamodbStreamNewImage,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, dynamo.CreateTableFromSchema(ctx, name, spec))
+
+		described, err := dynamo.DescribeTableSpec(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, spec.GSIs[0].NonKeyAttributes, described.GSIs[0].NonKeyAttributes)
+		assert.Equal(t, spec.Stream, described.Stream)
+		assert.Equal(t, spec.TTLAttribute, described.TTLAttribute)
+	})
+
+	t.Run("Failure: exists", func(t *testing.T) {
+		assert.Error(t, dynamo.CreateTableFromSchema(ctx, name, spec))
+	})
+
+	t.Run("Failure: no hash key", func(t *testing.T) {
+		assert.Error(t, dynamo.CreateTableFromSchema(ctx, "schema-"+faker.UUIDDigit(), TableSpec{}))
+	})
+}
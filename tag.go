@@ -0,0 +1,46 @@
+package dynamodb
+
+import (
+	"reflect"
+	"strings"
+)
+
+type taggedField struct {
+	name  string
+	value reflect.Value
+}
+
+// taggedFields finds the fields of struct rv carrying option in their dynamo tag,
+// e.g. `dynamo:"Version,version"`. Embedded structs are searched like dynamo flattens them.
+func taggedFields(rv reflect.Value, option string) []taggedField {
+	rv = reflect.Indirect(rv)
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []taggedField{}
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		fv := rv.Field(i)
+
+		if field.Anonymous && reflect.Indirect(fv).Kind() == reflect.Struct {
+			fields = append(fields, taggedFields(fv, option)...)
+			continue
+		}
+
+		tags := strings.Split(field.Tag.Get("dynamo"), ",")
+		for _, t := range tags[1:] {
+			if t != option {
+				continue
+			}
+
+			name := tags[0]
+			if len(name) < 1 {
+				name = field.Name
+			}
+			fields = append(fields, taggedField{name: name, value: fv})
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,218 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// ArchiveQuery selects the archived items of one hash key within a range of the range key.
+type ArchiveQuery struct {
+	Table     string
+	HashKey   string
+	HashValue interface{}
+	RangeKey  string
+	// From is inclusive. To is inclusive with IncludeTo and exclusive otherwise.
+	From, To  interface{}
+	IncludeTo bool
+}
+
+// ArchiveReader reads items that have left the table, such as exports queried with Athena or
+// parquet files. The iterator must yield the items in ascending order of the range key.
+type ArchiveReader interface {
+	Query(ctx context.Context, query ArchiveQuery) DynamodbIter
+}
+
+// TieredOptions :
+type TieredOptions struct {
+	// LiveFor is how long items stay in the table, such as its TTL. Older ranges are read from the archive.
+	LiveFor time.Duration
+	// RangeValue converts a time to a value of the range key. Defaults to an RFC 3339 string in UTC.
+	RangeValue func(t time.Time) interface{}
+	// Clock defaults to the system clock.
+	Clock Clock
+}
+
+// TieredTable reads time ranges across a table holding recent items and an archive of the older ones.
+type TieredTable struct {
+	db        Dynamodb
+	tableName string
+	archive   ArchiveReader
+	opts      TieredOptions
+}
+
+// NewTieredTable :
+func NewTieredTable(db Dynamodb, tableName string, archive ArchiveReader, opts TieredOptions) *TieredTable {
+	if opts.RangeValue == nil {
+		opts.RangeValue = func(t time.Time) interface{} { return t.UTC().Format(time.RFC3339) }
+	}
+	if opts.Clock == nil {
+		opts.Clock = systemClock{}
+	}
+	return &TieredTable{db: db, tableName: tableName, archive: archive, opts: opts}
+}
+
+// QueryRange iterates in ascending order over the items of hashValue whose range key lies between from and to,
+// both inclusive. The part older than LiveFor is read from the archive, the rest from the table.
+func (t *TieredTable) QueryRange(ctx context.Context, hashKey string, hashValue interface{}, rangeKey string, from, to time.Time) DynamodbIter {
+	cutoff := t.opts.Clock.Now().Add(-t.opts.LiveFor)
+
+	var iters []DynamodbIter
+	if from.Before(cutoff) {
+		query := ArchiveQuery{
+			Table:     t.tableName,
+			HashKey:   hashKey,
+			HashValue: hashValue,
+			RangeKey:  rangeKey,
+			From:      t.opts.RangeValue(from),
+			To:        t.opts.RangeValue(cutoff),
+		}
+		if to.Before(cutoff) {
+			query.To, query.IncludeTo = t.opts.RangeValue(to), true
+		}
+		iters = append(iters, t.archive.Query(ctx, query))
+	}
+	if !to.Before(cutoff) {
+		if from.Before(cutoff) {
+			from = cutoff
+		}
+		iters = append(iters, t.db.Query(t.tableName).
+			Hash(hashKey, hashValue).
+			Range(rangeKey, DynamodbBetween, t.opts.RangeValue(from), t.opts.RangeValue(to)).
+			Iter())
+	}
+	return &chainIter{iters: iters}
+}
+
+// chainIter iterates over several iterators one after the other.
+type chainIter struct {
+	iters []DynamodbIter
+	err   error
+}
+
+func (i *chainIter) Next(ctx context.Context, out interface{}) bool {
+	for len(i.iters) > 0 && i.err == nil {
+		if i.iters[0].Next(ctx, out) {
+			return true
+		}
+		i.err = i.iters[0].Err()
+		i.iters = i.iters[1:]
+	}
+	return false
+}
+
+func (i *chainIter) Err() error {
+	return i.err
+}
+
+// ExportArchive is an ArchiveReader over files written by ExportTable, such as exports copied to S3.
+// Every query reads the whole export, so it suits small archives and tools rather than hot paths.
+type ExportArchive struct {
+	Format ExportFormat
+	// Open returns the export of a table.
+	Open func(ctx context.Context, tableName string) (io.ReadCloser, error)
+}
+
+// Query :
+func (a ExportArchive) Query(ctx context.Context, query ArchiveQuery) DynamodbIter {
+	items, err := a.query(ctx, query)
+	if err != nil {
+		return &errIter{err: err}
+	}
+	return &itemsIter{items: items}
+}
+
+func (a ExportArchive) query(ctx context.Context, query ArchiveQuery) ([]map[string]*awsDynamodb.AttributeValue, error) {
+	hash, err := dynamo.Marshal(query.HashValue)
+	if err != nil {
+		return nil, err
+	}
+	from, err := dynamo.Marshal(query.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := dynamo.Marshal(query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := a.Open(ctx, query.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	items := []map[string]*awsDynamodb.AttributeValue{}
+	next := jsonLineReader(r, a.Format)
+	for {
+		item, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rng := item[query.RangeKey]
+		if compareKeys(item[query.HashKey], hash) != 0 || rng == nil || compareKeys(rng, from) < 0 {
+			continue
+		}
+		if c := compareKeys(rng, to); c > 0 || c == 0 && !query.IncludeTo {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return compareKeys(items[i][query.RangeKey], items[j][query.RangeKey]) < 0
+	})
+	return items, nil
+}
+
+// compareKeys orders key values like DynamoDB: strings and binaries by bytes, numbers numerically.
+// Values of different types, or nil, compare as unequal.
+func compareKeys(a, b *awsDynamodb.AttributeValue) int {
+	switch {
+	case a == nil || b == nil:
+	case a.S != nil && b.S != nil:
+		return bytes.Compare([]byte(*a.S), []byte(*b.S))
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B)
+	case a.N != nil && b.N != nil:
+		x, okA := new(big.Float).SetString(*a.N)
+		y, okB := new(big.Float).SetString(*b.N)
+		if okA && okB {
+			return x.Cmp(y)
+		}
+	}
+	return -2
+}
+
+// itemsIter iterates over items read ahead.
+type itemsIter struct {
+	items []map[string]*awsDynamodb.AttributeValue
+	err   error
+}
+
+func (i *itemsIter) Next(ctx context.Context, out interface{}) bool {
+	if len(i.items) < 1 || i.err != nil {
+		return false
+	}
+	if i.err = ctx.Err(); i.err != nil {
+		return false
+	}
+
+	i.err = dynamo.UnmarshalItem(i.items[0], out)
+	i.items = i.items[1:]
+	return i.err == nil
+}
+
+func (i *itemsIter) Err() error {
+	return i.err
+}
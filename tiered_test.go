@@ -0,0 +1,76 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTieredTable(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	name := "tiered-" + faker.UUIDDigit()
+	assert.NoError(t, dynamo.CreateTableWithContext(ctx, name, HashAndRange{}, CreateTableOptions{OnDemand: true, Wait: true}))
+	defer dynamo.DeleteTableWithContext(ctx, name)
+
+	now := time.Date(2021, 8, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+
+	// items older than a day are exported, then expire from the table
+	hashKey := faker.UUIDDigit()
+	old := []HashAndRange{{Id: hashKey, CreatedAt: at(-72 * time.Hour)}, {Id: hashKey, CreatedAt: at(-48 * time.Hour)}}
+	_, err := dynamo.BulkPut(ctx, name, old, BulkPutOptions{})
+	assert.NoError(t, err)
+
+	var export bytes.Buffer
+	_, err = dynamo.ExportTable(ctx, name, &export, ExportJSONLines)
+	assert.NoError(t, err)
+	for _, item := range old {
+		_, err := dynamo.Delete(name, DynamodbKey{
+			Hash:  func() (string, interface{}) { return "ID", item.Id },
+			Range: func() (string, interface{}, *DynamodbOptions) { return "CreatedAt", item.CreatedAt, nil },
+		})
+		assert.NoError(t, err)
+	}
+
+	recent := []HashAndRange{{Id: hashKey, CreatedAt: at(-time.Hour)}, {Id: hashKey, CreatedAt: at(0)}}
+	_, err = dynamo.BulkPut(ctx, name, recent, BulkPutOptions{})
+	assert.NoError(t, err)
+
+	tiered := NewTieredTable(dynamo, name, ExportArchive{
+		Format: ExportJSONLines,
+		Open: func(ctx context.Context, tableName string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(export.Bytes())), nil
+		},
+	}, TieredOptions{LiveFor: 24 * time.Hour, Clock: NewManualClock(now)})
+
+	read := func(from, to time.Time) []HashAndRange {
+		iter := tiered.QueryRange(ctx, "ID", hashKey, "CreatedAt", from, to)
+		items := []HashAndRange{}
+		var item HashAndRange
+		for iter.Next(ctx, &item) {
+			items = append(items, item)
+		}
+		assert.NoError(t, iter.Err())
+		return items
+	}
+
+	t.Run("Success: across tiers", func(t *testing.T) {
+		assert.Equal(t, append(append([]HashAndRange{}, old...), recent...), read(now.Add(-96*time.Hour), now))
+	})
+
+	t.Run("Success: archive only", func(t *testing.T) {
+		assert.Equal(t, old[:1], read(now.Add(-96*time.Hour), now.Add(-72*time.Hour)))
+	})
+
+	t.Run("Success: live only", func(t *testing.T) {
+		assert.Equal(t, recent, read(now.Add(-2*time.Hour), now))
+	})
+}
@@ -0,0 +1,216 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// MaxTransactItems is the number of operations DynamoDB accepts per TransactWriteItems.
+const MaxTransactItems = 100
+
+// ErrTransactionTooLarge is returned when operations that must commit together exceed MaxTransactItems.
+var ErrTransactionTooLarge = errors.New("transaction too large")
+
+// TransactOp is one operation of a transactional write: a put of Put, or else a delete of Delete.
+type TransactOp struct {
+	Table  string
+	Put    interface{}
+	Delete *DynamodbKey
+	// Conditions must all hold, or the whole transaction is canceled.
+	Conditions []ScanFilter
+}
+
+// TransactWrite applies up to MaxTransactItems operations atomically. Puts are written like Put:
+// with an IDGenerator, an empty hash key is generated and set on a pointer item, and with
+// OptimisticLock, a failed version check of a versioned item cancels the transaction with ErrVersionConflict.
+func (con *dynamodb) TransactWrite(ctx context.Context, ops []TransactOp) error {
+	if len(ops) > MaxTransactItems {
+		return fmt.Errorf("%w: %d operations", ErrTransactionTooLarge, len(ops))
+	}
+
+	tx := con.db.WriteTx()
+	locks := make([]*versionLock, len(ops))
+	checks := make([]*versionCheck, len(ops))
+	for i, op := range ops {
+		table := con.table(op.Table)
+		switch {
+		case op.Put != nil:
+			put, _, err := con.generateID(op.Put)
+			if err != nil {
+				return con.transactResult(ctx, locks, checks, err)
+			}
+			if locks[i], err = con.lockVersion(put); err != nil {
+				return con.transactResult(ctx, locks, checks, err)
+			}
+			item, err := con.encodePut(op.Table, put)
+			if err != nil {
+				return con.transactResult(ctx, locks, checks, err)
+			}
+			if locks[i] != nil && len(op.Conditions) > 0 {
+				if checks[i], err = con.newVersionCheck(op.Table, item); err != nil {
+					return con.transactResult(ctx, locks, checks, err)
+				}
+			}
+			req := locks[i].apply(table.Put(item))
+			for _, c := range op.Conditions {
+				req.If(c.Expr, c.args()...)
+			}
+			tx.Put(req)
+		case op.Delete != nil:
+			req := deleteItem(table, *op.Delete)
+			for _, c := range op.Conditions {
+				req.If(c.Expr, c.args()...)
+			}
+			tx.Delete(req)
+		default:
+			return con.transactResult(ctx, locks, checks, errors.New("transact operation has neither Put nor Delete"))
+		}
+	}
+	return con.transactResult(ctx, locks, checks, tx.RunWithContext(ctx))
+}
+
+// transactResult restores the previous versions of locks, indexed like the operations, when the
+// transaction failed. A failed condition of a versioned put is reported as ErrVersionConflict when
+// it was the version check: always for a put without Conditions, and for the others when the stored
+// version, read again through checks, is not the expected one.
+func (con *dynamodb) transactResult(ctx context.Context, locks []*versionLock, checks []*versionCheck, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var canceled *awsDynamodb.TransactionCanceledException
+	errors.As(err, &canceled)
+	conflict := false
+	for i, lock := range locks {
+		if lock == nil {
+			continue
+		}
+		lock.set(lock.version)
+		if canceled == nil || i >= len(canceled.CancellationReasons) ||
+			aws.StringValue(canceled.CancellationReasons[i].Code) != awsDynamodb.BatchStatementErrorCodeEnumConditionalCheckFailed {
+			continue
+		}
+		if checks[i] == nil || con.versionChanged(ctx, checks[i], lock) {
+			conflict = true
+		}
+	}
+	if conflict {
+		return fmt.Errorf("%w: %v", ErrVersionConflict, err)
+	}
+	return err
+}
+
+// versionCheck locates the item of a versioned put with Conditions, which is read again when the
+// put fails its condition to learn whether the version check failed.
+type versionCheck struct {
+	table string
+	key   map[string]*awsDynamodb.AttributeValue
+}
+
+func (con *dynamodb) newVersionCheck(tableName string, item interface{}) (*versionCheck, error) {
+	av, ok := item.(map[string]*awsDynamodb.AttributeValue)
+	if !ok {
+		var err error
+		if av, err = dynamo.MarshalItem(item); err != nil {
+			return nil, err
+		}
+	}
+	desc, err := con.describeCached(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := map[string]*awsDynamodb.AttributeValue{desc.HashKey: av[desc.HashKey]}
+	if len(desc.RangeKey) > 0 {
+		key[desc.RangeKey] = av[desc.RangeKey]
+	}
+	return &versionCheck{table: tableName, key: key}, nil
+}
+
+// versionChanged reports whether the stored version of the item of check is not the version of lock,
+// which failed the version check. An item that cannot be read counts as changed.
+func (con *dynamodb) versionChanged(ctx context.Context, check *versionCheck, lock *versionLock) bool {
+	out, err := con.db.Client().GetItemWithContext(ctx, &awsDynamodb.GetItemInput{
+		TableName:                aws.String(con.tableName(check.table)),
+		Key:                      check.key,
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     aws.String("#v"),
+		ExpressionAttributeNames: map[string]*string{"#v": aws.String(lock.name)},
+	})
+	if err != nil {
+		return true
+	}
+
+	stored := out.Item[lock.name]
+	if stored == nil || stored.N == nil {
+		return lock.version != 0
+	}
+	return aws.StringValue(stored.N) != strconv.FormatInt(lock.version, 10)
+}
+
+// TransactWriteSplit applies any number of operations in several transactions. Operations for which
+// boundary returns the same value always commit in the same transaction; a nil boundary puts every
+// operation on its own. Batches are formed in order and run one after the other, so a failure leaves
+// the earlier batches committed; committed is their number. Nothing is written when a boundary
+// holds more than MaxTransactItems operations.
+func (con *dynamodb) TransactWriteSplit(ctx context.Context, ops []TransactOp, boundary func(op TransactOp) string) (committed int, err error) {
+	batches, err := splitTransact(ops, boundary)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, batch := range batches {
+		if err := con.TransactWrite(ctx, batch); err != nil {
+			return committed, err
+		}
+		committed++
+	}
+	return committed, nil
+}
+
+func splitTransact(ops []TransactOp, boundary func(op TransactOp) string) ([][]TransactOp, error) {
+	var groups [][]TransactOp
+	if boundary == nil {
+		for _, op := range ops {
+			groups = append(groups, []TransactOp{op})
+		}
+	} else {
+		index := map[string]int{}
+		for _, op := range ops {
+			b := boundary(op)
+			i, ok := index[b]
+			if !ok {
+				i = len(groups)
+				index[b] = i
+				groups = append(groups, nil)
+			}
+			groups[i] = append(groups[i], op)
+		}
+
+		for b, i := range index {
+			if n := len(groups[i]); n > MaxTransactItems {
+				return nil, fmt.Errorf("%w: boundary %q has %d operations", ErrTransactionTooLarge, b, n)
+			}
+		}
+	}
+
+	var batches [][]TransactOp
+	var batch []TransactOp
+	for _, group := range groups {
+		if len(batch)+len(group) > MaxTransactItems {
+			batches = append(batches, batch)
+			batch = nil
+		}
+		batch = append(batch, group...)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
@@ -0,0 +1,140 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTransact(t *testing.T) {
+	ops := func(n int, table string) []TransactOp {
+		ops := make([]TransactOp, n)
+		for i := range ops {
+			ops[i] = TransactOp{Table: table, Put: HashOnly{Id: strconv.Itoa(i)}}
+		}
+		return ops
+	}
+	byTable := func(op TransactOp) string { return op.Table }
+
+	t.Run("Success", func(t *testing.T) {
+		all := append(append(ops(60, "a"), ops(60, "b")...), ops(30, "c")...)
+		batches, err := splitTransact(all, byTable)
+		assert.NoError(t, err)
+		if assert.Len(t, batches, 2) {
+			assert.Len(t, batches[0], 60)
+			assert.Len(t, batches[1], 90)
+		}
+
+		batches, err = splitTransact(ops(250, "a"), nil)
+		assert.NoError(t, err)
+		assert.Len(t, batches, 3)
+	})
+
+	t.Run("Failure: boundary too large", func(t *testing.T) {
+		_, err := splitTransact(ops(101, "a"), byTable)
+		assert.True(t, errors.Is(err, ErrTransactionTooLarge))
+	})
+}
+
+func TestTransactWriteSplit(t *testing.T) {
+	dynamo := newDynamo(t)
+	ctx := context.Background()
+
+	id := faker.UUIDDigit()
+	ops := make([]TransactOp, 150)
+	for i := range ops {
+		ops[i] = TransactOp{Table: tableNameHashAndRange, Put: HashAndRange{Id: id, CreatedAt: strconv.Itoa(i)}}
+	}
+
+	committed, err := dynamo.TransactWriteSplit(ctx, ops, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, committed)
+
+	count, err := dynamo.Count(tableNameHashAndRange, DynamodbKey{
+		Hash: func() (string, interface{}) { return "ID", id },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), count)
+
+	t.Run("Failure: condition", func(t *testing.T) {
+		err := dynamo.TransactWrite(ctx, []TransactOp{
+			{Table: tableNameHashAndRange, Put: HashAndRange{Id: id, CreatedAt: "new"}},
+			{Table: tableNameHashAndRange, Put: HashAndRange{Id: id, CreatedAt: "0"}, Conditions: []ScanFilter{Filter("attribute_not_exists($)", "ID")}},
+		})
+		assert.Error(t, err)
+
+		var item HashAndRange
+		err = dynamo.Get(tableNameHashAndRange, DynamodbKey{
+			Hash:  func() (string, interface{}) { return "ID", id },
+			Range: func() (string, interface{}, *DynamodbOptions) { return "CreatedAt", "new", nil },
+		}, &item)
+		assert.Error(t, err)
+	})
+}
+
+func TestTransactWriteVersioned(t *testing.T) {
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{OptimisticLock: true, IDGenerator: UUIDGenerator{}})
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		item := TaggedVersion{Name: faker.Name()}
+		assert.NoError(t, dynamo.TransactWrite(ctx, []TransactOp{{Table: tableNameHashOnly, Put: &item}}))
+		assert.NotEmpty(t, item.Id)
+		assert.Equal(t, 1, item.Version)
+
+		var got TaggedVersion
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", item.Id },
+		}, &got))
+		assert.Equal(t, item, got)
+	})
+
+	t.Run("Failure: version conflict", func(t *testing.T) {
+		item := TaggedVersion{Id: faker.UUIDDigit(), Name: faker.Name()}
+		stale := item
+		_, err := dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+
+		other := TaggedVersion{Id: faker.UUIDDigit()}
+		err = dynamo.TransactWrite(ctx, []TransactOp{
+			{Table: tableNameHashOnly, Put: &other},
+			{Table: tableNameHashOnly, Put: &stale},
+		})
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+		assert.Equal(t, 0, stale.Version)
+		assert.Equal(t, 0, other.Version)
+	})
+
+	t.Run("Failure: condition of the caller", func(t *testing.T) {
+		item := TaggedVersion{Id: faker.UUIDDigit(), Name: faker.Name()}
+		_, err := dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+
+		err = dynamo.TransactWrite(ctx, []TransactOp{{
+			Table:      tableNameHashOnly,
+			Put:        &item,
+			Conditions: []ScanFilter{{Expr: "'Name' = ?", Value: "other"}},
+		}})
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrVersionConflict))
+		assert.Equal(t, 1, item.Version)
+	})
+
+	t.Run("Failure: version conflict with conditions", func(t *testing.T) {
+		item := TaggedVersion{Id: faker.UUIDDigit(), Name: faker.Name()}
+		stale := item
+		_, err := dynamo.Put(tableNameHashOnly, &item)
+		assert.NoError(t, err)
+
+		err = dynamo.TransactWrite(ctx, []TransactOp{{
+			Table:      tableNameHashOnly,
+			Put:        &stale,
+			Conditions: []ScanFilter{{Expr: "'Name' = ?", Value: item.Name}},
+		}})
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+	})
+}
@@ -0,0 +1,129 @@
+package dynamodb
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsDynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// TTLStatus :
+type TTLStatus string
+
+// TTL status
+const (
+	TTLStatusEnabled   TTLStatus = "ENABLED"
+	TTLStatusEnabling  TTLStatus = "ENABLING"
+	TTLStatusDisabled  TTLStatus = "DISABLED"
+	TTLStatusDisabling TTLStatus = "DISABLING"
+)
+
+// TTLDescription :
+type TTLDescription struct {
+	// Attribute is empty while TTL is disabled.
+	Attribute string
+	Status    TTLStatus
+}
+
+// Enabled :
+func (d *TTLDescription) Enabled() bool {
+	return d.Status == TTLStatusEnabled
+}
+
+// ExpiresAt converts t to the Unix seconds DynamoDB expects in a TTL attribute.
+// A time.Time field tagged `dynamo:"ExpiresAt,unixtime"` is stored the same way.
+func ExpiresAt(t time.Time) int64 {
+	return t.Unix()
+}
+
+// ExpiresIn is ExpiresAt for d from now on the clock of the client.
+func (con *dynamodb) ExpiresIn(d time.Duration) int64 {
+	return ExpiresAt(con.clock().Now().Add(d))
+}
+
+// EnableTTL makes DynamoDB delete items once the time in attributeName has passed.
+// Deletion typically happens within 48 hours, so expired items may still be read.
+func (con *dynamodb) EnableTTL(tableName, attributeName string) error {
+	if err := con.table(tableName).UpdateTTL(attributeName, true).Run(); err != nil {
+		return err
+	}
+
+	con.emit(TableEvent{Type: TTLChanged, Table: tableName, Attribute: attributeName})
+	return nil
+}
+
+func (con *dynamodb) DescribeTTL(tableName string) (*TTLDescription, error) {
+	desc, err := con.table(tableName).DescribeTTL().Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TTLDescription{
+		Attribute: desc.Attribute,
+		Status:    TTLStatus(desc.Status),
+	}, nil
+}
+
+// TTLPolicy sets the TTL attribute of every item put without one, for cache-like and session-like tables.
+type TTLPolicy struct {
+	// Attribute is the TTL attribute of the table, as passed to EnableTTL.
+	Attribute string
+	// Duration the items live from the time of the put.
+	Duration time.Duration
+}
+
+// encodePut is encodeItem applying the TTL policy of the table. The TTL attribute counts as set
+// unless it is missing, null or not after the Unix epoch, like the zero value of an int64 field.
+func (con *dynamodb) encodePut(tableName string, item interface{}) (interface{}, error) {
+	put, err := con.encodeItem(tableName, item)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, ok := con.ttlPolicy(tableName)
+	if !ok {
+		return put, nil
+	}
+
+	av, ok := put.(map[string]*awsDynamodb.AttributeValue)
+	if !ok {
+		if av, err = dynamo.MarshalItem(put); err != nil {
+			return nil, err
+		}
+	}
+	if ttlSet(av[policy.Attribute]) {
+		return put, nil
+	}
+
+	// copy so that a map passed by the caller is left alone
+	withTTL := make(map[string]*awsDynamodb.AttributeValue, len(av)+1)
+	for k, v := range av {
+		withTTL[k] = v
+	}
+	con.setTTL(withTTL, policy)
+	return withTTL, nil
+}
+
+func (con *dynamodb) ttlPolicy(tableName string) (TTLPolicy, bool) {
+	policy, ok := con.config.TTLPolicies[tableName]
+	return policy, ok && len(policy.Attribute) > 0 && policy.Duration > 0
+}
+
+func (con *dynamodb) setTTL(av map[string]*awsDynamodb.AttributeValue, policy TTLPolicy) {
+	av[policy.Attribute] = &awsDynamodb.AttributeValue{
+		N: aws.String(strconv.FormatInt(con.ExpiresIn(policy.Duration), 10)),
+	}
+}
+
+func ttlSet(av *awsDynamodb.AttributeValue) bool {
+	if av == nil || av.NULL != nil {
+		return false
+	}
+	if av.N == nil {
+		return true
+	}
+	n, err := strconv.ParseFloat(*av.N, 64)
+	return err != nil || n > 0
+}
@@ -0,0 +1,102 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type WithTTL struct {
+	Id        string    `dynamo:"ID,hash"`
+	ExpiresAt time.Time `dynamo:"ExpiresAt,unixtime"`
+}
+
+func TestTTL(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	name := "with-ttl-" + faker.UUIDDigit()
+	if err := dynamo.CreateTableWithOptions(name, WithTTL{}, CreateTableOptions{Wait: true}); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		dynamo.DeleteTable(name)
+		dynamo.WaitUntilTableDeleted(context.Background(), name)
+	}()
+
+	desc, err := dynamo.DescribeTTL(name)
+	assert.NoError(t, err)
+	assert.False(t, desc.Enabled())
+
+	assert.NoError(t, dynamo.EnableTTL(name, "ExpiresAt"))
+
+	desc, err = dynamo.DescribeTTL(name)
+	assert.NoError(t, err)
+	assert.True(t, desc.Enabled())
+	assert.Equal(t, "ExpiresAt", desc.Attribute)
+
+	t.Run("ExpiresAt", func(t *testing.T) {
+		expires := time.Now().Add(time.Hour)
+		item := WithTTL{Id: faker.UUIDDigit(), ExpiresAt: expires}
+		_, err := dynamo.Put(name, &item)
+		assert.NoError(t, err)
+
+		var count int64
+		count, err = dynamo.Count(name, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", item.Id },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		var items []WithTTL
+		err = dynamo.Scan(name, &items, ScanFilter{Expr: "ExpiresAt = ?", Value: ExpiresAt(expires)})
+		assert.NoError(t, err)
+		assert.Len(t, items, 1)
+	})
+}
+
+func TestTTLPolicy(t *testing.T) {
+	name := "ttl-policy-" + faker.UUIDDigit()
+	clock := NewManualClock(time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC))
+	dynamo := newDynamoWithConfig(t, &DynamodbConfig{
+		Clock:       clock,
+		TTLPolicies: map[string]TTLPolicy{name: {Attribute: "ExpiresAt", Duration: time.Hour}},
+	})
+	if err := dynamo.CreateTableWithOptions(name, WithTTL{}, CreateTableOptions{OnDemand: true, Wait: true}); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer dynamo.DeleteTableWithContext(context.Background(), name)
+
+	get := func(id string) WithTTL {
+		var got WithTTL
+		assert.NoError(t, dynamo.Get(name, DynamodbKey{
+			Hash: func() (string, interface{}) { return "ID", id },
+		}, &got))
+		return got
+	}
+
+	t.Run("Success: default", func(t *testing.T) {
+		item := WithTTL{Id: faker.UUIDDigit()}
+		_, err := dynamo.Put(name, item)
+		assert.NoError(t, err)
+		assert.Equal(t, clock.Now().Add(time.Hour).Unix(), get(item.Id).ExpiresAt.Unix())
+	})
+
+	t.Run("Success: already set", func(t *testing.T) {
+		item := WithTTL{Id: faker.UUIDDigit(), ExpiresAt: clock.Now().Add(time.Minute)}
+		_, err := dynamo.Put(name, item)
+		assert.NoError(t, err)
+		assert.Equal(t, item.ExpiresAt.Unix(), get(item.Id).ExpiresAt.Unix())
+	})
+
+	t.Run("Success: bulk", func(t *testing.T) {
+		items := []WithTTL{{Id: faker.UUIDDigit()}, {Id: faker.UUIDDigit()}}
+		_, err := dynamo.BulkPut(context.Background(), name, items, BulkPutOptions{})
+		assert.NoError(t, err)
+		for _, item := range items {
+			assert.Equal(t, clock.Now().Add(time.Hour).Unix(), get(item.Id).ExpiresAt.Unix())
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package dynamodb
+
+import "errors"
+
+// Errors returned when the condition of a write fails.
+var (
+	ErrItemExists   = errors.New("item already exists")
+	ErrItemNotFound = errors.New("item not found")
+	// ErrConditionFailed is returned when the conditions of a conditional write do not hold.
+	ErrConditionFailed = errors.New("condition failed")
+)
+
+// WriteMode decides whether a put may create or replace an item.
+type WriteMode int
+
+// Write modes
+const (
+	// WriteUpsert creates or replaces the item, like Put.
+	WriteUpsert WriteMode = iota
+	// WriteInsertOnly fails with ErrItemExists when the item exists.
+	WriteInsertOnly
+	// WriteUpdateOnly fails with ErrItemNotFound when the item is missing.
+	WriteUpdateOnly
+)
+
+// PutIfNotExists is Put in WriteInsertOnly mode.
+func (con *dynamodb) PutIfNotExists(tableName string, item interface{}) (*DynamodbResponse, error) {
+	return con.PutWithMode(tableName, item, WriteInsertOnly)
+}
+
+// UpdateOnly is Put in WriteUpdateOnly mode.
+func (con *dynamodb) UpdateOnly(tableName string, item interface{}) (*DynamodbResponse, error) {
+	return con.PutWithMode(tableName, item, WriteUpdateOnly)
+}
+
+// PutWithMode writes item according to mode. The hash key of the table is read from its description.
+// With an IDGenerator, an empty hash key is generated and set on item when it is a pointer.
+// With OptimisticLock, a versioned item reports any failed condition as ErrVersionConflict.
+func (con *dynamodb) PutWithMode(tableName string, item interface{}, mode WriteMode) (*DynamodbResponse, error) {
+	var cond string
+	switch mode {
+	case WriteUpsert:
+	case WriteInsertOnly:
+		cond = "attribute_not_exists($)"
+	case WriteUpdateOnly:
+		cond = "attribute_exists($)"
+	default:
+		return &DynamodbResponse{}, errors.New("unknown write mode")
+	}
+
+	var hashKey string
+	if len(cond) > 0 {
+		desc, err := con.describeCached(tableName)
+		if err != nil {
+			return &DynamodbResponse{}, err
+		}
+		hashKey = desc.HashKey
+	}
+
+	item, id, err := con.generateID(item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	lock, err := con.lockVersion(item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+	put, err := con.encodePut(tableName, item)
+	if err != nil {
+		return &DynamodbResponse{}, err
+	}
+
+	ctx, cancel := callContext()
+	defer cancel()
+
+	cc := con.capacity()
+	req := lock.apply(con.table(tableName).Put(put))
+	if len(cond) > 0 {
+		req.If(cond, hashKey)
+	}
+	err = req.ConsumedCapacity(cc).RunWithContext(ctx)
+	res := &DynamodbResponse{ConsumedCapacity: consumed(cc), GeneratedID: id, RequestIDs: requestIDs(ctx)}
+	if lock == nil && isConditionalCheckFailed(err) {
+		if mode == WriteInsertOnly {
+			return res, ErrItemExists
+		}
+		return res, ErrItemNotFound
+	}
+	if err = lock.result(err); err != nil {
+		return res, err
+	}
+	return res, con.waitIndexes(tableName, item)
+}
@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutWithMode(t *testing.T) {
+	dynamo := newDynamo(t)
+
+	t.Run("PutIfNotExists", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+
+		_, err := dynamo.PutIfNotExists(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		item.Name = "second"
+		_, err = dynamo.PutIfNotExists(tableNameHashOnly, item)
+		assert.ErrorIs(t, err, ErrItemExists)
+	})
+
+	t.Run("UpdateOnly", func(t *testing.T) {
+		item := HashOnly{Id: faker.UUIDDigit(), Name: faker.Name()}
+
+		_, err := dynamo.UpdateOnly(tableNameHashOnly, item)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		_, err = dynamo.Put(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		item.Name = "updated"
+		_, err = dynamo.UpdateOnly(tableNameHashOnly, item)
+		assert.NoError(t, err)
+
+		var datum HashOnly
+		assert.NoError(t, dynamo.Get(tableNameHashOnly, DynamodbKey{
+			Hash: func() (string, interface{}) { return item.HashKey(), item.Id },
+		}, &datum))
+		assert.Equal(t, item, datum)
+	})
+
+	t.Run("Failure: unknown mode", func(t *testing.T) {
+		_, err := dynamo.PutWithMode(tableNameHashOnly, HashOnly{Id: faker.UUIDDigit()}, WriteMode(-1))
+		assert.Error(t, err)
+	})
+}